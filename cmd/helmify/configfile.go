@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile - config file read from the current directory if exists.
+const defaultConfigFile = ".helmify.yaml"
+
+// configFileChartKey - config file key of CHART_NAME argument.
+const configFileChartKey = "chart"
+
+// repeatedFlags - flags set once per list item of config file value. Lists of other flags are comma-separated.
+var repeatedFlags = map[string]bool{"dependency": true, "f": true}
+
+// notConfigurable - flags not allowed in config file.
+var notConfigurable = map[string]bool{"h": true, "help": true, "version": true, "config": true}
+
+// applyConfigFile - sets flags not set in command line from config file with flag names as keys, e.g.:
+//
+//	chart: mychart
+//	crd-dir: true
+//	skip-kind: [Namespace, CustomResourceDefinition]
+//	chart-annotations:
+//	  category: Database
+//
+// Returns chart name set in config file. Missing file is ignored unless required.
+func applyConfigFile(fs *flag.FlagSet, file string, required bool) (string, error) {
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) && !required {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "unable to read config file")
+	}
+	var doc yaml.Node
+	err = yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to parse config file %s", file)
+	}
+	if len(doc.Content) == 0 {
+		return "", nil
+	}
+	options := doc.Content[0]
+	if options.Kind != yaml.MappingNode {
+		return "", errors.Errorf("unable to parse config file %s: must be a map of options", file)
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	var chart string
+	for i := 0; i+1 < len(options.Content); i += 2 {
+		name, value := options.Content[i].Value, options.Content[i+1]
+		if name == configFileChartKey {
+			chart = value.Value
+			continue
+		}
+		if fs.Lookup(name) == nil || notConfigurable[name] {
+			return "", errors.Errorf("unknown option %s in config file %s", name, file)
+		}
+		if set[name] {
+			// command line flags override config file
+			continue
+		}
+		values, err := flagValues(name, value)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid option %s in config file %s", name, file)
+		}
+		for _, v := range values {
+			err = fs.Set(name, v)
+			if err != nil {
+				return "", errors.Wrapf(err, "invalid option %s in config file %s", name, file)
+			}
+		}
+	}
+	return chart, nil
+}
+
+// flagValues - converts config file value to flag values. Scalars are used as written, e.g. version '1.10'.
+// Maps are converted to comma-separated 'key=value' pairs.
+func flagValues(name string, value *yaml.Node) ([]string, error) {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		return []string{value.Value}, nil
+	case yaml.SequenceNode:
+		res := make([]string, len(value.Content))
+		for i, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, errors.New("list items must be scalars")
+			}
+			res[i] = item.Value
+		}
+		if repeatedFlags[name] {
+			return res, nil
+		}
+		return []string{strings.Join(res, ",")}, nil
+	case yaml.MappingNode:
+		res := make([]string, 0, len(value.Content)/2)
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			res = append(res, value.Content[i].Value+"="+value.Content[i+1].Value)
+		}
+		return []string{strings.Join(res, ",")}, nil
+	}
+	return nil, errors.New("value must be a scalar, list or map")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfigFile = `chart: deploy/mychart
+crd-dir: true
+skip-kind: [Namespace, CustomResourceDefinition]
+dependency:
+  - bitnami/postgresql:12.x
+  - bitnami/redis:16.x
+chart-annotations:
+  category: Database
+  licenses: Apache-2.0
+chart-version: 1.10
+files-get-size: 4096
+values-file: values.default.yaml
+`
+
+func Test_applyConfigFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".helmify.yaml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(testConfigFile), 0600))
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	crd := fs.Bool("crd-dir", false, "")
+	skipKinds := fs.String("skip-kind", "", "")
+	annotations := fs.String("chart-annotations", "", "")
+	size := fs.Int("files-get-size", 0, "")
+	version := fs.String("chart-version", "", "")
+	valuesFile := fs.String("values-file", "", "")
+	var deps []string
+	fs.Func("dependency", "", func(s string) error {
+		deps = append(deps, s)
+		return nil
+	})
+	assert.NoError(t, fs.Parse([]string{"-values-file=values.cli.yaml"}))
+
+	chart, err := applyConfigFile(fs, file, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy/mychart", chart)
+	assert.True(t, *crd)
+	assert.Equal(t, "Namespace,CustomResourceDefinition", *skipKinds)
+	assert.Equal(t, "category=Database,licenses=Apache-2.0", *annotations)
+	assert.Equal(t, 4096, *size)
+	assert.Equal(t, "1.10", *version)
+	assert.Equal(t, "values.cli.yaml", *valuesFile, "command line flag overrides config file")
+	assert.Equal(t, []string{"bitnami/postgresql:12.x", "bitnami/redis:16.x"}, deps)
+}
+
+func Test_applyConfigFile_errors(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("crd-dir", false, "")
+
+	chart, err := applyConfigFile(fs, filepath.Join(dir, "missing.yaml"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "", chart)
+	_, err = applyConfigFile(fs, filepath.Join(dir, "missing.yaml"), true)
+	assert.Error(t, err)
+
+	file := filepath.Join(dir, "unknown.yaml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("unknown-option: true\n"), 0600))
+	_, err = applyConfigFile(fs, file, true)
+	assert.Error(t, err)
+
+	file = filepath.Join(dir, "invalid.yaml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("crd-dir: maybe\n"), 0600))
+	_, err = applyConfigFile(fs, file, true)
+	assert.Error(t, err)
+}
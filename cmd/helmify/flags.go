@@ -5,20 +5,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/arttor/helmify/pkg/config"
 )
 
 const helpText = `Helmify parses kubernetes resources from std.in and converts it to a Helm chart.
 
-Example 1: 'kustomize build <kustomize_dir> | helmify mychart' 
+Example 1: 'helmify -from-kustomize <kustomize_dir> mychart' 
   - will create 'mychart' directory with Helm chart from kustomize output.
 
 Example 2: 'cat my-app.yaml | helmify mychart' 
   - will create 'mychart' directory with Helm chart from yaml file.
 
-Example 3: 'awk 'FNR==1 && NR!=1  {print "---"}{print}' /my_directory/*.yaml | helmify mychart' 
-  - will create 'mychart' directory with Helm chart from all yaml files in my_directory directory.
+Example 3: 'helmify -f /my_directory mychart' 
+  - will create 'mychart' directory with Helm chart from all yaml files in my_directory directory and its subdirectories.
 
 Usage:
   helmify [flags] CHART_NAME  -  CHART_NAME is optional. Default is 'chart'. Can be a directory, e.g. 'deploy/charts/mychart'.
@@ -30,12 +31,81 @@ Flags:
 func ReadFlags() config.Config {
 	result := config.Config{}
 	var h, help, version, crd bool
+	var filesGet, crImageFields, rawConfigMaps string
+	var chartKeywords, chartMaintainers, chartAnnotations string
+	var skipKinds, skipNames, skipNamespaces string
+	var configFile string
 	flag.BoolVar(&h, "h", false, "Print help. Example: helmify -h")
 	flag.BoolVar(&help, "help", false, "Print help. Example: helmify -help")
 	flag.BoolVar(&version, "version", false, "Print helmify version. Example: helmify -version")
 	flag.BoolVar(&result.Verbose, "v", false, "Enable verbose output (print WARN & INFO). Example: helmify -v")
 	flag.BoolVar(&result.VeryVerbose, "vv", false, "Enable very verbose output. Same as verbose but with DEBUG. Example: helmify -vv")
+	flag.StringVar(&result.ChartVersion, "chart-version", "", "Set Chart.yaml version. Chart.yaml fields are updated on every run even if the file exists.\nExample: helmify -chart-version=1.2.0")
+	flag.StringVar(&result.ChartAppVersion, "app-version", "", "Set Chart.yaml appVersion.\nExample: helmify -app-version=v2.5.1")
+	flag.StringVar(&result.ChartDescription, "chart-description", "", "Set Chart.yaml description.\nExample: helmify -chart-description='My application'")
+	flag.StringVar(&result.ChartHome, "chart-home", "", "Set Chart.yaml home URL.\nExample: helmify -chart-home=https://example.com")
+	flag.StringVar(&result.ChartIcon, "chart-icon", "", "Set Chart.yaml icon URL.\nExample: helmify -chart-icon=https://example.com/icon.png")
+	flag.StringVar(&chartKeywords, "chart-keywords", "", "Comma-separated list of Chart.yaml keywords.\nExample: helmify -chart-keywords=web,database")
+	flag.StringVar(&chartMaintainers, "chart-maintainers", "", "Comma-separated list of Chart.yaml maintainers in form 'name <email> (url)' with optional email and url.\nExample: helmify -chart-maintainers='Jane Doe <jane@example.com>'")
+	flag.StringVar(&chartAnnotations, "chart-annotations", "", "Comma-separated list of Chart.yaml annotations in form 'key=value'.\nExample: helmify -chart-annotations=category=Database,licenses=Apache-2.0")
+	flag.Func("dependency", "Chart dependency in form '<repository>/<name>:<version>[:<condition>]'. Can be repeated.\nResources with names matching dependency name, e.g. '<app>-postgresql', are not converted. Example: helmify -dependency=bitnami/postgresql:12.x:postgresql.enabled", func(s string) error {
+		dep, err := config.ParseDependency(s)
+		if err != nil {
+			return err
+		}
+		result.Dependencies = append(result.Dependencies, dep)
+		return nil
+	})
 	flag.BoolVar(&crd, "crd-dir", false, "Enable crd install into 'crds' directory.\nWarning: CRDs placed in 'crds' directory will not be templated by Helm.\nSee https://helm.sh/docs/chart_best_practices/custom_resource_definitions/#some-caveats-and-explanations\nExample: helmify -crd-dir")
+	flag.BoolVar(&result.RestartAnnotation, "restart-annotation", false, "Add 'kubectl.kubernetes.io/restartedAt' pod annotation to Deployments and StatefulSets.\nSet '<name>.restartedAt' value to force rollout on upgrade.\nExample: helmify -restart-annotation")
+	flag.BoolVar(&result.ReplicaCount, "replica-count", false, "Use top-level 'replicaCount' value for Deployment or StatefulSet replicas like 'helm create' does.\nIntended for charts with a single workload. Example: helmify -replica-count")
+	flag.StringVar(&result.ValuesFile, "values-file", "", "Write a copy of values.yaml under the given file name in chart directory.\nExample: helmify -values-file=values.default.yaml")
+	flag.StringVar(&filesGet, "files-get", "", "Comma-separated list of ConfigMap data keys in form '<configmap name>/<key>'.\nKey content is moved into chart 'files' dir and read with '.Files.Get'.\nExample: helmify -files-get=my-config/app.conf,my-config/logback.xml")
+	flag.IntVar(&result.FilesGetSize, "files-get-size", 0, "Move ConfigMap data keys larger than the given number of bytes into chart 'files' dir and read them with '.Files.Get'.\nExample: helmify -files-get-size=4096")
+	flag.StringVar(&crImageFields, "cr-image-fields", "", "Comma-separated list of dot-separated field paths moved to values for custom resources.\nOther custom resource fields are kept as is. Example: helmify -cr-image-fields=spec.image,spec.sidecar.image")
+	flag.BoolVar(&result.CRValues, "cr-values", false, "Move scalar spec fields of custom resources to values.\nExample: helmify -cr-values")
+	flag.BoolVar(&result.ConfigMapTypes, "configmap-types", false, "Store numeric and boolean ConfigMap values as typed values instead of quoted strings.\nTemplates still quote them as ConfigMap data must be strings. Example: helmify -configmap-types")
+	flag.BoolVar(&result.JobHooks, "job-hooks", false, "Annotate Jobs as Helm 'pre-install,pre-upgrade' hooks, e.g. for database migrations.\nExample: helmify -job-hooks")
+	flag.BoolVar(&result.SchedulingValues, "scheduling-values", false, "Move workloads nodeSelector, tolerations, affinity, runtimeClassName and schedulerName to values.\nExample: helmify -scheduling-values")
+	flag.BoolVar(&result.EnvValues, "env-values", false, "Move containers env vars with plain values to values and add extraEnv values to append env vars.\nExample: helmify -env-values")
+	flag.BoolVar(&result.CommandValues, "command-values", false, "Move containers command and args to values.\nExample: helmify -command-values")
+	flag.BoolVar(&result.ConfigChecksum, "config-checksum", false, "Add 'checksum/<name>' pod annotations for chart ConfigMaps and Secrets used by workloads to trigger rollout on config change.\nExample: helmify -config-checksum")
+	flag.BoolVar(&result.SecretValues, "secret-values", false, "Use decoded Secret data as default values. Templates encode values with 'b64enc'.\nWarning: secret content is written into values.yaml. Example: helmify -secret-values")
+	flag.StringVar(&result.SecretValuesFile, "secret-values-file", "", "Write decoded Secret data into the given values file in chart directory instead of values.yaml.\nExample: helmify -secret-values-file=secret-values.yaml")
+	flag.BoolVar(&result.ExistingSecrets, "existing-secrets", false, "Add '<name>.existingSecret' values to reference existing Secrets instead of creating chart Secrets.\nExample: helmify -existing-secrets")
+	flag.StringVar(&rawConfigMaps, "raw-configmaps", "", "Comma-separated list of ConfigMap names or '*' for all ConfigMaps.\nData keys are stored in values as is without parsing and rendered with 'tpl'. Example: helmify -raw-configmaps=my-config")
+	flag.BoolVar(&result.ValuesSchema, "values-schema", false, "Generate 'values.schema.json' from types of values. Helm validates user-supplied values with the schema.\nExample: helmify -values-schema")
+	flag.BoolVar(&result.ValuesReadme, "values-readme", false, "Generate chart 'README.md' with a table of values keys, types, defaults and templates using them.\nExample: helmify -values-readme")
+	flag.StringVar(&result.LibraryChart, "library-chart", "", "Create library chart with the given name in chart 'charts' dir with shared helpers: labels, fullname, image and securityContext.\nChart helpers and templates use the library helpers. Example: helmify -library-chart=common")
+	flag.BoolVar(&result.CIValues, "ci-values", false, "Generate 'ci/default-values.yaml' with placeholders for required values, e.g. Secret data, so chart-testing 'ct install' works.\nExample: helmify -ci-values")
+	flag.BoolVar(&result.Lint, "lint", false, "Run 'helm lint' on the created chart and fail on errors. Errors are reported with source resources of templates.\nExample: helmify -lint")
+	flag.BoolVar(&result.Verify, "verify", false, "Render the created chart with default values and fail if source resource fields are lost or changed.\nExample: helmify -verify")
+	flag.BoolVar(&result.Update, "update", false, "Update existing chart keeping templates and values defaults modified since previous run.\nGenerated content is tracked in '.helmify-lock' file. Example: helmify -update")
+	flag.BoolVar(&result.Diff, "diff", false, "Print unified diff of changes to templates, values and other files of the existing chart instead of writing them.\nExample: helmify -diff")
+	flag.BoolVar(&result.Package, "package", false, "Package the created chart into '<chart name>-<version>.tgz' archive like 'helm package' does.\nExample: helmify -package")
+	flag.StringVar(&result.Push, "push", "", "Package the created chart and push it to the given OCI registry repository. Credentials are read from docker config.\nExample: helmify -push=oci://registry.example.com/charts")
+	flag.BoolVar(&result.PushPlainHTTP, "push-plain-http", false, "Push the chart to OCI registry over plain HTTP, e.g. to a local registry.\nExample: helmify -push=oci://localhost:5000/charts -push-plain-http")
+	flag.Func("f", "Input file, directory read recursively, glob pattern with '**' matching any number of directories or http(s) URL.\nCan be repeated. Manifests are read from stdin if not set. Example: helmify -f deploy/ -f 'config/**/*.yaml' -f https://example.com/app.yaml", func(s string) error {
+		result.Files = append(result.Files, s)
+		return nil
+	})
+	flag.BoolVar(&result.SourceFilenames, "source-filenames", false, "Name templates after input files relative to '-f' directories instead of resource names.\nExample: helmify -f deploy/ -source-filenames")
+	flag.StringVar(&result.Kustomize, "from-kustomize", "", "Build the given kustomization directory like 'kubectl kustomize' does and use it as input instead of stdin.\nKustomization namePrefix and nameSuffix are trimmed from template names. Example: helmify -from-kustomize=./overlays/prod mychart")
+	flag.StringVar(&result.Release, "from-release", "", "Read manifests and hooks of the installed Helm release instead of stdin.\nRelease namespace is taken from kube context or HELM_NAMESPACE env var. Example: helmify -from-release=my-release mychart")
+	flag.StringVar(&skipKinds, "skip-kind", "", "Comma-separated list of input resource kinds dropped before conversion.\nExample: helmify -skip-kind=Namespace,CustomResourceDefinition")
+	flag.StringVar(&skipNames, "skip-name", "", "Comma-separated list of input resource names or glob patterns dropped before conversion.\nExample: helmify -skip-name='test-*,debug-pod'")
+	flag.StringVar(&skipNamespaces, "skip-namespace", "", "Comma-separated list of namespaces with input resources dropped before conversion.\nExample: helmify -skip-namespace=kube-system")
+	flag.StringVar(&result.Only, "only", "", "Label selector of input resources converted into the chart. Other resources are dropped.\nExample: helmify -only=app=backend")
+	flag.StringVar(&result.Report, "report", "", "Write JSON report listing every input object with its processor, template file, values keys and warnings.\nExample: helmify -report=helmify-report.json")
+	flag.StringVar(&result.LogFormat, "log-format", "", "Log format: 'text' or 'json'. Default is 'text'.\nExample: helmify -v -log-format=json")
+	flag.StringVar(&result.NamespaceMode, "namespace-mode", "", "Namespace handling: 'keep' source namespaces, 'drop' them from metadata and template references to the app namespace,\nor set every namespace and namespace reference to the release namespace with 'release'. Default is 'drop'. Example: helmify -namespace-mode=release")
+	flag.StringVar(&configFile, "config", defaultConfigFile, "Config file with flags not set in command line. Keys are flag names and 'chart' for CHART_NAME.\nRead from the current directory if exists. Example: helmify -config=deploy/helmify.yaml")
+	flag.BoolVar(&result.DryRun, "dry-run", false, "Print all chart files with '# Source: <path>' headers to stdout instead of writing them.\nExample: helmify -dry-run | less")
+	flag.BoolVar(&result.TestHooks, "test-hooks", false, "Generate 'templates/tests/test-connection.yaml' Pod checking chart Services ports with 'helm test'.\nExample: helmify -test-hooks")
+	flag.BoolVar(&result.Subcharts, "subcharts", false, "Create umbrella chart with a subchart in 'charts' dir for every value of '-subcharts-label'.\nResources without the label are placed into the umbrella chart. Example: helmify -subcharts")
+	flag.StringVar(&result.SubchartsLabel, "subcharts-label", "", "Label grouping resources into subcharts. Default is 'app.kubernetes.io/component'.\nExample: helmify -subcharts -subcharts-label=app.kubernetes.io/part-of")
+	flag.BoolVar(&result.GenWebhookCerts, "gen-webhook-certs", false, "Generate webhook certificates on install with Helm 'genCA' instead of cert-manager. CA is injected into webhooks, CRDs and APIServices caBundle.\nExample: helmify -gen-webhook-certs")
+	flag.BoolVar(&result.NoValues, "no-values", false, "Inline all values into templates and leave values.yaml empty.\nSecret data is still required on install. Example: helmify -no-values")
 	flag.Parse()
 	if h || help {
 		fmt.Print(helpText)
@@ -46,7 +116,19 @@ func ReadFlags() config.Config {
 		printVersion()
 		os.Exit(0)
 	}
+	configRequired := false
+	flag.Visit(func(f *flag.Flag) {
+		configRequired = configRequired || f.Name == "config"
+	})
+	chart, err := applyConfigFile(flag.CommandLine, configFile, configRequired)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	name := flag.Arg(0)
+	if name == "" {
+		name = chart
+	}
 	if name != "" {
 		result.ChartName = filepath.Base(name)
 		result.ChartDir = filepath.Dir(name)
@@ -54,5 +136,40 @@ func ReadFlags() config.Config {
 	if crd {
 		result.Crd = crd
 	}
+	if filesGet != "" {
+		result.FilesGet = strings.Split(filesGet, ",")
+	}
+	if rawConfigMaps != "" {
+		result.RawConfigMaps = strings.Split(rawConfigMaps, ",")
+	}
+	if crImageFields != "" {
+		result.CRImageFields = strings.Split(crImageFields, ",")
+	}
+	if skipKinds != "" {
+		result.SkipKinds = strings.Split(skipKinds, ",")
+	}
+	if skipNames != "" {
+		result.SkipNames = strings.Split(skipNames, ",")
+	}
+	if skipNamespaces != "" {
+		result.SkipNamespaces = strings.Split(skipNamespaces, ",")
+	}
+	if chartKeywords != "" {
+		result.ChartKeywords = strings.Split(chartKeywords, ",")
+	}
+	if chartMaintainers != "" {
+		result.ChartMaintainers = strings.Split(chartMaintainers, ",")
+	}
+	if chartAnnotations != "" {
+		result.ChartAnnotations = map[string]string{}
+		for _, a := range strings.Split(chartAnnotations, ",") {
+			kv := strings.SplitN(a, "=", 2)
+			if len(kv) != 2 {
+				fmt.Fprintf(os.Stderr, "Invalid chart annotation %s: must be in form 'key=value'\n", a)
+				os.Exit(1)
+			}
+			result.ChartAnnotations[kv[0]] = kv[1]
+		}
+	}
 	return result
 }
@@ -9,16 +9,18 @@ import (
 
 func main() {
 	conf := ReadFlags()
-	stat, err := os.Stdin.Stat()
-	if err != nil {
-		logrus.WithError(err).Error("stdin error")
-		os.Exit(1)
-	}
-	if (stat.Mode() & os.ModeCharDevice) != 0 {
-		logrus.Error("no data piped in stdin")
-		os.Exit(1)
+	if len(conf.Files) == 0 && conf.Kustomize == "" && conf.Release == "" {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			logrus.WithError(err).Error("stdin error")
+			os.Exit(1)
+		}
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			logrus.Error("no data piped in stdin")
+			os.Exit(1)
+		}
 	}
-	if err = app.Start(os.Stdin, conf); err != nil {
+	if err := app.Start(os.Stdin, conf); err != nil {
 		logrus.WithError(err).Error("helmify finished with error")
 		os.Exit(1)
 	}
@@ -0,0 +1,48 @@
+// Package helmify converts Kubernetes manifests into Helm charts. It is the library entrypoint of the helmify
+// command: the chart is created in memory and returned to the caller instead of being written to disk.
+package helmify
+
+import (
+	"context"
+	"io"
+
+	"github.com/arttor/helmify/pkg/app"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Options conversion options. Same as command line flags, see config.Config fields. ChartDir is ignored,
+// output options Diff, DryRun, Package, Push and Report are not supported.
+type Options = config.Config
+
+// Chart in-memory Helm chart.
+type Chart struct {
+	// Name chart name from Chart.yaml.
+	Name string
+	// Files content of all chart files by slash-separated path relative to the chart dir, e.g. 'templates/deployment.yaml'.
+	Files map[string][]byte
+	// Values chart default values from values.yaml.
+	Values map[string]interface{}
+}
+
+// Convert reads k8s manifests from input and converts them into a Helm chart. Manifests are read from
+// opts.Files, opts.Kustomize or opts.Release instead if set. Conversion stops when ctx is cancelled.
+func Convert(ctx context.Context, input io.Reader, opts Options) (Chart, error) {
+	files, err := app.Convert(ctx, input, opts)
+	if err != nil {
+		return Chart{}, err
+	}
+	res := Chart{Files: files, Values: map[string]interface{}{}}
+	var chartfile struct {
+		Name string `json:"name"`
+	}
+	if err = yaml.Unmarshal(files["Chart.yaml"], &chartfile); err != nil {
+		return Chart{}, errors.Wrap(err, "unable to read Chart.yaml")
+	}
+	res.Name = chartfile.Name
+	if err = yaml.Unmarshal(files["values.yaml"], &res.Values); err != nil {
+		return Chart{}, errors.Wrap(err, "unable to read values.yaml")
+	}
+	return res, nil
+}
@@ -0,0 +1,32 @@
+package helmify
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	file, err := os.Open("test_data/sample-app.yaml")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	chart, err := Convert(context.Background(), file, Options{ChartName: "sample-app"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sample-app", chart.Name)
+	assert.Contains(t, chart.Files, "Chart.yaml")
+	assert.Contains(t, chart.Files, "values.yaml")
+	assert.Contains(t, chart.Files, "templates/_helpers.tpl")
+	assert.Contains(t, chart.Files, "templates/deployment.yaml")
+	assert.NotEmpty(t, chart.Values)
+
+	_, err = os.Stat("sample-app")
+	assert.True(t, os.IsNotExist(err), "chart must not be written to disk")
+}
+
+func TestConvert_unsupported(t *testing.T) {
+	_, err := Convert(context.Background(), nil, Options{DryRun: true})
+	assert.Error(t, err)
+}
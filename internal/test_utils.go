@@ -1,6 +1,11 @@
 package internal
 
 import (
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 )
@@ -28,3 +33,40 @@ func GenerateObj(objYaml string) *unstructured.Unstructured {
 	}
 	return &obj
 }
+
+const testHelpers = `{{- define "<CHARTNAME>.fullname" -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" }}
+{{- end }}
+{{- define "<CHARTNAME>.selectorLabels" -}}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end }}
+{{- define "<CHARTNAME>.labels" -}}
+{{ include "<CHARTNAME>.selectorLabels" . }}
+{{- end }}
+{{- define "<CHARTNAME>.serviceAccountName" -}}
+{{- if .Values.serviceAccount.create }}
+{{- default (include "<CHARTNAME>.fullname" .) .Values.serviceAccount.name }}
+{{- else }}
+{{- default "default" .Values.serviceAccount.name }}
+{{- end }}
+{{- end }}`
+
+// RenderTemplate renders Helm template with given values. Chart helpers are replaced with simplified versions.
+func RenderTemplate(chartName, tpl string, values map[string]interface{}) (string, error) {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: chartName, Version: "0.1.0", AppVersion: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: []byte(strings.ReplaceAll(testHelpers, "<CHARTNAME>", chartName))},
+			{Name: "templates/test.yaml", Data: []byte(tpl)},
+		},
+	}
+	vals, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	if err != nil {
+		return "", err
+	}
+	out, err := engine.Render(chrt, vals)
+	if err != nil {
+		return "", err
+	}
+	return out[chartName+"/templates/test.yaml"], nil
+}
@@ -2,11 +2,15 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/arttor/helmify/pkg/config"
@@ -17,12 +21,22 @@ import (
 	"github.com/arttor/helmify/pkg/processor/crd"
 	"github.com/arttor/helmify/pkg/processor/daemonset"
 	"github.com/arttor/helmify/pkg/processor/deployment"
-	"github.com/arttor/helmify/pkg/processor/statefulset"
+	"github.com/arttor/helmify/pkg/processor/gateway"
+	"github.com/arttor/helmify/pkg/processor/hpa"
+	"github.com/arttor/helmify/pkg/processor/istio"
+	"github.com/arttor/helmify/pkg/processor/job"
+	"github.com/arttor/helmify/pkg/processor/monitoring"
+	"github.com/arttor/helmify/pkg/processor/openshift"
+	"github.com/arttor/helmify/pkg/processor/quota"
 	"github.com/arttor/helmify/pkg/processor/rbac"
+	"github.com/arttor/helmify/pkg/processor/rollout"
 	"github.com/arttor/helmify/pkg/processor/secret"
 	"github.com/arttor/helmify/pkg/processor/service"
+	"github.com/arttor/helmify/pkg/processor/statefulset"
 	"github.com/arttor/helmify/pkg/processor/storage"
+	"github.com/arttor/helmify/pkg/processor/traefik"
 	"github.com/arttor/helmify/pkg/processor/webhook"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // Start - application entrypoint for processing input to a Helm chart.
@@ -31,7 +45,11 @@ func Start(input io.Reader, config config.Config) error {
 	if err != nil {
 		return err
 	}
-	setLogLevel(config)
+	var rep *report
+	if config.Report != "" {
+		rep = newReport()
+	}
+	initLogging(config, rep)
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	defer cancelFunc()
 	done := make(chan os.Signal, 1)
@@ -41,39 +59,188 @@ func Start(input io.Reader, config config.Config) error {
 		logrus.Debug("Received termination, signaling shutdown")
 		cancelFunc()
 	}()
-	objects := decoder.Decode(ctx.Done(), input)
-	appCtx := New(config, helm.NewOutput())
-	appCtx = appCtx.WithProcessors(
+	summary, err := convert(ctx, input, config, rep)
+	if err == nil {
+		// printed at any log level
+		fmt.Fprintln(os.Stderr, summary)
+	}
+	if rep != nil {
+		if reportErr := rep.write(config.Report); reportErr != nil && err == nil {
+			err = reportErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return publish(ctx, config)
+}
+
+// Convert - creates chart from manifests of configured input or input reader in a temporary dir and returns
+// chart files content by slash-separated path relative to the chart dir. Nothing is written to config.ChartDir.
+func Convert(ctx context.Context, input io.Reader, config config.Config) (map[string][]byte, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if config.Diff || config.DryRun || config.Package || config.Push != "" || config.Report != "" {
+		return nil, errors.New("diff, dry-run, package, push and report are not supported by in-memory conversion")
+	}
+	tmpDir, err := ioutil.TempDir("", "helmify-convert")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create temporary dir")
+	}
+	defer os.RemoveAll(tmpDir)
+	config.ChartDir = tmpDir
+	_, err = convert(ctx, input, config, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return readChart(filepath.Join(tmpDir, config.ChartName))
+}
+
+// convert - reads objects from configured input or input reader and creates chart in config.ChartDir.
+// Returns summary of converted and skipped objects.
+func convert(ctx context.Context, input io.Reader, config config.Config, rep *report) (string, error) {
+	var err error
+	if config.Kustomize != "" {
+		input, err = kustomizeInput(&config)
+		if err != nil {
+			return "", err
+		}
+	}
+	if config.Release != "" {
+		cfg, err := releaseConfig()
+		if err != nil {
+			return "", err
+		}
+		input, err = releaseInput(cfg, config.Release)
+		if err != nil {
+			return "", err
+		}
+	}
+	var objects <-chan *unstructured.Unstructured
+	if len(config.Files) != 0 {
+		objects, err = decoder.DecodeFiles(ctx.Done(), config.Files)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		objects = decoder.Decode(ctx.Done(), input)
+	}
+	objects = filterObjects(objects, config, rep)
+	if config.Subcharts {
+		return createSubcharts(ctx.Done(), objects, config, rep)
+	}
+	return createChart(ctx.Done(), config, objects, rep)
+}
+
+// readChart - returns content of all files in chartDir by slash-separated relative path.
+func readChart(chartDir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.Walk(chartDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(chartDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "unable to read "+rel)
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read chart")
+	}
+	return files, nil
+}
+
+// createChart - creates chart from all objects. Returns summary of converted and skipped objects.
+func createChart(stop <-chan struct{}, config config.Config, objects <-chan *unstructured.Unstructured, rep *report) (string, error) {
+	appCtx := newAppContext(config).WithReport(rep)
+	for obj := range objects {
+		appCtx.Add(obj)
+	}
+	err := appCtx.CreateHelm(stop)
+	if err != nil {
+		return "", err
+	}
+	return appCtx.Summary(), nil
+}
+
+// newAppContext - returns context with all processors writing chart to filesystem.
+func newAppContext(config config.Config) *appContext {
+	return New(config, helm.NewOutput()).WithProcessors(
 		configmap.New(),
 		crd.New(),
 		daemonset.New(),
 		deployment.New(),
+		job.NewCronJob(),
+		job.NewJob(),
+		gateway.New(),
+		gateway.NewRoute(),
+		hpa.New(),
+		istio.New(),
 		statefulset.New(),
 		storage.New(),
 		service.New(),
 		service.NewIngress(),
+		openshift.NewDeploymentConfig(),
+		openshift.NewRoute(),
+		monitoring.PodMonitor(),
+		monitoring.PrometheusRule(),
+		monitoring.ServiceMonitor(),
+		quota.LimitRange(),
+		quota.ResourceQuota(),
 		rbac.ClusterRoleBinding(),
 		rbac.Role(),
 		rbac.RoleBinding(),
 		rbac.ServiceAccount(),
+		rollout.New(),
 		secret.New(),
+		secret.NewExternal(),
+		secret.NewSealed(),
+		traefik.New(),
 		webhook.Issuer(),
 		webhook.Certificate(),
 		webhook.ValidatingWebhook(),
 		webhook.MutatingWebhook(),
+		webhook.APIService(),
 	).WithDefaultProcessor(processor.Default())
-	for obj := range objects {
-		appCtx.Add(obj)
-	}
-	return appCtx.CreateHelm(ctx.Done())
 }
 
-func setLogLevel(config config.Config) {
-	logrus.SetLevel(logrus.ErrorLevel)
+// initLogging - sets log level and format. If report is set, warnings are collected into it at any log level.
+func initLogging(config config.Config, rep *report) {
+	logger := logrus.StandardLogger()
+	logger.ReplaceHooks(make(logrus.LevelHooks))
+	logger.SetOutput(os.Stderr)
+	if config.LogFormat == "json" {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{})
+	}
+	level := logrus.ErrorLevel
 	if config.Verbose {
-		logrus.SetLevel(logrus.InfoLevel)
+		level = logrus.InfoLevel
 	}
 	if config.VeryVerbose {
-		logrus.SetLevel(logrus.DebugLevel)
+		level = logrus.DebugLevel
+	}
+	logger.SetLevel(level)
+	if rep == nil {
+		return
+	}
+	logger.AddHook(rep)
+	if level < logrus.WarnLevel {
+		// warnings are logged for the report only
+		logger.SetLevel(logrus.WarnLevel)
+		logger.SetOutput(ioutil.Discard)
+		logger.AddHook(&writerHook{level: level, writer: os.Stderr})
 	}
 }
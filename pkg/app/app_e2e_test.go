@@ -2,12 +2,21 @@ package app
 
 import (
 	"bufio"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/arttor/helmify/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -58,3 +67,543 @@ func TestApp(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestNoValues(t *testing.T) {
+	dir := t.TempDir()
+	input := strings.NewReader(strStatefulSet + "\n---\n" + strService)
+	err := Start(input, config.Config{ChartName: appChartName, ChartDir: dir, NoValues: true})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	values, err := ioutil.ReadFile(filepath.Join(chartDir, "values.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{}\n", string(values))
+
+	sts, err := ioutil.ReadFile(filepath.Join(chartDir, "templates", "statefulset.yaml"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(sts), ".Values")
+	assert.Contains(t, string(sts), "image: postgres:14")
+	assert.Contains(t, string(sts), "value: cluster.local")
+	assert.Contains(t, string(sts), `{{- include "test-app.selectorLabels" . | nindent 6 }}`)
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+func TestConfigChecksum(t *testing.T) {
+	dir := t.TempDir()
+	file, err := os.Open("../../test_data/sample-app.yaml")
+	assert.NoError(t, err)
+
+	err = Start(bufio.NewReader(file), config.Config{ChartName: appChartName, ChartDir: dir, ConfigChecksum: true})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	depl, err := ioutil.ReadFile(filepath.Join(chartDir, "templates", "deployment.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(depl), `checksum/my-config: {{ include (print $.Template.BasePath "/my-config.yaml") . | sha256sum }}`)
+	assert.Contains(t, string(depl), `checksum/my-secret-vars: {{ include (print $.Template.BasePath "/my-secret-vars.yaml") . | sha256sum }}`)
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+const strTemplatedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-dashboards
+  namespace: my-app-ns
+  annotations:
+    legend: '{{ instance }}'
+data:
+  dashboard.json: |
+    {"legendFormat": "{{instance}} - {{ job }}"}
+  title: '{{ .Title }}'
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: my-app-rules
+  namespace: my-app-ns
+spec:
+  groups:
+  - name: my-app
+    rules:
+    - alert: InstanceDown
+      expr: up == 0
+      annotations:
+        summary: '{{ $labels.instance }} is down'
+        description: |
+          Instance {{ $labels.instance }}
+          of job {{ $labels.job }} is down.`
+
+func TestEscapeTemplates(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strTemplatedConfigMap), config.Config{ChartName: appChartName, ChartDir: dir})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	values, err := ioutil.ReadFile(filepath.Join(chartDir, "values.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(values), `title: '{{ .Title }}'`)
+
+	chrt, err := loader.Load(chartDir)
+	assert.NoError(t, err)
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+
+	cm := out[appChartName+"/templates/dashboards.yaml"]
+	assert.Contains(t, cm, `legend: "{{ instance }}"`)
+	assert.Contains(t, cm, `"legendFormat": "{{instance}} - {{ job }}"`)
+	assert.Contains(t, cm, `title: "{{ .Title }}"`)
+	rules := out[appChartName+"/templates/rules.yaml"]
+	assert.Contains(t, rules, `summary: "{{ $labels.instance }} is down"`)
+	assert.Contains(t, rules, "Instance {{ $labels.instance }}\n          of job {{ $labels.job }} is down.")
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+func TestValuesSchema(t *testing.T) {
+	dir := t.TempDir()
+	file, err := os.Open("../../test_data/sample-app.yaml")
+	assert.NoError(t, err)
+
+	err = Start(bufio.NewReader(file), config.Config{ChartName: appChartName, ChartDir: dir, ValuesSchema: true})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	chrt, err := loader.Load(chartDir)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, chrt.Schema)
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+	result = helmLint.Run([]string{chartDir}, map[string]interface{}{"myapp": map[string]interface{}{"replicas": "three"}})
+	assert.NotEmpty(t, result.Errors)
+}
+
+func TestTestHooks(t *testing.T) {
+	dir := t.TempDir()
+	file, err := os.Open("../../test_data/sample-app.yaml")
+	assert.NoError(t, err)
+
+	err = Start(bufio.NewReader(file), config.Config{ChartName: appChartName, ChartDir: dir, TestHooks: true})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	_, err = os.Stat(filepath.Join(chartDir, "templates", "tests", "test-connection.yaml"))
+	assert.NoError(t, err)
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+const strComponents = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: myapp-config
+data:
+  mode: prod
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp-backend
+  labels:
+    app.kubernetes.io/component: backend
+spec:
+  selector:
+    matchLabels:
+      app: backend
+  template:
+    metadata:
+      labels:
+        app: backend
+    spec:
+      containers:
+      - name: backend
+        image: backend:1.0
+        envFrom:
+        - configMapRef:
+            name: myapp-config
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myapp-backend
+  labels:
+    app.kubernetes.io/component: backend
+spec:
+  selector:
+    app: backend
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp-frontend
+  labels:
+    app.kubernetes.io/component: frontend
+spec:
+  selector:
+    matchLabels:
+      app: frontend
+  template:
+    metadata:
+      labels:
+        app: frontend
+    spec:
+      containers:
+      - name: frontend
+        image: frontend:1.0
+`
+
+func TestSubcharts(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strComponents), config.Config{ChartName: appChartName, ChartDir: dir, Subcharts: true})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	chrt, err := loader.Load(chartDir)
+	assert.NoError(t, err)
+	var subcharts []string
+	for _, dep := range chrt.Dependencies() {
+		subcharts = append(subcharts, dep.Name())
+	}
+	assert.ElementsMatch(t, []string{"backend", "frontend"}, subcharts)
+	assert.Len(t, chrt.Metadata.Dependencies, 2)
+	assert.Equal(t, "backend.enabled", chrt.Metadata.Dependencies[0].Condition)
+
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	assert.Contains(t, out[appChartName+"/templates/config.yaml"], "name: release-test-app-config")
+	backend := out[appChartName+"/charts/backend/templates/deployment.yaml"]
+	assert.Contains(t, backend, "name: release-test-app-backend")
+	assert.Contains(t, backend, "name: release-test-app-config")
+	assert.Contains(t, out[appChartName+"/charts/backend/templates/backend.yaml"], "name: release-test-app-backend")
+	assert.Contains(t, out[appChartName+"/charts/frontend/templates/deployment.yaml"], "name: release-test-app-frontend")
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+func TestLibraryChart(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strComponents), config.Config{ChartName: appChartName, ChartDir: dir, LibraryChart: "common"})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	chrt, err := loader.Load(chartDir)
+	assert.NoError(t, err)
+	assert.Len(t, chrt.Metadata.Dependencies, 1)
+	assert.Equal(t, "common", chrt.Metadata.Dependencies[0].Name)
+	assert.Len(t, chrt.Dependencies(), 1)
+	assert.Equal(t, "library", chrt.Dependencies()[0].Metadata.Type)
+
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	var rendered []string
+	for _, content := range out {
+		rendered = append(rendered, content)
+	}
+	all := strings.Join(rendered, "\n")
+	assert.Contains(t, all, "name: release-test-app-backend")
+	assert.Contains(t, all, "image: backend:1.0")
+	assert.Contains(t, all, "app.kubernetes.io/name: test-app")
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+func TestSourceFilenames(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "deploy")
+	assert.NoError(t, os.MkdirAll(filepath.Join(input, "db"), 0750))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(input, "config.yml"), []byte(strConfigMapA+"\n---\n"+strConfigMapB), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(input, "db", "db.yaml"), []byte(strStatefulSet+"\n---\n"+strService), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(input, "README.md"), []byte("# not a manifest"), 0600))
+
+	err := Start(nil, config.Config{ChartName: appChartName, ChartDir: dir, Files: []string{input}, SourceFilenames: true})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	configMaps, err := ioutil.ReadFile(filepath.Join(chartDir, "templates", "config.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(configMaps), `name: {{ include "test-app.fullname" . }}-config-a`)
+	assert.Contains(t, string(configMaps), `name: {{ include "test-app.fullname" . }}-config-b`)
+	assert.NotContains(t, string(configMaps), "source-file")
+	db, err := ioutil.ReadFile(filepath.Join(chartDir, "templates", "db", "db.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(db), "kind: StatefulSet")
+	assert.Contains(t, string(db), "kind: Service")
+	assert.NoFileExists(t, filepath.Join(chartDir, "templates", "db-a.yaml"))
+
+	helmLint := action.NewLint()
+	helmLint.Strict = true
+	helmLint.Namespace = "test-ns"
+	result := helmLint.Run([]string{chartDir}, nil)
+	for _, err = range result.Errors {
+		assert.NoError(t, err)
+	}
+}
+
+func TestKustomize(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "overlay")
+	assert.NoError(t, os.MkdirAll(input, 0750))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(input, "kustomization.yaml"), []byte("namePrefix: prod-\nresources:\n- statefulset.yaml\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(input, "statefulset.yaml"), []byte(strStatefulSet), 0600))
+
+	err := Start(nil, config.Config{ChartName: appChartName, ChartDir: dir, Kustomize: input})
+	assert.NoError(t, err)
+
+	db, err := ioutil.ReadFile(filepath.Join(dir, appChartName, "templates", "statefulset.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(db), `name: {{ include "test-app.fullname" . }}-my-app-db`)
+}
+
+const strNestedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config
+data:
+  config.json: '{"a": 1, "nested": {"ratio": 0.5, "title": "it''s {{ .Title }}"}}'
+  config.yaml: |
+    nested: {ratio: 0.5}
+    legend: "{{ instance }}"`
+
+func TestNestedConfigMap(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strNestedConfigMap), config.Config{ChartName: appChartName, ChartDir: dir})
+	assert.NoError(t, err)
+
+	chartDir := filepath.Join(dir, appChartName)
+	chrt, err := loader.Load(chartDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"configJson": map[string]interface{}{
+			"a":      float64(1),
+			"nested": map[string]interface{}{"ratio": 0.5, "title": "it's {{ .Title }}"},
+		},
+		"configYaml": map[string]interface{}{
+			"nested": map[string]interface{}{"ratio": 0.5},
+			"legend": "{{ instance }}",
+		},
+	}, chrt.Values["myAppConfig"])
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	cm := out[appChartName+"/templates/my-app-config.yaml"]
+	assert.Contains(t, cm, `"ratio": 0.5,`)
+	assert.Contains(t, cm, `"title": "it's {{ .Title }}"`)
+	assert.Contains(t, cm, `legend: "{{ instance }}"`)
+}
+
+const strIngressAndRoute = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-app-web
+spec:
+  tls:
+  - hosts:
+    - web.example.com
+  rules:
+  - host: web.example.com
+---
+apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: my-app-web
+spec:
+  host: web.apps.example.com
+  to:
+    kind: Service
+    name: my-app-web
+  tls:
+    termination: edge`
+
+func TestIngressAndRoute(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strIngressAndRoute), config.Config{ChartName: appChartName, ChartDir: dir})
+	assert.NoError(t, err)
+
+	chrt, err := loader.Load(filepath.Join(dir, appChartName))
+	assert.NoError(t, err)
+	assert.Contains(t, chrt.Values, "ingress")
+	assert.Contains(t, chrt.Values, "route")
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	var rendered strings.Builder
+	for _, content := range out {
+		rendered.WriteString(content)
+	}
+	assert.Contains(t, rendered.String(), `termination: "edge"`)
+	assert.Contains(t, rendered.String(), `- "web.example.com"`)
+}
+
+const strDockerConfigSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: my-app-pull-secret
+type: kubernetes.io/dockerconfigjson
+data:
+  .dockerconfigjson: eyJhdXRocyI6eyJyZWdpc3RyeS5leGFtcGxlLmNvbSI6eyJhdXRoIjoiZFhObGNqcHdZWE56In19fQ==`
+
+func TestNoValues_dockerConfigSecret(t *testing.T) {
+	dir := t.TempDir()
+	input := strings.NewReader(strDockerConfigSecret + "\n---\n" + strService)
+	err := Start(input, config.Config{ChartName: appChartName, ChartDir: dir, NoValues: true})
+	assert.NoError(t, err)
+
+	chrt, err := loader.Load(filepath.Join(dir, appChartName))
+	assert.NoError(t, err)
+	vals, err := chartutil.ToRenderValues(chrt, map[string]interface{}{"pullSecret": map[string]interface{}{
+		"registry": "registry.example.com",
+		"username": "user",
+		"password": "pass",
+	}}, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	sec := corev1.Secret{}
+	assert.NoError(t, yaml.Unmarshal([]byte(out[appChartName+"/templates/pull-secret.yaml"]), &sec))
+	assert.JSONEq(t, `{"auths":{"registry.example.com":{"username":"user","password":"pass","auth":"dXNlcjpwYXNz"}}}`, string(sec.Data[".dockerconfigjson"]))
+}
+
+const strServiceAccountDeployment = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-app-sa
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      serviceAccountName: my-app-sa
+      containers:
+      - name: web
+        image: nginx:1.25`
+
+func TestNoValues_serviceAccount(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strServiceAccountDeployment), config.Config{ChartName: appChartName, ChartDir: dir, NoValues: true})
+	assert.NoError(t, err)
+
+	chrt, err := loader.Load(filepath.Join(dir, appChartName))
+	assert.NoError(t, err)
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	sa := corev1.ServiceAccount{}
+	assert.NoError(t, yaml.Unmarshal([]byte(out[appChartName+"/templates/serviceaccount.yaml"]), &sa))
+	assert.Equal(t, "release-test-app-sa", sa.Name)
+	assert.Contains(t, out[appChartName+"/templates/deployment.yaml"], "serviceAccountName: release-test-app-sa")
+}
+
+const strTopologySpreadDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-app-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: topology.kubernetes.io/zone
+        whenUnsatisfiable: DoNotSchedule
+        labelSelector:
+          matchLabels:
+            app: web
+      - maxSkew: 2
+        topologyKey: kubernetes.io/hostname
+        whenUnsatisfiable: ScheduleAnyway
+      containers:
+      - name: web
+        image: nginx:1.21`
+
+func TestNoValues_topologySpread(t *testing.T) {
+	dir := t.TempDir()
+	err := Start(strings.NewReader(strTopologySpreadDeployment), config.Config{ChartName: appChartName, ChartDir: dir, NoValues: true})
+	assert.NoError(t, err)
+
+	chrt, err := loader.Load(filepath.Join(dir, appChartName))
+	assert.NoError(t, err)
+	vals, err := chartutil.ToRenderValues(chrt, chrt.Values, chartutil.ReleaseOptions{Name: "release", Namespace: "ns"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	depl := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(out[appChartName+"/templates/deployment.yaml"]), &depl))
+	constraints := depl.Spec.Template.Spec.TopologySpreadConstraints
+	assert.Len(t, constraints, 2)
+	assert.Equal(t, int32(1), constraints[0].MaxSkew)
+	assert.Equal(t, "topology.kubernetes.io/zone", constraints[0].TopologyKey)
+	assert.Equal(t, corev1.DoNotSchedule, constraints[0].WhenUnsatisfiable)
+	assert.Equal(t, map[string]string{"app": "web", "app.kubernetes.io/name": appChartName, "app.kubernetes.io/instance": "release"}, constraints[0].LabelSelector.MatchLabels)
+	assert.Equal(t, int32(2), constraints[1].MaxSkew)
+	assert.Equal(t, "kubernetes.io/hostname", constraints[1].TopologyKey)
+	assert.Equal(t, corev1.ScheduleAnyway, constraints[1].WhenUnsatisfiable)
+	assert.Nil(t, constraints[1].LabelSelector)
+}
@@ -16,6 +16,13 @@ type appContext struct {
 	config           config.Config
 	appMeta          *metadata.Service
 	objects          []*unstructured.Unstructured
+	// files source files of objects relative to input paths. Empty if objects are read from stdin.
+	files []string
+	// sources unmodified copies of objects compared with the chart on verification.
+	sources []*unstructured.Unstructured
+	summary *summary
+	// report optional conversion report.
+	report *report
 }
 
 // New returns context with config set.
@@ -24,6 +31,7 @@ func New(config config.Config, output helmify.Output) *appContext {
 		config:  config,
 		appMeta: metadata.New(config),
 		output:  output,
+		summary: newSummary(),
 	}
 }
 
@@ -39,27 +47,74 @@ func (c *appContext) WithDefaultProcessor(processor helmify.Processor) *appConte
 	return c
 }
 
+// WithReport  sets conversion report filled on chart creation and returns the context.
+func (c *appContext) WithReport(r *report) *appContext {
+	c.report = r
+	return c
+}
+
 // Add k8s object to app context.
 func (c *appContext) Add(obj *unstructured.Unstructured) {
+	c.files = append(c.files, popSourceFile(obj))
+	if c.config.Verify {
+		c.sources = append(c.sources, obj.DeepCopy())
+	}
+	// template actions found in manifests are escaped on chart output.
+	helmify.MarkTemplates(obj.Object)
 	// we need to add all objects before start processing only to define app metadata.
 	c.appMeta.Load(obj)
 	c.objects = append(c.objects, obj)
 }
 
+// AddReference loads k8s object of another chart, e.g. sibling subchart, to template references to it.
+// The object is not processed into this chart.
+func (c *appContext) AddReference(obj *unstructured.Unstructured) {
+	helmify.MarkTemplates(obj.Object)
+	c.appMeta.LoadReference(obj)
+}
+
 // CreateHelm creates helm chart from context k8s objects.
 func (c *appContext) CreateHelm(stop <-chan struct{}) error {
 	logrus.WithFields(logrus.Fields{
 		"ChartName": c.appMeta.ChartName(),
 		"Namespace": c.appMeta.Namespace(),
 	}).Info("creating a chart")
+	if c.config.ReplicaCount {
+		c.warnSharedReplicaCount()
+	}
 	var templates []helmify.Template
-	for _, obj := range c.objects {
+	// source resources of template files reported by lint
+	sources := map[string][]string{}
+	// copies of converted objects compared with the chart on verification
+	var converted []*unstructured.Unstructured
+	for i, obj := range c.objects {
+		if c.appMeta.IsDependency(obj.GetName()) {
+			c.report.start(c.config.ChartName, obj, reportDependency)
+			logrus.WithFields(logrus.Fields{
+				"Kind": obj.GetKind(),
+				"Name": obj.GetName(),
+			}).Info("Skipping: resource is replaced by chart dependency.")
+			c.summary.addSkipped(obj.GetKind())
+			continue
+		}
+		c.report.start(c.config.ChartName, obj, reportSkipped)
 		template, err := c.process(obj)
 		if err != nil {
 			return err
 		}
+		if template != nil && c.config.SourceFilenames && c.files[i] != "" {
+			template = withFilename(template, sourceFilename(c.files[i]))
+		}
+		c.report.setResult(template)
 		if template != nil {
 			templates = append(templates, template)
+			sources[template.Filename()] = append(sources[template.Filename()], obj.GetKind()+"/"+obj.GetName())
+			if c.config.Verify {
+				converted = append(converted, c.sources[i])
+			}
+			c.summary.addConverted(obj.GetKind())
+		} else {
+			c.summary.addSkipped(obj.GetKind())
 		}
 		select {
 		case <-stop:
@@ -67,12 +122,54 @@ func (c *appContext) CreateHelm(stop <-chan struct{}) error {
 		default:
 		}
 	}
-	return c.output.Create(c.config.ChartDir, c.config.ChartName, c.config.Crd, templates)
+	c.report.done()
+	if notes := c.newNotes(); notes != nil {
+		templates = append(templates, notes)
+	}
+	if c.config.TestHooks {
+		if tests := c.newTests(); tests != nil {
+			templates = append(templates, tests)
+		}
+	}
+	err := c.output.Create(c.config, templates)
+	if err != nil || c.config.Diff || c.config.DryRun {
+		// chart on disk is not changed in diff and dry-run modes
+		return err
+	}
+	if c.config.Lint {
+		err = c.lint(sources)
+		if err != nil {
+			return err
+		}
+	}
+	if c.config.Verify {
+		return c.verify(converted)
+	}
+	return nil
+}
+
+// warnSharedReplicaCount warns if top-level replicaCount value is shared by several workloads.
+func (c *appContext) warnSharedReplicaCount() {
+	workloads := 0
+	for _, obj := range c.objects {
+		if obj.GetKind() == "Deployment" || obj.GetKind() == "StatefulSet" {
+			workloads++
+		}
+	}
+	if workloads > 1 {
+		logrus.Warnf("replicaCount value is shared by %d workloads", workloads)
+	}
+}
+
+// Summary returns counts of converted and skipped objects by kind.
+func (c *appContext) Summary() string {
+	return c.summary.String()
 }
 
 func (c *appContext) process(obj *unstructured.Unstructured) (helmify.Template, error) {
 	for _, p := range c.processors {
 		if processed, result, err := p.Process(c.appMeta, obj); processed {
+			c.report.setProcessor(p)
 			if err != nil {
 				return nil, err
 			}
@@ -92,6 +189,7 @@ func (c *appContext) process(obj *unstructured.Unstructured) (helmify.Template,
 		}).Warn("Skipping: no suitable processor for resource.")
 		return nil, nil
 	}
+	c.report.setProcessor(c.defaultProcessor)
 	_, t, err := c.defaultProcessor.Process(c.appMeta, obj)
 	return t, err
 }
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor/configmap"
+	"github.com/arttor/helmify/pkg/processor/service"
+	"github.com/arttor/helmify/pkg/processor/statefulset"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	strConfigMapA = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config-a
+data:
+  key: value`
+	strConfigMapB = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config-b
+data:
+  key: value`
+	strStatefulSet = `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-app-db
+spec:
+  selector:
+    matchLabels:
+      app: db
+  template:
+    metadata:
+      labels:
+        app: db
+    spec:
+      containers:
+      - name: db
+        image: postgres:14`
+	strService = `apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-db
+spec:
+  ports:
+  - port: 5432
+  selector:
+    app: db`
+	strUnknown = `apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: my-app-unknown`
+)
+
+type fakeOutput struct {
+	templates []helmify.Template
+}
+
+func (o *fakeOutput) Create(_ config.Config, templates []helmify.Template) error {
+	o.templates = templates
+	return nil
+}
+
+func TestAppContext_Summary(t *testing.T) {
+	output := &fakeOutput{}
+	ctx := New(config.Config{ChartName: "my-app"}, output).
+		WithProcessors(configmap.New(), statefulset.New(), service.New())
+	for _, obj := range []string{strConfigMapA, strConfigMapB, strStatefulSet, strService, strUnknown} {
+		ctx.Add(internal.GenerateObj(obj))
+	}
+	err := ctx.CreateHelm(nil)
+	assert.NoError(t, err)
+	assert.Len(t, output.templates, 5)
+	assert.Equal(t, "Converted: 2 ConfigMap, 1 Service, 1 StatefulSet; Skipped: 1 Unknown", ctx.Summary())
+}
+
+func TestAppContext_dependencies(t *testing.T) {
+	output := &fakeOutput{}
+	conf := config.Config{ChartName: "my-app", Dependencies: []config.Dependency{{Name: "db", Version: "12.x", Repository: "@bitnami"}}}
+	ctx := New(conf, output).WithProcessors(configmap.New(), statefulset.New(), service.New())
+	for _, obj := range []string{strConfigMapA, strStatefulSet, strService} {
+		ctx.Add(internal.GenerateObj(obj))
+	}
+	err := ctx.CreateHelm(nil)
+	assert.NoError(t, err)
+	assert.Len(t, output.templates, 1)
+	assert.Equal(t, "Converted: 1 ConfigMap; Skipped: 1 Service, 1 StatefulSet", ctx.Summary())
+}
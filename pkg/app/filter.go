@@ -0,0 +1,66 @@
+package app
+
+import (
+	"path"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// filterObjects - drops objects excluded by config filters. Counts of dropped objects by kind are logged when
+// all objects are read. Dropped objects are added to optional report.
+func filterObjects(objects <-chan *unstructured.Unstructured, conf config.Config, rep *report) <-chan *unstructured.Unstructured {
+	if len(conf.SkipKinds) == 0 && len(conf.SkipNames) == 0 && len(conf.SkipNamespaces) == 0 && conf.Only == "" {
+		return objects
+	}
+	// selector is validated with config
+	selector, _ := labels.Parse(conf.Only)
+	res := make(chan *unstructured.Unstructured)
+	go func() {
+		defer close(res)
+		dropped := map[string]int{}
+		for obj := range objects {
+			if reason := filterReason(obj, conf, selector); reason != "" {
+				rep.start(conf.ChartName, obj, reportFiltered)
+				rep.done()
+				logrus.WithFields(logrus.Fields{
+					"Kind": obj.GetKind(),
+					"Name": obj.GetName(),
+				}).Info("Skipping: resource is " + reason)
+				dropped[obj.GetKind()]++
+				continue
+			}
+			res <- obj
+		}
+		if len(dropped) != 0 {
+			logrus.Info("Filtered out: " + countsString(dropped))
+		}
+	}()
+	return res
+}
+
+// filterReason - returns reason to drop the object or empty string if the object passes filters.
+func filterReason(obj *unstructured.Unstructured, conf config.Config, selector labels.Selector) string {
+	for _, kind := range conf.SkipKinds {
+		if strings.EqualFold(obj.GetKind(), kind) {
+			return "excluded by kind."
+		}
+	}
+	for _, pattern := range conf.SkipNames {
+		if ok, _ := path.Match(pattern, obj.GetName()); ok {
+			return "excluded by name."
+		}
+	}
+	for _, ns := range conf.SkipNamespaces {
+		if obj.GetNamespace() == ns {
+			return "excluded by namespace."
+		}
+	}
+	if !selector.Matches(labels.Set(obj.GetLabels())) {
+		return "not matching label selector."
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_filterObjects(t *testing.T) {
+	backend := internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-backend
+  namespace: my-ns
+  labels:
+    app: backend`)
+	frontend := internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-frontend
+  namespace: my-ns
+  labels:
+    app: frontend`)
+	ns := internal.GenerateObj(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-ns`)
+	system := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config
+  namespace: kube-system`)
+	filter := func(conf config.Config) []string {
+		in := make(chan *unstructured.Unstructured, 4)
+		in <- backend
+		in <- frontend
+		in <- ns
+		in <- system
+		close(in)
+		var names []string
+		for obj := range filterObjects(in, conf, nil) {
+			names = append(names, obj.GetName())
+		}
+		return names
+	}
+	assert.Equal(t, []string{"my-app-backend", "my-app-frontend", "my-ns", "my-app-config"}, filter(config.Config{}))
+	assert.Equal(t, []string{"my-app-backend", "my-app-frontend", "my-app-config"}, filter(config.Config{SkipKinds: []string{"namespace"}}))
+	assert.Equal(t, []string{"my-app-backend", "my-ns"}, filter(config.Config{SkipNames: []string{"*-frontend", "my-app-config"}}))
+	assert.Equal(t, []string{"my-app-backend", "my-app-frontend", "my-ns"}, filter(config.Config{SkipNamespaces: []string{"kube-system"}}))
+	assert.Equal(t, []string{"my-app-backend"}, filter(config.Config{Only: "app=backend"}))
+	assert.Equal(t, []string{"my-app-frontend"}, filter(config.Config{Only: "app", SkipNames: []string{"my-app-backend"}}))
+}
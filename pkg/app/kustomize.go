@@ -0,0 +1,34 @@
+package app
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/kustomize"
+	"github.com/sirupsen/logrus"
+)
+
+// kustomizeInput - builds kustomization set in config and returns its manifests.
+// Kustomization name prefix and suffix are set in config to be trimmed from template names.
+func kustomizeInput(conf *config.Config) (io.Reader, error) {
+	kustomization, err := kustomize.Load(conf.Kustomize)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := kustomize.Build(conf.Kustomize)
+	if err != nil {
+		return nil, err
+	}
+	if conf.NamePrefix == "" {
+		conf.NamePrefix = kustomization.NamePrefix
+	}
+	if conf.NameSuffix == "" {
+		conf.NameSuffix = kustomization.NameSuffix
+	}
+	logrus.WithFields(logrus.Fields{
+		"namePrefix": conf.NamePrefix,
+		"nameSuffix": conf.NameSuffix,
+	}).Debug("kustomization built")
+	return bytes.NewReader(manifests), nil
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+// lint - runs 'helm lint' on the created chart. Lint messages are logged with source resources of templates
+// they refer to. Returns error if there are lint errors.
+func (c *appContext) lint(sources map[string][]string) error {
+	chartDir := filepath.Join(c.config.ChartDir, c.config.ChartName)
+	client := action.NewLint()
+	client.Namespace = c.appMeta.Namespace()
+	if client.Namespace == "" {
+		client.Namespace = "default"
+	}
+	result := client.Run([]string{chartDir}, nil)
+	errCount := 0
+	for _, msg := range result.Messages {
+		if msg.Severity < support.WarningSev {
+			continue
+		}
+		entry := logrus.WithField("chart", chartDir)
+		if resources := lintSources(msg, sources); len(resources) != 0 {
+			entry = entry.WithField("source", strings.Join(resources, ","))
+		}
+		if msg.Severity == support.ErrorSev {
+			errCount++
+			entry.Error(msg.Error())
+		} else {
+			entry.Warn(msg.Error())
+		}
+	}
+	if errCount != 0 {
+		return errors.Errorf("helm lint found %d errors in chart %s", errCount, chartDir)
+	}
+	logrus.WithField("chart", chartDir).Info("helm lint passed")
+	return nil
+}
+
+// lintSources - returns source resources of templates mentioned in lint message path or error.
+func lintSources(msg support.Message, sources map[string][]string) []string {
+	text := msg.Path + " " + msg.Err.Error()
+	var res []string
+	for filename, resources := range sources {
+		if strings.Contains(text, "/"+filename) {
+			res = append(res, resources...)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
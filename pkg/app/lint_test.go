@@ -0,0 +1,37 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+func Test_appContext_lint(t *testing.T) {
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+	assert.NoError(t, os.MkdirAll(filepath.Join(chartDir, "templates"), 0750))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("apiVersion: v2\nname: chart\nversion: 0.1.0\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte("web: {}\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "templates", "web.yaml"), []byte("replicas: {{ .Values.web.spec.replicas }}\n"), 0600))
+	c := New(config.Config{ChartName: "chart", ChartDir: dir}, nil)
+
+	err := c.lint(map[string][]string{"web.yaml": {"Deployment/my-web"}})
+	assert.Error(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte("web:\n  spec:\n    replicas: 1\n"), 0600))
+	assert.NoError(t, c.lint(map[string][]string{"web.yaml": {"Deployment/my-web"}}))
+}
+
+func Test_lintSources(t *testing.T) {
+	sources := map[string][]string{"web.yaml": {"Deployment/my-web"}, "my-web.yaml": {"Service/my-web"}}
+	msg := support.NewMessage(support.ErrorSev, "templates/", errors.New("template: chart/templates/web.yaml:1:20: nil pointer"))
+	assert.Equal(t, []string{"Deployment/my-web"}, lintSources(msg, sources))
+	msg = support.NewMessage(support.WarningSev, "templates/my-web.yaml", errors.New("invalid name"))
+	assert.Equal(t, []string{"Service/my-web"}, lintSources(msg, sources))
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor/service"
+	"github.com/iancoleman/strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const notesHeader = `Get the application URL by running these commands:`
+
+// notesIngressTempl - prints URLs of enabled Ingress hosts. %[1]s - Ingress values path.
+const notesIngressTempl = `
+{{- if .Values.%[1]s.enabled }}
+{{- range $host := .Values.%[1]s.hosts }}
+  {{- range .paths }}
+  http{{ if $.Values.%[1]s.tls }}s{{ end }}://{{ $host.host }}{{ .path }}
+  {{- end }}
+{{- end }}
+{{- end }}`
+
+// notesServiceTempl - prints access instructions depending on Service type like 'helm create' does.
+// %[1]s - Service values name, %[2]s - templated Service name.
+const notesServiceTempl = `
+Service %[2]s:
+{{- if eq .Values.%[1]s.type "NodePort" }}
+  export NODE_PORT=$(kubectl get --namespace {{ .Release.Namespace }} -o jsonpath="{.spec.ports[0].nodePort}" services %[2]s)
+  export NODE_IP=$(kubectl get nodes --namespace {{ .Release.Namespace }} -o jsonpath="{.items[0].status.addresses[0].address}")
+  echo http://$NODE_IP:$NODE_PORT
+{{- else if eq .Values.%[1]s.type "LoadBalancer" }}
+  NOTE: It may take a few minutes for the LoadBalancer IP to be available.
+        You can watch its status by running 'kubectl get --namespace {{ .Release.Namespace }} svc -w %[2]s'
+  export SERVICE_IP=$(kubectl get svc --namespace {{ .Release.Namespace }} %[2]s -o jsonpath="{.status.loadBalancer.ingress[0]['ip','hostname']}")
+  echo http://$SERVICE_IP:{{ (index .Values.%[1]s.ports 0).port }}
+{{- else }}
+  kubectl --namespace {{ .Release.Namespace }} port-forward svc/%[2]s {{ (index .Values.%[1]s.ports 0).port }}:{{ (index .Values.%[1]s.ports 0).port }}
+  echo http://127.0.0.1:{{ (index .Values.%[1]s.ports 0).port }}
+{{- end }}`
+
+var (
+	notesServiceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	notesIngressGVK = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+)
+
+// notes - chart NOTES.txt printed after install with access instructions for chart Ingresses and Services.
+type notes struct {
+	content string
+}
+
+// newNotes - returns NOTES.txt template for chart Ingresses and Services with ports. Returns nil if there are none.
+// Value names must match values of Ingress and Service processors.
+func (c *appContext) newNotes() helmify.Template {
+	var ingresses, services strings.Builder
+	for _, obj := range c.objects {
+		if c.appMeta.IsDependency(obj.GetName()) {
+			continue
+		}
+		name := c.appMeta.TrimName(obj.GetName())
+		switch obj.GroupVersionKind() {
+		case notesIngressGVK:
+			ingresses.WriteString(fmt.Sprintf(notesIngressTempl, service.IngressValuesPath(c.appMeta, obj.GetName())))
+		case notesServiceGVK:
+			ports, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+			if len(ports) == 0 {
+				continue
+			}
+			valuesName := strcase.ToLowerCamel(strings.TrimPrefix(name, "controller-manager-"))
+			services.WriteString(fmt.Sprintf(notesServiceTempl, valuesName, c.appMeta.TemplatedName(obj.GetName())))
+		}
+	}
+	if ingresses.Len() == 0 && services.Len() == 0 {
+		return nil
+	}
+	return &notes{content: notesHeader + ingresses.String() + services.String() + "\n"}
+}
+
+func (n *notes) Filename() string {
+	return "NOTES.txt"
+}
+
+func (n *notes) Values() helmify.Values {
+	return helmify.Values{}
+}
+
+func (n *notes) Write(writer io.Writer) error {
+	_, err := io.WriteString(writer, n.content)
+	return err
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/processor/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const strIngress = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-app-ingress
+spec:
+  rules:
+  - host: app.example.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: my-app-db
+            port:
+              number: 5432`
+
+func TestAppContext_notes(t *testing.T) {
+	t.Run("no services", func(t *testing.T) {
+		ctx := New(config.Config{ChartName: "my-app"}, &fakeOutput{})
+		ctx.Add(internal.GenerateObj(strConfigMapA))
+		assert.Nil(t, ctx.newNotes())
+	})
+	output := &fakeOutput{}
+	ctx := New(config.Config{ChartName: "my-app"}, output).WithProcessors(service.New(), service.NewIngress())
+	ctx.Add(internal.GenerateObj(strService))
+	ctx.Add(internal.GenerateObj(strIngress))
+	err := ctx.CreateHelm(nil)
+	assert.NoError(t, err)
+	assert.Len(t, output.templates, 3)
+	notes := output.templates[2]
+	assert.Equal(t, "NOTES.txt", notes.Filename())
+	buf := bytes.Buffer{}
+	assert.NoError(t, notes.Write(&buf))
+
+	values := map[string]interface{}{}
+	for _, tpl := range output.templates[:2] {
+		for k, v := range tpl.Values() {
+			values[k] = v
+		}
+	}
+	rendered, err := internal.RenderTemplate("my-app", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Equal(t, `Get the application URL by running these commands:
+  http://app.example.com/
+Service release-db:
+  kubectl --namespace ns port-forward svc/release-db 5432:5432
+  echo http://127.0.0.1:5432
+`, rendered)
+
+	values["db"].(map[string]interface{})["type"] = "NodePort"
+	rendered, err = internal.RenderTemplate("my-app", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Contains(t, rendered, `export NODE_PORT=$(kubectl get --namespace ns -o jsonpath="{.spec.ports[0].nodePort}" services release-db)`)
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"oras.land/oras-go/pkg/auth"
+	dockerauth "oras.land/oras-go/pkg/auth/docker"
+	"oras.land/oras-go/pkg/content"
+	"oras.land/oras-go/pkg/oras"
+)
+
+const (
+	// helmConfigMediaType - media type of Helm chart OCI manifest config.
+	helmConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+	// helmChartMediaType - media type of Helm chart OCI layer.
+	helmChartMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// publish - packages created chart into chart directory and pushes the archive to OCI registry if enabled by config.
+func publish(ctx context.Context, conf config.Config) error {
+	if (!conf.Package && conf.Push == "") || conf.Diff || conf.DryRun {
+		return nil
+	}
+	client := action.NewPackage()
+	client.Destination = conf.ChartDir
+	if client.Destination == "" {
+		client.Destination = "."
+	}
+	archive, err := client.Run(filepath.Join(conf.ChartDir, conf.ChartName), nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to package chart")
+	}
+	logrus.WithField("file", archive).Info("packaged")
+	if conf.Push == "" {
+		return nil
+	}
+	return pushChart(ctx, archive, conf.Push, conf.PushPlainHTTP)
+}
+
+// pushChart - pushes chart archive to OCI registry repository as '<repository>/<chart name>:<chart version>'
+// like 'helm push' does. Registry credentials are read from docker config.
+func pushChart(ctx context.Context, archive, remote string, plainHTTP bool) error {
+	if !strings.HasPrefix(remote, "oci://") {
+		return errors.Errorf("invalid push repository %s: must start with oci://", remote)
+	}
+	chrt, err := loader.Load(archive)
+	if err != nil {
+		return errors.Wrap(err, "unable to load chart archive")
+	}
+	data, err := ioutil.ReadFile(archive)
+	if err != nil {
+		return errors.Wrap(err, "unable to read chart archive")
+	}
+	configData, err := json.Marshal(chrt.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal chart metadata")
+	}
+	ref := strings.TrimSuffix(strings.TrimPrefix(remote, "oci://"), "/") + "/" + chrt.Metadata.Name + ":" + chrt.Metadata.Version
+	authClient, err := dockerauth.NewClient()
+	if err != nil {
+		return errors.Wrap(err, "unable to load docker config")
+	}
+	opts := []auth.ResolverOption{auth.WithResolverClient(http.DefaultClient)}
+	if plainHTTP {
+		opts = append(opts, auth.WithResolverPlainHTTP())
+	}
+	resolver, err := authClient.ResolverWithOpts(opts...)
+	if err != nil {
+		return errors.Wrap(err, "unable to create registry resolver")
+	}
+	store := content.NewMemoryStore()
+	chartDesc := store.Add("", helmChartMediaType, data)
+	configDesc := store.Add("", helmConfigMediaType, configData)
+	manifest, err := oras.Push(ctx, resolver, ref, store, []ocispec.Descriptor{chartDesc},
+		oras.WithConfig(configDesc), oras.WithNameValidation(nil))
+	if err != nil {
+		return errors.Wrapf(err, "unable to push chart to %s", ref)
+	}
+	logrus.WithFields(logrus.Fields{"ref": ref, "digest": manifest.Digest.String()}).Info("pushed")
+	return nil
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_publish(t *testing.T) {
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+	assert.NoError(t, os.MkdirAll(filepath.Join(chartDir, "templates"), 0750))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("apiVersion: v2\nname: chart\nversion: 0.2.0\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte("{}\n"), 0600))
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, publish(context.Background(), config.Config{ChartName: "chart", ChartDir: dir}))
+		assert.NoFileExists(t, filepath.Join(dir, "chart-0.2.0.tgz"))
+	})
+	t.Run("package", func(t *testing.T) {
+		assert.NoError(t, publish(context.Background(), config.Config{ChartName: "chart", ChartDir: dir, Package: true}))
+		assert.FileExists(t, filepath.Join(dir, "chart-0.2.0.tgz"))
+	})
+	t.Run("push", func(t *testing.T) {
+		t.Setenv("DOCKER_CONFIG", t.TempDir())
+		var mu sync.Mutex
+		var manifests []string
+		registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodHead:
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/blobs/uploads/"):
+				w.Header().Set("Location", r.URL.Path+"upload")
+				w.WriteHeader(http.StatusAccepted)
+			case r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/"):
+				body, _ := ioutil.ReadAll(r.Body)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(body).String())
+				mu.Lock()
+				manifests = append(manifests, r.URL.Path)
+				mu.Unlock()
+				w.WriteHeader(http.StatusCreated)
+			case r.Method == http.MethodPut:
+				w.Header().Set("Docker-Content-Digest", r.URL.Query().Get("digest"))
+				w.WriteHeader(http.StatusCreated)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer registry.Close()
+		remote := "oci://" + strings.TrimPrefix(registry.URL, "http://") + "/charts"
+
+		err := publish(context.Background(), config.Config{ChartName: "chart", ChartDir: dir, Push: remote, PushPlainHTTP: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/v2/charts/chart/manifests/0.2.0"}, manifests)
+	})
+	t.Run("invalid remote", func(t *testing.T) {
+		err := publish(context.Background(), config.Config{ChartName: "chart", ChartDir: dir, Push: "https://example.com/charts"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// releaseConfig - returns Helm action configuration reading release storage of the current kube context namespace
+// like helm CLI does. HELM_NAMESPACE and HELM_DRIVER env vars are respected.
+func releaseConfig() (*action.Configuration, error) {
+	settings := cli.New()
+	cfg := &action.Configuration{}
+	err := cfg.Init(settings.RESTClientGetter(), settings.Namespace(), os.Getenv("HELM_DRIVER"), logrus.Debugf)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to init Helm release storage")
+	}
+	return cfg, nil
+}
+
+// releaseInput - returns manifests and hooks of the latest revision of installed Helm release.
+func releaseInput(cfg *action.Configuration, name string) (io.Reader, error) {
+	rel, err := action.NewGet(cfg).Run(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get release %s", name)
+	}
+	manifests := []string{rel.Manifest}
+	for _, hook := range rel.Hooks {
+		manifests = append(manifests, hook.Manifest)
+	}
+	logrus.WithFields(logrus.Fields{
+		"release":  rel.Name,
+		"revision": rel.Version,
+		"hooks":    len(rel.Hooks),
+	}).Debug("release read")
+	return strings.NewReader(strings.Join(manifests, "\n---\n")), nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func Test_releaseInput(t *testing.T) {
+	cfg := &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: ioutil.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          t.Logf,
+	}
+	assert.NoError(t, cfg.Releases.Create(&release.Release{
+		Name:     "legacy",
+		Version:  1,
+		Info:     &release.Info{Status: release.StatusSuperseded},
+		Manifest: strConfigMapA,
+	}))
+	assert.NoError(t, cfg.Releases.Create(&release.Release{
+		Name:     "legacy",
+		Version:  2,
+		Info:     &release.Info{Status: release.StatusDeployed},
+		Manifest: strConfigMapB,
+		Hooks:    []*release.Hook{{Name: "my-app-db", Manifest: strService}},
+	}))
+
+	input, err := releaseInput(cfg, "legacy")
+	assert.NoError(t, err)
+	manifests, err := ioutil.ReadAll(input)
+	assert.NoError(t, err)
+	assert.Equal(t, strConfigMapB+"\n---\n"+strService, string(manifests))
+
+	_, err = releaseInput(cfg, "missing")
+	assert.Error(t, err)
+}
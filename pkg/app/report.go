@@ -0,0 +1,196 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Statuses of input objects in the report.
+const (
+	reportConverted  = "converted"
+	reportSkipped    = "skipped"
+	reportFiltered   = "filtered"
+	reportDependency = "dependency"
+)
+
+// report - machine-readable conversion report. Collects warnings logged while an object is processed as logrus hook.
+// Methods of nil report do nothing.
+type report struct {
+	mu      sync.Mutex
+	current *reportObject
+	// Objects every input object with its conversion result.
+	Objects []*reportObject `json:"objects"`
+	// Warnings logged not while processing an object.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type reportObject struct {
+	Chart      string `json:"chart,omitempty"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Status     string `json:"status"`
+	// Processor type of processor converted the object, e.g. 'configmap.configMap'.
+	Processor string `json:"processor,omitempty"`
+	// File chart template file of the object.
+	File string `json:"file,omitempty"`
+	// Values dot-separated keys of values created for the object.
+	Values   []string `json:"values,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func newReport() *report {
+	return &report{}
+}
+
+// start - adds object to the report. Warnings are added to the object until the next call or done.
+func (r *report) start(chart string, obj *unstructured.Unstructured, status string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = &reportObject{
+		Chart:      chart,
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Status:     status,
+	}
+	r.Objects = append(r.Objects, r.current)
+}
+
+// setProcessor - sets processor of the current object.
+func (r *report) setProcessor(p helmify.Processor) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current != nil {
+		r.current.Processor = strings.TrimPrefix(fmt.Sprintf("%T", p), "*")
+	}
+}
+
+// setResult - sets conversion result of the current object. Nil template means the object is skipped.
+func (r *report) setResult(template helmify.Template) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return
+	}
+	if template == nil {
+		r.current.Status = reportSkipped
+		return
+	}
+	r.current.Status = reportConverted
+	r.current.File = template.Filename()
+	r.current.Values = valuesKeys(template.Values(), "")
+}
+
+// done - finishes the current object.
+func (r *report) done() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = nil
+}
+
+// Levels - implements logrus.Hook.
+func (r *report) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+// Fire - implements logrus.Hook. Adds warning or error message with its fields to the current object or the report.
+func (r *report) Fire(entry *logrus.Entry) error {
+	msg := entry.Message
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		msg += fmt.Sprintf(" %s=%v", key, entry.Data[key])
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current != nil {
+		r.current.Warnings = append(r.current.Warnings, msg)
+	} else {
+		r.Warnings = append(r.Warnings, msg)
+	}
+	return nil
+}
+
+// write - writes the report into JSON file.
+func (r *report) write(file string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal report")
+	}
+	err = ioutil.WriteFile(file, append(res, '\n'), 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write report")
+	}
+	logrus.WithField("file", file).Info("report written")
+	return nil
+}
+
+// valuesKeys - returns sorted dot-separated keys of values leaves. Lists are leaves.
+func valuesKeys(values map[string]interface{}, prefix string) []string {
+	var res []string
+	for key, value := range values {
+		nested, ok := value.(map[string]interface{})
+		if v, isValues := value.(helmify.Values); isValues {
+			nested, ok = v, true
+		}
+		if ok && len(nested) != 0 {
+			res = append(res, valuesKeys(nested, prefix+key+".")...)
+			continue
+		}
+		res = append(res, prefix+key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// writerHook - writes entries up to the level to writer. Used when logger level is raised to collect warnings
+// into the report while logger output is discarded.
+type writerHook struct {
+	level  logrus.Level
+	writer io.Writer
+}
+
+// Levels - implements logrus.Hook.
+func (h *writerHook) Levels() []logrus.Level {
+	return logrus.AllLevels[:h.level+1]
+}
+
+// Fire - implements logrus.Hook.
+func (h *writerHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.Bytes()
+	if err != nil {
+		return err
+	}
+	_, err = h.writer.Write(line)
+	return err
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.json")
+	input := strings.Join([]string{strConfigMapA, strService, strUnknown, strStatefulSet}, "\n---\n")
+	err := Start(strings.NewReader(input), config.Config{ChartName: appChartName, ChartDir: dir, Report: file, SkipKinds: []string{"StatefulSet"}})
+	assert.NoError(t, err)
+	assert.Equal(t, logrus.WarnLevel, logrus.GetLevel(), "warnings are collected at default log level")
+
+	content, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	var res report
+	assert.NoError(t, json.Unmarshal(content, &res))
+	assert.Len(t, res.Objects, 4)
+	byName := map[string]*reportObject{}
+	for _, obj := range res.Objects {
+		byName[obj.Kind+"/"+obj.Name] = obj
+	}
+
+	cm := byName["ConfigMap/my-app-config-a"]
+	assert.Equal(t, reportConverted, cm.Status)
+	assert.Equal(t, "configmap.configMap", cm.Processor)
+	assert.Equal(t, "config-a.yaml", cm.File)
+	assert.Equal(t, []string{"configA.key"}, cm.Values)
+	assert.Empty(t, cm.Warnings)
+
+	unknown := byName["Unknown/my-app-unknown"]
+	assert.Equal(t, reportConverted, unknown.Status)
+	assert.Equal(t, "processor.dft", unknown.Processor)
+	assert.Len(t, unknown.Warnings, 1)
+	assert.Contains(t, unknown.Warnings[0], "Unsupported resource: using default processor.")
+
+	assert.Equal(t, reportFiltered, byName["StatefulSet/my-app-db"].Status)
+	assert.Equal(t, reportConverted, byName["Service/my-app-db"].Status)
+}
+
+func Test_valuesKeys(t *testing.T) {
+	values := helmify.Values{
+		"web": map[string]interface{}{
+			"image":     helmify.Values{"repository": "nginx", "tag": "1.21"},
+			"ports":     []interface{}{80},
+			"resources": map[string]interface{}{},
+		},
+		"replicaCount": 1,
+	}
+	assert.Equal(t, []string{"replicaCount", "web.image.repository", "web.image.tag", "web.ports", "web.resources"}, valuesKeys(values, ""))
+}
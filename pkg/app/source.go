@@ -0,0 +1,68 @@
+package app
+
+import (
+	"path"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/decoder"
+	"github.com/arttor/helmify/pkg/helmify"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// popSourceFile - removes source file annotation set by decoder from the object and returns its value.
+func popSourceFile(obj *unstructured.Unstructured) string {
+	annotations := obj.GetAnnotations()
+	file, ok := annotations[decoder.SourceFileAnnotation]
+	if !ok {
+		return ""
+	}
+	delete(annotations, decoder.SourceFileAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+	return file
+}
+
+// sourceFilename - returns template filename for the source file, e.g. 'web/app.yml' -> 'web/app.yaml'.
+func sourceFilename(file string) string {
+	return strings.TrimSuffix(file, path.Ext(file)) + ".yaml"
+}
+
+// withFilename - returns template written into the given file. Optional template interfaces are kept.
+func withFilename(template helmify.Template, filename string) helmify.Template {
+	switch t := template.(type) {
+	case helmify.SecretValuesTemplate:
+		return &secretValuesTemplateFile{SecretValuesTemplate: t, filename: filename}
+	case helmify.FilesTemplate:
+		return &filesTemplateFile{FilesTemplate: t, filename: filename}
+	}
+	return &templateFile{Template: template, filename: filename}
+}
+
+type templateFile struct {
+	helmify.Template
+	filename string
+}
+
+func (t *templateFile) Filename() string {
+	return t.filename
+}
+
+type filesTemplateFile struct {
+	helmify.FilesTemplate
+	filename string
+}
+
+func (t *filesTemplateFile) Filename() string {
+	return t.filename
+}
+
+type secretValuesTemplateFile struct {
+	helmify.SecretValuesTemplate
+	filename string
+}
+
+func (t *secretValuesTemplateFile) Filename() string {
+	return t.filename
+}
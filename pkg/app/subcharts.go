@@ -0,0 +1,81 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaultSubchartsLabel - label grouping resources into subcharts if not set in config.
+const defaultSubchartsLabel = "app.kubernetes.io/component"
+
+// defaultSubchartVersion - version of created Chart.yaml used in umbrella chart dependencies if chart version is not set in config.
+const defaultSubchartVersion = "0.1.0"
+
+// createSubcharts - creates umbrella chart with a subchart in 'charts' dir for every value of subcharts label.
+// Resources without the label are placed into the umbrella chart which declares subcharts as dependencies enabled
+// by '<component>.enabled' value. Every chart loads all resources to template names of resources from other charts.
+func createSubcharts(stop <-chan struct{}, objects <-chan *unstructured.Unstructured, conf config.Config, rep *report) (string, error) {
+	label := conf.SubchartsLabel
+	if label == "" {
+		label = defaultSubchartsLabel
+	}
+	var all []*unstructured.Unstructured
+	var components []string
+	seen := map[string]bool{}
+	for obj := range objects {
+		all = append(all, obj)
+		component := obj.GetLabels()[label]
+		if component != "" && !seen[component] {
+			seen[component] = true
+			components = append(components, component)
+		}
+	}
+	var summaries []string
+	for _, component := range components {
+		subConf := conf
+		subConf.ChartName = component
+		subConf.ChartDir = filepath.Join(conf.ChartDir, conf.ChartName, "charts")
+		subConf.ParentChartName = conf.ChartName
+		err := subConf.Validate()
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to create subchart for %s=%s", label, component)
+		}
+		summary, err := createComponentChart(stop, subConf, all, label, component, rep)
+		if err != nil {
+			return "", err
+		}
+		summaries = append(summaries, summary)
+		version := conf.ChartVersion
+		if version == "" {
+			version = defaultSubchartVersion
+		}
+		conf.Dependencies = append(conf.Dependencies, config.Dependency{Name: component, Version: version, Condition: component + ".enabled"})
+	}
+	summary, err := createComponentChart(stop, conf, all, label, "", rep)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append(summaries, summary), "\n"), nil
+}
+
+// createComponentChart - creates chart from resources with the given label value. Other resources are loaded as references.
+// Objects are copied as every chart loads them and processors may modify them. Returns summary prefixed with chart name.
+func createComponentChart(stop <-chan struct{}, conf config.Config, objects []*unstructured.Unstructured, label, component string, rep *report) (string, error) {
+	appCtx := newAppContext(conf).WithReport(rep)
+	for _, obj := range objects {
+		if obj.GetLabels()[label] == component {
+			appCtx.Add(obj.DeepCopy())
+		} else {
+			appCtx.AddReference(obj.DeepCopy())
+		}
+	}
+	err := appCtx.CreateHelm(stop)
+	if err != nil {
+		return "", err
+	}
+	return conf.ChartName + ": " + appCtx.Summary(), nil
+}
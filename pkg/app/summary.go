@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// summary counts processed objects by kind.
+type summary struct {
+	converted map[string]int
+	skipped   map[string]int
+}
+
+func newSummary() *summary {
+	return &summary{converted: map[string]int{}, skipped: map[string]int{}}
+}
+
+func (s *summary) addConverted(kind string) {
+	s.converted[kind]++
+}
+
+func (s *summary) addSkipped(kind string) {
+	s.skipped[kind]++
+}
+
+// String returns summary in form: "Converted: 2 ConfigMap, 1 Service; Skipped: 1 Namespace".
+func (s *summary) String() string {
+	res := "Converted: " + countsString(s.converted)
+	if len(s.skipped) != 0 {
+		res += "; Skipped: " + countsString(s.skipped)
+	}
+	return res
+}
+
+func countsString(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	res := make([]string, len(kinds))
+	for i, kind := range kinds {
+		res[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return strings.Join(res, ", ")
+}
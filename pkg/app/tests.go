@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// testConnectionTempl - 'helm test' Pod checking connection to chart Services. %[1]s - chart name, %[2]s - containers.
+const testConnectionTempl = `apiVersion: v1
+kind: Pod
+metadata:
+  name: "{{ include "%[1]s.fullname" . }}-test-connection"
+  labels:
+  {{- include "%[1]s.labels" . | nindent 4 }}
+  annotations:
+    "helm.sh/hook": test
+spec:
+  containers:%[2]s
+  restartPolicy: Never
+`
+
+// testContainerTempl - container checking a single Service port. HTTP ports are requested with wget,
+// other ports are checked for open TCP connection. %[1]s - container name, %[2]s - command, %[3]s - templated Service name,
+// %[4]s - Service values name, %[5]d - port index.
+const testContainerTempl = `
+  - name: %[1]s
+    image: busybox
+    command: [%[2]s]
+    args: ['%[3]s', '{{ (index .Values.%[4]s.ports %[5]d).port }}']`
+
+const (
+	testHTTPCommand = `'sh', '-c', 'wget -q -O /dev/null -T 5 http://$0:$1'`
+	testTCPCommand  = `'nc', '-z', '-w', '5'`
+)
+
+// tests - 'helm test' hook Pod checking TCP ports of chart Services.
+type tests struct {
+	content string
+}
+
+// newTests - returns test Pod template with a container for every TCP port of chart Services.
+// Returns nil if there are no such ports. Value names must match values of Service processor.
+func (c *appContext) newTests() helmify.Template {
+	var containers strings.Builder
+	names := map[string]bool{}
+	for _, obj := range c.objects {
+		if obj.GroupVersionKind() != notesServiceGVK || c.appMeta.IsDependency(obj.GetName()) {
+			continue
+		}
+		svc := corev1.Service{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &svc)
+		if err != nil || svc.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		name := c.appMeta.TrimName(obj.GetName())
+		valuesName := strcase.ToLowerCamel(strings.TrimPrefix(name, "controller-manager-"))
+		for i, p := range svc.Spec.Ports {
+			if p.Protocol != "" && p.Protocol != corev1.ProtocolTCP {
+				continue
+			}
+			containerName := uniqueContainerName(strcase.ToKebab(name+"-"+portName(p)), names)
+			command := testTCPCommand
+			if isHTTPPort(p) {
+				command = testHTTPCommand
+			}
+			containers.WriteString(fmt.Sprintf(testContainerTempl, containerName, command, c.appMeta.TemplatedName(obj.GetName()), valuesName, i))
+		}
+	}
+	if containers.Len() == 0 {
+		return nil
+	}
+	return &tests{content: fmt.Sprintf(testConnectionTempl, c.appMeta.ChartName(), containers.String())}
+}
+
+func portName(p corev1.ServicePort) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return fmt.Sprint(p.Port)
+}
+
+// isHTTPPort - returns true for ports with plain http application protocol, name or well-known port number.
+func isHTTPPort(p corev1.ServicePort) bool {
+	if p.AppProtocol != nil {
+		return *p.AppProtocol == "http"
+	}
+	return p.Name == "http" || strings.HasPrefix(p.Name, "http-") || p.Port == 80 || p.Port == 8080
+}
+
+// uniqueContainerName - returns name not used by previous containers, container names are DNS labels.
+func uniqueContainerName(name string, names map[string]bool) string {
+	if len(name) > 60 {
+		name = strings.TrimRight(name[:60], "-")
+	}
+	res := name
+	for i := 2; names[res]; i++ {
+		res = fmt.Sprintf("%s-%d", name, i)
+	}
+	names[res] = true
+	return res
+}
+
+func (t *tests) Filename() string {
+	return "tests/test-connection.yaml"
+}
+
+func (t *tests) Values() helmify.Values {
+	return helmify.Values{}
+}
+
+func (t *tests) Write(writer io.Writer) error {
+	_, err := io.WriteString(writer, t.content)
+	return err
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/processor/service"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const strWebService = `apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-web
+spec:
+  ports:
+  - name: http
+    port: 80
+  - name: dns
+    port: 53
+    protocol: UDP
+  - name: metrics
+    port: 9090
+  selector:
+    app: web`
+
+func TestAppContext_tests(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		output := &fakeOutput{}
+		ctx := New(config.Config{ChartName: "my-app"}, output).WithProcessors(service.New())
+		ctx.Add(internal.GenerateObj(strWebService))
+		assert.NoError(t, ctx.CreateHelm(nil))
+		for _, tpl := range output.templates {
+			assert.NotEqual(t, "tests/test-connection.yaml", tpl.Filename())
+		}
+	})
+	output := &fakeOutput{}
+	ctx := New(config.Config{ChartName: "my-app", TestHooks: true}, output).WithProcessors(service.New())
+	ctx.Add(internal.GenerateObj(strService))
+	ctx.Add(internal.GenerateObj(strWebService))
+	assert.NoError(t, ctx.CreateHelm(nil))
+	tests := output.templates[len(output.templates)-1]
+	assert.Equal(t, "tests/test-connection.yaml", tests.Filename())
+	buf := bytes.Buffer{}
+	assert.NoError(t, tests.Write(&buf))
+
+	values := map[string]interface{}{}
+	for _, tpl := range output.templates[:2] {
+		for k, v := range tpl.Values() {
+			values[k] = v
+		}
+	}
+	rendered, err := internal.RenderTemplate("my-app", buf.String(), values)
+	assert.NoError(t, err)
+	pod := corev1.Pod{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &pod))
+	assert.Equal(t, "release-test-connection", pod.Name)
+	assert.Equal(t, "test", pod.Annotations["helm.sh/hook"])
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Len(t, pod.Spec.Containers, 3)
+	assert.Equal(t, "db-5432", pod.Spec.Containers[0].Name)
+	assert.Equal(t, []string{"nc", "-z", "-w", "5"}, pod.Spec.Containers[0].Command)
+	assert.Equal(t, []string{"release-db", "5432"}, pod.Spec.Containers[0].Args)
+	assert.Equal(t, "web-http", pod.Spec.Containers[1].Name)
+	assert.Equal(t, "sh", pod.Spec.Containers[1].Command[0])
+	assert.Equal(t, []string{"release-web", "80"}, pod.Spec.Containers[1].Args)
+	assert.Equal(t, "web-metrics", pod.Spec.Containers[2].Name)
+	assert.Equal(t, []string{"release-web", "9090"}, pod.Spec.Containers[2].Args)
+}
@@ -0,0 +1,373 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// verifyFullname - chart fullname used to render the chart on verification.
+const verifyFullname = "verify"
+
+// verifyIgnored - fields set by the cluster and namespace replaced by release namespace, not expected in the chart.
+var verifyIgnored = map[string]bool{
+	"status":                     true,
+	"metadata.creationTimestamp": true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.namespace":         true,
+	"metadata.resourceVersion":   true,
+	"metadata.selfLink":          true,
+	"metadata.uid":               true,
+}
+
+// drift - difference between source resource and the resource rendered from the chart.
+type drift struct {
+	resource, field, reason string
+}
+
+// verify - renders the chart with its default values and reports converted source resource fields which are lost or changed
+// in rendered resources. Resource names are expected to be prefixed with chart fullname. Secret data is verified only
+// if Secret values are written into values files. Changes made on purpose by config options are not reported.
+// Returns error if there is a drift.
+func (c *appContext) verify(sources []*unstructured.Unstructured) error {
+	chartDir := filepath.Join(c.config.ChartDir, c.config.ChartName)
+	rendered, err := c.renderChart(chartDir)
+	if err != nil {
+		return err
+	}
+	var drifts []drift
+	for _, src := range sources {
+		if c.config.GenWebhookCerts && src.GroupVersionKind().Group == "cert-manager.io" {
+			// replaced by TLS Secret generated on install
+			continue
+		}
+		resource := src.GetKind() + "/" + src.GetName()
+		obj, ok := findRendered(rendered, src.GroupVersionKind().GroupKind().String(), src.GetName(), c.expectedName(src.GetName()))
+		if !ok {
+			drifts = append(drifts, drift{resource: resource, reason: "resource is not rendered"})
+			continue
+		}
+		srcObj, renderedObj := normalize(src.Object), normalize(obj)
+		if c.config.GenWebhookCerts {
+			// CA is generated on install instead of being injected by cert-manager
+			unstructured.RemoveNestedField(srcObj, "metadata", "annotations", processor.InjectCAAnnotation)
+		}
+		if src.GetKind() == "Secret" {
+			if !c.config.SecretValues && c.config.SecretValuesFile == "" {
+				delete(srcObj, "data")
+				delete(srcObj, "stringData")
+			}
+			decodeSecretData(srcObj)
+			decodeSecretData(renderedObj)
+		}
+		drifts = append(drifts, c.compare(resource, "", srcObj, renderedObj)...)
+	}
+	for _, d := range drifts {
+		entry := logrus.WithField("source", d.resource)
+		if d.field != "" {
+			entry = entry.WithField("field", d.field)
+		}
+		entry.Error(d.reason)
+	}
+	if len(drifts) != 0 {
+		return errors.Errorf("chart %s verification found %d differences from source resources", chartDir, len(drifts))
+	}
+	logrus.WithField("chart", chartDir).Info("chart verified: rendered resources match source resources")
+	return nil
+}
+
+// renderChart - renders chart templates and CRDs with default values and secret values file.
+// Returns rendered resources by group kind and name.
+func (c *appContext) renderChart(chartDir string) (map[string]map[string]interface{}, error) {
+	chrt, err := loader.Load(chartDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load chart")
+	}
+	values := chrt.Values
+	if c.config.SecretValuesFile != "" {
+		secretValues, err := chartutil.ReadValuesFile(filepath.Join(chartDir, c.config.SecretValuesFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read secret values")
+		}
+		values = chartutil.CoalesceTables(secretValues.AsMap(), values)
+	}
+	values = chartutil.CoalesceTables(map[string]interface{}{"fullnameOverride": verifyFullname}, values)
+	namespace := c.appMeta.Namespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	renderValues, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{Name: "release", Namespace: namespace, IsInstall: true}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to prepare values")
+	}
+	// missing required values, e.g. Secret data, are rendered empty
+	out, err := engine.Engine{LintMode: true}.Render(chrt, renderValues)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to render chart")
+	}
+	var manifests []string
+	for file, content := range out {
+		if strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml") {
+			manifests = append(manifests, content)
+		}
+	}
+	for _, crd := range chrt.CRDObjects() {
+		manifests = append(manifests, string(crd.File.Data))
+	}
+	res := map[string]map[string]interface{}{}
+	for _, manifest := range manifests {
+		for _, doc := range releaseutil.SplitManifests(manifest) {
+			obj := map[string]interface{}{}
+			err = yaml.Unmarshal([]byte(doc), &obj)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to parse rendered chart")
+			}
+			if len(obj) == 0 {
+				continue
+			}
+			u := unstructured.Unstructured{Object: obj}
+			res[u.GroupVersionKind().GroupKind().String()+"/"+u.GetName()] = obj
+		}
+	}
+	return res, nil
+}
+
+// findRendered - returns rendered resource with either expected, original or chart fullname, e.g. for CRDs
+// keeping their names or a single ServiceAccount named as chart.
+func findRendered(rendered map[string]map[string]interface{}, groupKind string, names ...string) (map[string]interface{}, bool) {
+	for _, name := range append(names, verifyFullname) {
+		if obj, ok := rendered[groupKind+"/"+name]; ok {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// expectedName - returns name of source resource rendered with verification fullname.
+func (c *appContext) expectedName(name string) string {
+	return verifyFullname + "-" + c.appMeta.TrimName(name)
+}
+
+// compare - returns fields of source value lost or changed in rendered value. Empty source values are ignored.
+func (c *appContext) compare(resource, path string, src, rendered interface{}) []drift {
+	if verifyIgnored[path] || isEmpty(src) {
+		return nil
+	}
+	if rendered == nil {
+		return []drift{{resource: resource, field: path, reason: "field is lost"}}
+	}
+	switch s := src.(type) {
+	case map[string]interface{}:
+		r, ok := rendered.(map[string]interface{})
+		if !ok {
+			return []drift{{resource: resource, field: path, reason: "object is rendered as " + fmt.Sprint(rendered)}}
+		}
+		keys := make([]string, 0, len(s))
+		for k := range s {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var res []drift
+		for _, k := range keys {
+			res = append(res, c.compare(resource, joinPath(path, k), s[k], r[k])...)
+		}
+		return res
+	case []interface{}:
+		r, ok := rendered.([]interface{})
+		if !ok {
+			return []drift{{resource: resource, field: path, reason: fmt.Sprintf("list is rendered as %v", rendered)}}
+		}
+		if byName := itemsByName(r); byName != nil && itemsByName(s) != nil {
+			// named items, e.g. env vars, may be added by the chart
+			var res []drift
+			for _, item := range s {
+				name := item.(map[string]interface{})["name"].(string)
+				res = append(res, c.compare(resource, fmt.Sprintf("%s[%s]", path, name), item, c.itemByName(byName, name))...)
+			}
+			return res
+		}
+		if len(r) != len(s) {
+			return []drift{{resource: resource, field: path, reason: fmt.Sprintf("list is rendered as %v", rendered)}}
+		}
+		var res []drift
+		for i := range s {
+			res = append(res, c.compare(resource, fmt.Sprintf("%s[%d]", path, i), s[i], r[i])...)
+		}
+		return res
+	case string:
+		if r, ok := rendered.(string); ok && (c.sameString(s, r) || sameYAML(s, r) || sameProperties(s, r)) {
+			return nil
+		}
+	default:
+		if rendered == src {
+			return nil
+		}
+	}
+	if isScalar(rendered) && fmt.Sprint(src) == fmt.Sprint(rendered) {
+		// scalar types may differ, e.g. quoted numbers
+		return nil
+	}
+	return []drift{{resource: resource, field: path, reason: fmt.Sprintf("value %v is rendered as %v", src, rendered)}}
+}
+
+// sameString - returns true if strings are equal, rendered string may contain chart resource names prefixed with
+// chart fullname, e.g. '<namespace>/<name>' references. Single ServiceAccount is named as chart.
+func (c *appContext) sameString(src, rendered string) bool {
+	srcParts, renderedParts := strings.Split(src, "/"), strings.Split(rendered, "/")
+	if len(srcParts) != len(renderedParts) {
+		return false
+	}
+	for i := range srcParts {
+		if srcParts[i] != renderedParts[i] && renderedParts[i] != c.expectedName(srcParts[i]) && renderedParts[i] != verifyFullname {
+			return false
+		}
+	}
+	return true
+}
+
+// sameYAML - returns true if strings are equal YAML documents, e.g. ConfigMap files with changed quoting.
+func sameYAML(src, rendered string) bool {
+	var srcValue, renderedValue interface{}
+	if yaml.Unmarshal([]byte(src), &srcValue) != nil || yaml.Unmarshal([]byte(rendered), &renderedValue) != nil {
+		return false
+	}
+	switch srcValue.(type) {
+	case map[string]interface{}, []interface{}:
+		return reflect.DeepEqual(srcValue, renderedValue)
+	}
+	return false
+}
+
+// sameProperties - returns true if strings are equal properties files, rendered property values may be quoted.
+func sameProperties(src, rendered string) bool {
+	srcLines := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	renderedLines := strings.Split(strings.TrimSuffix(rendered, "\n"), "\n")
+	if len(srcLines) != len(renderedLines) {
+		return false
+	}
+	for i := range srcLines {
+		srcKey, srcValue, ok := strings.Cut(srcLines[i], "=")
+		renderedKey, renderedValue, renderedOk := strings.Cut(renderedLines[i], "=")
+		if !ok || !renderedOk || srcKey != renderedKey {
+			return false
+		}
+		if unquoted, err := strconv.Unquote(renderedValue); err == nil && !strings.HasPrefix(srcValue, `"`) {
+			renderedValue = unquoted
+		}
+		if srcValue != renderedValue {
+			return false
+		}
+	}
+	return true
+}
+
+// itemByName - returns item with original, expected or chart fullname.
+func (c *appContext) itemByName(items map[string]interface{}, name string) interface{} {
+	for _, n := range []string{name, c.expectedName(name), verifyFullname} {
+		if item, ok := items[n]; ok {
+			return item
+		}
+	}
+	return nil
+}
+
+// itemsByName - returns list items by name if all items are objects with unique names.
+func itemsByName(list []interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		name, ok := m["name"].(string)
+		if _, duplicate := res[name]; !ok || duplicate {
+			return nil
+		}
+		res[name] = item
+	}
+	return res
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, float64:
+		return true
+	}
+	return false
+}
+
+// decodeSecretData - replaces base64 Secret data with decoded content and merges stringData into data,
+// so re-encoded or wrapped data is not reported as changed.
+func decodeSecretData(obj map[string]interface{}) {
+	data, ok := obj["data"].(map[string]interface{})
+	if !ok {
+		data = map[string]interface{}{}
+	}
+	for k, v := range data {
+		encoded, ok := v.(string)
+		if !ok {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+		if err == nil {
+			data[k] = string(decoded)
+		}
+	}
+	stringData, _ := obj["stringData"].(map[string]interface{})
+	for k, v := range stringData {
+		data[k] = v
+	}
+	delete(obj, "stringData")
+	if len(data) != 0 {
+		obj["data"] = data
+	}
+}
+
+// normalize - returns JSON copy of the object, so source and rendered numbers have the same type.
+func normalize(obj map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(helmify.RestoreValues(obj))
+	if err != nil {
+		return obj
+	}
+	res := map[string]interface{}{}
+	if err = json.Unmarshal(data, &res); err != nil {
+		return obj
+	}
+	return res
+}
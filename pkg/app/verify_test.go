@@ -0,0 +1,103 @@
+package app
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_appContext_compare(t *testing.T) {
+	c := New(config.Config{ChartName: "chart"}, nil)
+	for _, name := range []string{"myapp-web", "myapp-config"} {
+		c.Add(internal.GenerateObj("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name))
+	}
+	src := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "myapp-web", "namespace": "ns", "uid": "1"},
+		"spec": map[string]interface{}{
+			"replicas": 3.0,
+			"env": []interface{}{
+				map[string]interface{}{"name": "A", "value": "a"},
+			},
+			"config":   "myapp-config",
+			"file":     "key: value\n",
+			"props":    "a.b=8081\nc=x\n",
+			"port":     "8081",
+			"selector": map[string]interface{}{},
+			"lost":     "value",
+		},
+	}
+	rendered := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "verify-web"},
+		"spec": map[string]interface{}{
+			"replicas": 1.0,
+			"env": []interface{}{
+				map[string]interface{}{"name": "B", "value": "b"},
+				map[string]interface{}{"name": "A", "value": "a"},
+			},
+			"config": "verify-config",
+			"file":   `key: "value"`,
+			"props":  "a.b=\"8081\"\nc=\"x\"\n",
+			"port":   8081.0,
+		},
+	}
+	drifts := c.compare("Deployment/myapp-web", "", src, rendered)
+	var fields []string
+	for _, d := range drifts {
+		fields = append(fields, d.field)
+	}
+	assert.Equal(t, []string{"spec.lost", "spec.replicas"}, fields)
+}
+
+func TestVerify(t *testing.T) {
+	err := Start(strings.NewReader(strComponents), config.Config{ChartName: appChartName, ChartDir: t.TempDir(), Verify: true})
+	assert.NoError(t, err)
+
+	const changed = strComponents + `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myapp-frontend
+spec:
+  selector:
+    app: frontend
+  ports:
+  - port: 80
+  sessionAffinityConfig:
+    clientIP:
+      timeoutSeconds: 60
+`
+	err = Start(strings.NewReader(changed), config.Config{ChartName: appChartName, ChartDir: t.TempDir(), Verify: true})
+	assert.Error(t, err)
+}
+
+func Test_decodeSecretData(t *testing.T) {
+	src := map[string]interface{}{"data": map[string]interface{}{"a": "aGVsbG8gd29ybGQ="}, "stringData": map[string]interface{}{"b": "x"}}
+	rendered := map[string]interface{}{"data": map[string]interface{}{"a": "aGVsbG8g\nd29ybGQ=", "b": "eA=="}}
+	decodeSecretData(src)
+	decodeSecretData(rendered)
+	assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{"a": "hello world", "b": "x"}}, src)
+	assert.Equal(t, src, rendered)
+}
+
+func TestVerify_sampleApp(t *testing.T) {
+	for _, conf := range []config.Config{{}, {SecretValues: true}} {
+		file, err := os.Open("../../test_data/sample-app.yaml")
+		assert.NoError(t, err)
+		conf.ChartName, conf.ChartDir, conf.Verify = appChartName, t.TempDir(), true
+		assert.NoError(t, Start(bufio.NewReader(file), conf))
+		file.Close()
+	}
+}
+
+func TestVerify_genWebhookCerts(t *testing.T) {
+	file, err := os.Open("../../test_data/k8s-operator-kustomize.output")
+	assert.NoError(t, err)
+	defer file.Close()
+	err = Start(bufio.NewReader(file), config.Config{ChartName: operatorChartName, ChartDir: t.TempDir(), Verify: true, GenWebhookCerts: true})
+	assert.NoError(t, err)
+}
@@ -1,26 +1,162 @@
 package config
 
 import (
+	"path/filepath"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // defaultChartName - default name for a helm chart directory.
 const defaultChartName = "chart"
 
+// Namespace modes.
+const (
+	// NamespaceDrop - namespaces are removed from resources metadata. References to the app namespace are
+	// replaced with the release namespace. Default mode.
+	NamespaceDrop = "drop"
+	// NamespaceKeep - source namespaces of resources and references are kept as is.
+	NamespaceKeep = "keep"
+	// NamespaceRelease - namespaces of resources and all namespace references are replaced with the release namespace.
+	NamespaceRelease = "release"
+)
+
 // Config for Helmify application.
 type Config struct {
 	// ChartName name of the Helm chart and its base directory where Chart.yaml is located.
 	ChartName string
 	// ChartDir - optional path to chart dir. Full chart path will be: ChartDir/ChartName/Chart.yaml.
 	ChartDir string
+	// ChartVersion optional Chart.yaml version. Chart.yaml fields below are updated on every run if set.
+	ChartVersion string
+	// ChartAppVersion optional Chart.yaml appVersion.
+	ChartAppVersion string
+	// ChartDescription optional Chart.yaml description.
+	ChartDescription string
+	// ChartHome optional Chart.yaml home URL.
+	ChartHome string
+	// ChartIcon optional Chart.yaml icon URL.
+	ChartIcon string
+	// ChartKeywords optional Chart.yaml keywords.
+	ChartKeywords []string
+	// ChartMaintainers optional Chart.yaml maintainers in form 'name <email> (url)' with optional email and url.
+	ChartMaintainers []string
+	// ChartAnnotations optional Chart.yaml annotations.
+	ChartAnnotations map[string]string
+	// Dependencies optional Chart.yaml dependencies. Resources with names matching dependency name are not converted.
+	Dependencies []Dependency
 	// Verbose set true to see WARN and INFO logs.
 	Verbose bool
 	// VeryVerbose set true to see WARN, INFO, and DEBUG logs.
 	VeryVerbose bool
 	// crd-dir set true to enable crd folder.
 	Crd bool
+	// RestartAnnotation set true to add templated 'kubectl.kubernetes.io/restartedAt' pod annotation to workloads.
+	RestartAnnotation bool
+	// ReplicaCount set true to use top-level 'replicaCount' value for workload replicas. Intended for single-workload charts.
+	ReplicaCount bool
+	// ValuesFile optional name of an additional values file with the same content as values.yaml, e.g. values.default.yaml.
+	ValuesFile string
+	// FilesGet list of ConfigMap data keys in form '<configmap name>/<key>' moved to chart files and read with '.Files.Get'.
+	FilesGet []string
+	// FilesGetSize ConfigMap data keys with content larger than the given number of bytes are moved to chart files
+	// and read with '.Files.Get'. Disabled if not positive.
+	FilesGetSize int
+	// CRImageFields list of dot-separated field paths, e.g. 'spec.image', moved to values for custom resources without dedicated processor.
+	CRImageFields []string
+	// CRValues set true to move scalar spec fields of custom resources to values.
+	CRValues bool
+	// ConfigMapTypes set true to store numeric and boolean looking ConfigMap data as typed values instead of quoted strings.
+	ConfigMapTypes bool
+	// JobHooks set true to annotate Jobs as Helm pre-install and pre-upgrade hooks.
+	JobHooks bool
+	// SchedulingValues set true to move workloads nodeSelector, tolerations, affinity, runtimeClassName and schedulerName to values.
+	SchedulingValues bool
+	// EnvValues set true to move containers env vars with plain values to values.
+	EnvValues bool
+	// CommandValues set true to move containers command and args to values.
+	CommandValues bool
+	// ConfigChecksum set true to add checksum pod annotations of chart ConfigMaps and Secrets used by workloads.
+	ConfigChecksum bool
+	// SecretValues set true to use decoded Secret data as default values instead of empty required values.
+	SecretValues bool
+	// SecretValuesFile optional name of a values file for decoded Secret data. values.yaml keeps empty required values then.
+	SecretValuesFile string
+	// ExistingSecrets set true to skip chart Secrets if <name>.existingSecret value is set and reference the existing Secret instead.
+	ExistingSecrets bool
+	// RawConfigMaps list of ConfigMap names with data keys stored in values as is and rendered with 'tpl'. '*' matches all ConfigMaps.
+	RawConfigMaps []string
+	// ValuesSchema set true to generate values.schema.json from types of values.
+	ValuesSchema bool
+	// ValuesReadme set true to generate chart README.md with a table of values keys, defaults and templates using them.
+	ValuesReadme bool
+	// Subcharts set true to create umbrella chart with subcharts grouping resources by SubchartsLabel value.
+	Subcharts bool
+	// SubchartsLabel optional label grouping resources into subcharts. Default is 'app.kubernetes.io/component'.
+	SubchartsLabel string
+	// ParentChartName name of umbrella chart. Set for subcharts only: resource names are prefixed with
+	// umbrella chart fullname to be the same in all subcharts.
+	ParentChartName string
+	// LibraryChart optional name of library chart with shared helpers created in chart 'charts' dir.
+	// Chart helpers delegate to the library, images and securityContexts are rendered with library helpers.
+	LibraryChart string
+	// CIValues set true to generate 'ci/default-values.yaml' with placeholders for required values used by chart-testing.
+	CIValues bool
+	// Lint set true to run 'helm lint' on the created chart and fail on lint errors.
+	Lint bool
+	// Verify set true to render the created chart with default values and report differences from source resources.
+	Verify bool
+	// Update set true to keep templates and values defaults modified since previous run on existing chart update.
+	Update bool
+	// Diff set true to write unified diff of changes to the existing chart to stdout instead of changing the chart.
+	Diff bool
+	// Package set true to package the created chart into '<chart name>-<version>.tgz' archive in ChartDir.
+	Package bool
+	// Push optional OCI registry repository, e.g. 'oci://registry.example.com/charts', to push the packaged chart to.
+	// Registry credentials are read from docker config.
+	Push string
+	// PushPlainHTTP set true to push to OCI registry over plain HTTP.
+	PushPlainHTTP bool
+	// Files optional input files, directories read recursively, glob patterns like 'deploy/**/*.yaml' or http(s) URLs.
+	// Manifests are read from stdin if empty.
+	Files []string
+	// SourceFilenames set true to name templates after input files instead of resource names.
+	SourceFilenames bool
+	// Kustomize optional kustomization directory built as input instead of stdin.
+	Kustomize string
+	// Release optional name of installed Helm release. Manifests and hooks of the release are used as input
+	// instead of stdin.
+	Release string
+	// NamePrefix optional prefix of all resource names, e.g. kustomization namePrefix. Trimmed from template names
+	// instead of detected common prefix.
+	NamePrefix string
+	// NameSuffix optional suffix of all resource names, e.g. kustomization nameSuffix. Trimmed from template names.
+	NameSuffix string
+	// SkipKinds list of input resource kinds dropped before conversion, e.g. 'Namespace'.
+	SkipKinds []string
+	// SkipNames list of input resource names or glob patterns, e.g. 'test-*', dropped before conversion.
+	SkipNames []string
+	// SkipNamespaces list of namespaces with input resources dropped before conversion.
+	SkipNamespaces []string
+	// Only optional label selector, e.g. 'app=backend'. Input resources not matching the selector are dropped before conversion.
+	Only string
+	// DryRun set true to write all chart files to stdout instead of the chart directory.
+	DryRun bool
+	// Report optional file the JSON conversion report is written into. The report lists every input object with
+	// its processor, template file, values keys and warnings.
+	Report string
+	// LogFormat log format: 'text' or 'json'. Default is 'text'.
+	LogFormat string
+	// NamespaceMode namespace handling: NamespaceDrop, NamespaceKeep or NamespaceRelease. Default is NamespaceDrop.
+	NamespaceMode string
+	// TestHooks set true to generate 'helm test' Pod checking connection to chart Services.
+	TestHooks bool
+	// GenWebhookCerts set true to generate webhook certificates on install with Helm instead of cert-manager.
+	GenWebhookCerts bool
+	// NoValues set true to inline all values into templates and produce a chart with empty values.yaml.
+	NoValues bool
 }
 
 func (c *Config) Validate() error {
@@ -35,5 +171,44 @@ func (c *Config) Validate() error {
 		}
 		return errors.Errorf("Invalid chart name %s", c.ChartName)
 	}
+	if c.ValuesFile != "" && filepath.Base(c.ValuesFile) != c.ValuesFile {
+		return errors.Errorf("Invalid values file name %s: must be a file name without directory", c.ValuesFile)
+	}
+	if c.SecretValuesFile != "" && filepath.Base(c.SecretValuesFile) != c.SecretValuesFile {
+		return errors.Errorf("Invalid secret values file name %s: must be a file name without directory", c.SecretValuesFile)
+	}
+	if c.SecretValuesFile == "values.yaml" || (c.SecretValuesFile != "" && c.SecretValuesFile == c.ValuesFile) {
+		return errors.Errorf("Invalid secret values file name %s: must differ from values files", c.SecretValuesFile)
+	}
+	if c.LogFormat != "" && c.LogFormat != "text" && c.LogFormat != "json" {
+		return errors.Errorf("Invalid log format %s: must be 'text' or 'json'", c.LogFormat)
+	}
+	switch c.NamespaceMode {
+	case "", NamespaceDrop, NamespaceKeep, NamespaceRelease:
+	default:
+		return errors.Errorf("Invalid namespace mode %s: must be one of %s, %s, %s", c.NamespaceMode, NamespaceKeep, NamespaceDrop, NamespaceRelease)
+	}
+	if c.Diff && c.DryRun {
+		return errors.New("Invalid output: diff and dry-run can not be used together")
+	}
+	inputs := 0
+	for _, set := range []bool{len(c.Files) != 0, c.Kustomize != "", c.Release != ""} {
+		if set {
+			inputs++
+		}
+	}
+	if inputs > 1 {
+		return errors.New("Invalid input: only one of files, kustomization or release can be used")
+	}
+	if c.Only != "" {
+		if _, err := labels.Parse(c.Only); err != nil {
+			return errors.Wrapf(err, "Invalid label selector %s", c.Only)
+		}
+	}
+	if c.LibraryChart != "" {
+		if errs := validation.IsDNS1123Subdomain(c.LibraryChart); len(errs) != 0 || c.LibraryChart == c.ChartName {
+			return errors.Errorf("Invalid library chart name %s: must be a valid chart name different from chart name", c.LibraryChart)
+		}
+	}
 	return nil
 }
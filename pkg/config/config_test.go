@@ -41,6 +41,34 @@ func TestConfig_Validate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, defaultChartName, c.ChartName)
 	})
+	t.Run("values file name", func(t *testing.T) {
+		c := &Config{ValuesFile: "values.default.yaml"}
+		assert.NoError(t, c.Validate())
+		c = &Config{ValuesFile: "../values.yaml"}
+		assert.Error(t, c.Validate())
+	})
+	t.Run("secret values file name", func(t *testing.T) {
+		c := &Config{SecretValuesFile: "secret-values.yaml"}
+		assert.NoError(t, c.Validate())
+		c = &Config{SecretValuesFile: "values.yaml"}
+		assert.Error(t, c.Validate())
+		c = &Config{SecretValuesFile: "values.default.yaml", ValuesFile: "values.default.yaml"}
+		assert.Error(t, c.Validate())
+	})
+	t.Run("inputs", func(t *testing.T) {
+		c := &Config{Kustomize: "overlays/prod"}
+		assert.NoError(t, c.Validate())
+		c = &Config{Kustomize: "overlays/prod", Files: []string{"deploy"}}
+		assert.Error(t, c.Validate())
+		c = &Config{Release: "my-release", Files: []string{"deploy"}}
+		assert.Error(t, c.Validate())
+	})
+	t.Run("label selector", func(t *testing.T) {
+		c := &Config{Only: "app in (backend,frontend),tier!=cache"}
+		assert.NoError(t, c.Validate())
+		c = &Config{Only: "app in backend"}
+		assert.Error(t, c.Validate())
+	})
 	t.Run("chart name set", func(t *testing.T) {
 		c := &Config{ChartName: "test"}
 		err := c.Validate()
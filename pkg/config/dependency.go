@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dependency - chart dependency. Chart resources with names matching dependency name are replaced by the subchart.
+type Dependency struct {
+	// Name of the subchart.
+	Name string
+	// Version constraint, e.g. '12.x'.
+	Version string
+	// Repository URL or '@<alias>' of repository added with 'helm repo add'. Empty for subcharts in 'charts' dir.
+	Repository string
+	// Condition optional value path enabling the subchart, e.g. 'postgresql.enabled'.
+	Condition string
+}
+
+// ParseDependency - parses dependency in form '<repository>/<name>:<version>[:<condition>]'.
+// Repository is either URL, e.g. 'https://charts.bitnami.com/bitnami' or 'oci://registry/charts', or repository alias, e.g. 'bitnami'.
+func ParseDependency(dep string) (Dependency, error) {
+	scheme := ""
+	if i := strings.Index(dep, "://"); i >= 0 {
+		scheme, dep = dep[:i+3], dep[i+3:]
+	}
+	parts := strings.Split(dep, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] == "" {
+		return Dependency{}, errors.Errorf("invalid dependency %s: must be in form '<repository>/<name>:<version>[:<condition>]'", scheme+dep)
+	}
+	slash := strings.LastIndex(parts[0], "/")
+	if slash <= 0 || slash == len(parts[0])-1 {
+		return Dependency{}, errors.Errorf("invalid dependency %s: must be in form '<repository>/<name>:<version>[:<condition>]'", scheme+dep)
+	}
+	res := Dependency{Name: parts[0][slash+1:], Version: parts[1], Repository: scheme + parts[0][:slash]}
+	if scheme == "" {
+		res.Repository = "@" + res.Repository
+	}
+	if len(parts) == 3 {
+		res.Condition = parts[2]
+	}
+	return res, nil
+}
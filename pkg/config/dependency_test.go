@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDependency(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Dependency
+		wantErr bool
+	}{
+		{in: "bitnami/postgresql:12.x:postgresql.enabled", want: Dependency{Name: "postgresql", Version: "12.x", Repository: "@bitnami", Condition: "postgresql.enabled"}},
+		{in: "bitnami/redis:17.3.2", want: Dependency{Name: "redis", Version: "17.3.2", Repository: "@bitnami"}},
+		{in: "https://charts.bitnami.com/bitnami/postgresql:12.x:postgresql.enabled", want: Dependency{Name: "postgresql", Version: "12.x", Repository: "https://charts.bitnami.com/bitnami", Condition: "postgresql.enabled"}},
+		{in: "oci://registry-1.docker.io/bitnamicharts/redis:18.x", want: Dependency{Name: "redis", Version: "18.x", Repository: "oci://registry-1.docker.io/bitnamicharts"}},
+		{in: "postgresql:12.x", wantErr: true},
+		{in: "bitnami/postgresql", wantErr: true},
+		{in: "bitnami/:12.x", wantErr: true},
+		{in: "bitnami/postgresql:12.x:a:b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDependency(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
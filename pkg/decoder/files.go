@@ -0,0 +1,192 @@
+package decoder
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SourceFileAnnotation - annotation set on objects decoded from files. Contains slash-separated path of the source
+// file relative to the input directory or glob pattern base directory.
+const SourceFileAnnotation = "helmify.arttor.github.io/source-file"
+
+// manifestExtensions - extensions of files read from input directories.
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// sourceFile - input file path and its name relative to the input path. Content is set for downloaded files.
+type sourceFile struct {
+	path    string
+	name    string
+	content []byte
+}
+
+// DecodeFiles - reads k8s yaml manifests from files and decodes them to k8s unstructured objects annotated
+// with SourceFileAnnotation. Paths are files, directories read recursively, glob patterns where '**' matches
+// any number of directories, e.g. 'deploy/**/*.yaml', or http(s) URLs downloaded before decoding.
+// Non-blocking function. Sends results into buffered channel. Closes channel when all files are read.
+func DecodeFiles(stop <-chan struct{}, paths []string) (<-chan *unstructured.Unstructured, error) {
+	var files []sourceFile
+	for _, path := range paths {
+		found, err := findFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	res := make(chan *unstructured.Unstructured, decoderResultChannelBufferSize)
+	go func() {
+		defer close(res)
+		logrus.Debug("Start processing...")
+		for _, file := range files {
+			logrus.WithField("file", file.path).Debug("reading")
+			if file.content != nil {
+				if !decode(stop, bytes.NewReader(file.content), file.name, res) {
+					return
+				}
+				continue
+			}
+			f, err := os.Open(file.path)
+			if err != nil {
+				logrus.WithError(err).Error("unable to read input file")
+				continue
+			}
+			ok := decode(stop, f, file.name, res)
+			_ = f.Close()
+			if !ok {
+				return
+			}
+		}
+	}()
+	return res, nil
+}
+
+// findFiles - returns files matching input path in lexical order.
+func findFiles(path string) ([]sourceFile, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		file, err := download(path)
+		if err != nil {
+			return nil, err
+		}
+		return []sourceFile{file}, nil
+	}
+	if isGlob(path) {
+		return findGlob(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read input")
+	}
+	if !info.IsDir() {
+		return []sourceFile{{path: path, name: filepath.Base(path)}}, nil
+	}
+	return walkFiles(path, func(name string) bool {
+		return manifestExtensions[strings.ToLower(filepath.Ext(name))]
+	})
+}
+
+// findGlob - returns files matching glob pattern. File names are relative to the pattern base directory.
+func findGlob(pattern string) ([]sourceFile, error) {
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+	i := 0
+	for i < len(parts)-1 && !isGlob(parts[i]) {
+		i++
+	}
+	base := filepath.FromSlash(strings.Join(parts[:i], "/"))
+	if base == "" {
+		base = "."
+	}
+	if strings.HasPrefix(pattern, "/") && i == 1 {
+		base = "/"
+	}
+	files, err := walkFiles(base, func(name string) bool {
+		return matchGlob(parts[i:], strings.Split(name, "/"))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no files match input %s", pattern)
+	}
+	return files, nil
+}
+
+// walkFiles - returns files of the directory tree with slash-separated relative names accepted by match func.
+func walkFiles(dir string, match func(name string) bool) ([]sourceFile, error) {
+	var files []sourceFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if match(name) {
+			files = append(files, sourceFile{path: path, name: name})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read input dir")
+	}
+	return files, nil
+}
+
+// download - returns file downloaded from the URL. File name is the last element of URL path.
+func download(rawURL string) (sourceFile, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return sourceFile{}, errors.Wrap(err, "invalid input URL")
+	}
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		return sourceFile{}, errors.Wrapf(err, "unable to download %s", rawURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return sourceFile{}, errors.Errorf("unable to download %s: %s", rawURL, resp.Status)
+	}
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return sourceFile{}, errors.Wrapf(err, "unable to download %s", rawURL)
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = u.Host + ".yaml"
+	}
+	return sourceFile{path: rawURL, name: name, content: content}, nil
+}
+
+// matchGlob - returns true if path segments match pattern segments. '**' segment matches any number of segments.
+func matchGlob(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchGlob(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	ok, err := filepath.Match(pattern[0], path[0])
+	return err == nil && ok && matchGlob(pattern[1:], path[1:])
+}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
@@ -0,0 +1,70 @@
+package decoder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "a", "b"), 0750))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "root.yaml"), []byte(validObjects2), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a", "b", "nested.yml"), []byte(validObjects2), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a", "notes.txt"), []byte(validObjects2), 0600))
+
+	decodeSources := func(t *testing.T, paths ...string) []string {
+		objects, err := DecodeFiles(make(chan struct{}), paths)
+		assert.NoError(t, err)
+		var sources []string
+		for obj := range objects {
+			sources = append(sources, obj.GetAnnotations()[SourceFileAnnotation])
+		}
+		return sources
+	}
+	t.Run("dir", func(t *testing.T) {
+		assert.Equal(t, []string{"a/b/nested.yml", "a/b/nested.yml", "root.yaml", "root.yaml"}, decodeSources(t, dir))
+	})
+	t.Run("file", func(t *testing.T) {
+		assert.Equal(t, []string{"notes.txt", "notes.txt"}, decodeSources(t, filepath.Join(dir, "a", "notes.txt")))
+	})
+	t.Run("glob", func(t *testing.T) {
+		assert.Equal(t, []string{"a/b/nested.yml", "a/b/nested.yml"}, decodeSources(t, filepath.Join(dir, "**", "*.yml")))
+		assert.Equal(t, []string{"b/nested.yml", "b/nested.yml"}, decodeSources(t, filepath.Join(dir, "a", "**", "*.yml")))
+		assert.Equal(t, []string{"root.yaml", "root.yaml"}, decodeSources(t, filepath.Join(dir, "*.yaml")))
+	})
+	t.Run("url", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/manifests/app.yaml" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(validObjects2))
+		}))
+		defer server.Close()
+		assert.Equal(t, []string{"app.yaml", "app.yaml"}, decodeSources(t, server.URL+"/manifests/app.yaml"))
+		_, err := DecodeFiles(make(chan struct{}), []string{server.URL + "/missing.yaml"})
+		assert.Error(t, err)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := DecodeFiles(make(chan struct{}), []string{filepath.Join(dir, "missing")})
+		assert.Error(t, err)
+		_, err = DecodeFiles(make(chan struct{}), []string{filepath.Join(dir, "**", "*.json")})
+		assert.Error(t, err)
+	})
+}
+
+func Test_matchGlob(t *testing.T) {
+	split := func(s string) []string { return strings.Split(s, "/") }
+	assert.True(t, matchGlob(split("**/*.yaml"), split("app.yaml")))
+	assert.True(t, matchGlob(split("**/*.yaml"), split("a/b/app.yaml")))
+	assert.True(t, matchGlob(split("a/**/b/*.yaml"), split("a/b/app.yaml")))
+	assert.False(t, matchGlob(split("*.yaml"), split("a/app.yaml")))
+	assert.False(t, matchGlob(split("**/*.yaml"), split("a/app.yml")))
+}
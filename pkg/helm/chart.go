@@ -1,15 +1,19 @@
 package helm
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/arttor/helmify/pkg/cluster"
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"sigs.k8s.io/yaml"
 )
@@ -23,14 +27,51 @@ type output struct{}
 
 // Create a helm chart in the current directory:
 // chartName/
-//    ├── .helmignore   	# Contains patterns to ignore when packaging Helm charts.
-//    ├── Chart.yaml    	# Information about your chart
-//    ├── values.yaml   	# The default values for your templates
-//    └── templates/    	# The template files
-//        └── _helpers.tp   # Helm default template partials
+//
+//	├── .helmignore   	# Contains patterns to ignore when packaging Helm charts.
+//	├── Chart.yaml    	# Information about your chart
+//	├── values.yaml   	# The default values for your templates
+//	└── templates/    	# The template files
+//	    └── _helpers.tp   # Helm default template partials
+//
 // Overwrites existing values.yaml and templates in templates dir on every run.
-func (o output) Create(chartDir, chartName string, crd bool, templates []helmify.Template) error {
-	err := initChartDir(chartDir, chartName, crd)
+// If config.ValuesFile is set, values are also copied into chartName/<ValuesFile>.
+// Chart.yaml fields and dependencies set in config are updated on every run.
+// If config.ParentChartName is set, the chart is a subchart rendering resource names of its parent chart.
+// If config.LibraryChart is set, library chart with shared helpers is created in chartName/charts.
+// If config.ValuesSchema is set, values.schema.json is generated from values types.
+// If config.ValuesReadme is set, README.md with a table of values is generated.
+// If config.Diff is set, the chart is not changed. Unified diff of changes is written to stdout instead.
+// If config.DryRun is set, the chart is not changed. All chart files are written to stdout instead.
+// If config.Update is set, templates and values defaults modified since previous run are kept. Hashes of generated
+// templates and values are written into chartName/.helmify-lock.
+// If config.CIValues is set, chart-testing values with placeholders for required values are written into chartName/ci.
+// If config.SecretValuesFile is set, decoded Secret values are written into chartName/<SecretValuesFile>.
+func (o output) Create(config config.Config, templates []helmify.Template) error {
+	if config.Diff {
+		return o.diff(config, templates, os.Stdout)
+	}
+	if config.DryRun {
+		return o.dryRun(config, templates, os.Stdout)
+	}
+	chartDir, chartName, crd := config.ChartDir, config.ChartName, config.Crd
+	err := initChartDir(config)
+	if err != nil {
+		return err
+	}
+	if config.ParentChartName != "" {
+		// dependencies are declared by umbrella chart only
+		config.Dependencies = nil
+	}
+	if config.LibraryChart != "" {
+		err = initLibraryChart(filepath.Join(chartDir, chartName, "charts"), config.LibraryChart, config.ParentChartName)
+		if err != nil {
+			return err
+		}
+		deps := config.Dependencies
+		config.Dependencies = append(deps[:len(deps):len(deps)], libraryDependency(config.LibraryChart))
+	}
+	err = updateChartfile(filepath.Join(chartDir, chartName), config)
 	if err != nil {
 		return err
 	}
@@ -38,6 +79,15 @@ func (o output) Create(chartDir, chartName string, crd bool, templates []helmify
 	files := map[string][]helmify.Template{}
 	values := helmify.Values{}
 	values[cluster.DomainKey] = cluster.DefaultDomain
+	for _, dep := range config.Dependencies {
+		if dep.Condition == "" {
+			continue
+		}
+		err = unstructured.SetNestedField(values, true, strings.Split(dep.Condition, ".")...)
+		if err != nil {
+			return errors.Wrapf(err, "unable to set dependency %s condition value", dep.Name)
+		}
+	}
 	for _, template := range templates {
 		file := files[template.Filename()]
 		file = append(file, template)
@@ -48,20 +98,105 @@ func (o output) Create(chartDir, chartName string, crd bool, templates []helmify
 		}
 	}
 	cDir := filepath.Join(chartDir, chartName)
+	for _, template := range templates {
+		filesTpl, ok := template.(helmify.FilesTemplate)
+		if !ok {
+			continue
+		}
+		err = writeChartFiles(cDir, filesTpl.Files())
+		if err != nil {
+			return err
+		}
+	}
+	var inline func([]byte) ([]byte, error)
+	if config.NoValues {
+		meta, err := chartutil.LoadChartfile(filepath.Join(cDir, "Chart.yaml"))
+		if err != nil {
+			return errors.Wrap(err, "unable to load Chart.yaml")
+		}
+		chartValues := values
+		inline = func(content []byte) ([]byte, error) {
+			return inlineValues(content, meta, chartValues)
+		}
+		values = helmify.Values{}
+	}
+	var upd *updater
+	if config.Update {
+		upd, err = newUpdater(cDir)
+		if err != nil {
+			return err
+		}
+	}
 	for filename, tpls := range files {
-		err = overwriteTemplateFile(filename, cDir, crd, tpls)
+		err = overwriteTemplateFile(filename, cDir, crd, tpls, inline, upd)
+		if err != nil {
+			return err
+		}
+	}
+	if upd != nil {
+		err = upd.removeStale()
+		if err != nil {
+			return err
+		}
+		values, err = upd.mergeValues("values.yaml", values)
 		if err != nil {
 			return err
 		}
 	}
-	err = overwriteValuesFile(cDir, values)
+	err = overwriteValuesFile(cDir, "values.yaml", values)
 	if err != nil {
 		return err
 	}
+	if config.ValuesSchema && !config.NoValues {
+		err = overwriteValuesSchema(cDir, values)
+		if err != nil {
+			return err
+		}
+	}
+	if config.ValuesReadme && !config.NoValues {
+		err = overwriteReadme(cDir, chartName, values, templates)
+		if err != nil {
+			return err
+		}
+	}
+	if config.ValuesFile != "" && config.ValuesFile != "values.yaml" {
+		err = overwriteValuesFile(cDir, config.ValuesFile, values)
+		if err != nil {
+			return err
+		}
+	}
+	if config.CIValues {
+		err = overwriteCIValues(cDir, templates)
+		if err != nil {
+			return err
+		}
+	}
+	if config.SecretValuesFile != "" {
+		secretValues := helmify.Values{}
+		for _, template := range templates {
+			secretTpl, ok := template.(helmify.SecretValuesTemplate)
+			if !ok {
+				continue
+			}
+			err = secretValues.Merge(secretTpl.SecretValues())
+			if err != nil {
+				return err
+			}
+		}
+		err = overwriteValuesFile(cDir, config.SecretValuesFile, secretValues)
+		if err != nil {
+			return err
+		}
+	}
+	if upd != nil {
+		return upd.save()
+	}
 	return nil
 }
 
-func overwriteTemplateFile(filename, chartDir string, crd bool, templates []helmify.Template) error {
+// overwriteTemplateFile - writes templates into the file. Optional inline func replaces values in the file content.
+// Files modified by user are kept if optional updater is set.
+func overwriteTemplateFile(filename, chartDir string, crd bool, templates []helmify.Template, inline func([]byte) ([]byte, error), upd *updater) error {
 	// pull in crd-dir setting and siphon crds into folder
 	var subdir string
 	if strings.Contains(filename, "crd") && crd {
@@ -77,37 +212,72 @@ func overwriteTemplateFile(filename, chartDir string, crd bool, templates []helm
 		subdir = "templates"
 	}
 	file := filepath.Join(chartDir, subdir, filename)
-	f, err := os.OpenFile(file, os.O_APPEND|os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	// templates may be placed in subdirectories, e.g. templates/tests
+	err := os.MkdirAll(filepath.Dir(file), 0750)
 	if err != nil {
-		return errors.Wrap(err, "unable to open "+file)
+		return errors.Wrap(err, "unable to create dir for "+file)
 	}
-	defer f.Close()
+	var buf bytes.Buffer
 	for i, t := range templates {
 		logrus.WithField("file", file).Debug("writing a template into")
-		err = t.Write(f)
+		err := t.Write(&buf)
 		if err != nil {
 			return errors.Wrap(err, "unable to write into "+file)
 		}
 		if i != len(templates)-1 {
-			_, err = f.Write([]byte("\n---\n"))
-			if err != nil {
-				return errors.Wrap(err, "unable to write into "+file)
-			}
+			buf.WriteString("\n---\n")
+		}
+	}
+	content := buf.Bytes()
+	if inline != nil && subdir == "templates" {
+		var err error
+		content, err = inline(content)
+		if err != nil {
+			return errors.Wrap(err, "unable to write into "+file)
 		}
 	}
+	if subdir == "templates" {
+		content = []byte(helmify.EscapeTemplates(string(content)))
+	} else {
+		content = []byte(helmify.QuoteTemplates(string(content)))
+	}
+	if upd != nil && !upd.writeTemplate(filepath.Join(subdir, filename), content) {
+		return nil
+	}
+	err = ioutil.WriteFile(file, content, 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write into "+file)
+	}
 	logrus.WithField("file", file).Info("overwritten")
 	return nil
 }
 
-func overwriteValuesFile(chartDir string, values helmify.Values) error {
-	res, err := yaml.Marshal(values)
+func writeChartFiles(chartDir string, files map[string][]byte) error {
+	for path, content := range files {
+		file := filepath.Join(chartDir, filepath.FromSlash(path))
+		content = []byte(helmify.RestoreTemplates(string(content)))
+		err := os.MkdirAll(filepath.Dir(file), 0750)
+		if err != nil {
+			return errors.Wrap(err, "unable to create dir for "+file)
+		}
+		err = ioutil.WriteFile(file, content, 0600)
+		if err != nil {
+			return errors.Wrap(err, "unable to write "+file)
+		}
+		logrus.WithField("file", file).Info("overwritten")
+	}
+	return nil
+}
+
+func overwriteValuesFile(chartDir, filename string, values helmify.Values) error {
+	res, err := yaml.Marshal(helmify.RestoreValues(values))
 	if err != nil {
-		return errors.Wrap(err, "unable to write marshal values.yaml")
+		return errors.Wrap(err, "unable to write marshal "+filename)
 	}
-	file := filepath.Join(chartDir, "values.yaml")
+	file := filepath.Join(chartDir, filename)
 	err = ioutil.WriteFile(file, res, 0600)
 	if err != nil {
-		return errors.Wrap(err, "unable to write values.yaml")
+		return errors.Wrap(err, "unable to write "+filename)
 	}
 	logrus.WithField("file", file).Info("overwritten")
 	return nil
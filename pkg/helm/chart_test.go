@@ -0,0 +1,176 @@
+package helm
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+)
+
+type filesTemplate struct{}
+
+func (f filesTemplate) Filename() string {
+	return "config.yaml"
+}
+
+func (f filesTemplate) Values() helmify.Values {
+	return helmify.Values{}
+}
+
+func (f filesTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(`data: {{ .Files.Get "files/config/app.conf" | quote }}`))
+	return err
+}
+
+func (f filesTemplate) Files() map[string][]byte {
+	return map[string][]byte{"files/config/app.conf": []byte("key=value")}
+}
+
+type secretTemplate struct{}
+
+func (s secretTemplate) Filename() string {
+	return "secret.yaml"
+}
+
+func (s secretTemplate) Values() helmify.Values {
+	return helmify.Values{"secret": map[string]interface{}{"password": ""}}
+}
+
+func (s secretTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(`password: {{ required "secret.password is required" .Values.secret.password | b64enc | quote }}`))
+	return err
+}
+
+func (s secretTemplate) SecretValues() helmify.Values {
+	return helmify.Values{"secret": map[string]interface{}{"password": "p@ss"}}
+}
+
+func Test_output_Create(t *testing.T) {
+	t.Run("additional values file", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart", ValuesFile: "values.default.yaml"}, nil)
+		assert.NoError(t, err)
+		values, err := ioutil.ReadFile(filepath.Join(dir, "chart", "values.yaml"))
+		assert.NoError(t, err)
+		extra, err := ioutil.ReadFile(filepath.Join(dir, "chart", "values.default.yaml"))
+		assert.NoError(t, err)
+		assert.NotEmpty(t, values)
+		assert.Equal(t, values, extra)
+	})
+	t.Run("no additional values file by default", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, nil)
+		assert.NoError(t, err)
+		files, err := filepath.Glob(filepath.Join(dir, "chart", "values*"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "chart", "values.yaml")}, files)
+	})
+	t.Run("chart files", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, []helmify.Template{filesTemplate{}})
+		assert.NoError(t, err)
+		content, err := ioutil.ReadFile(filepath.Join(dir, "chart", "files", "config", "app.conf"))
+		assert.NoError(t, err)
+		assert.Equal(t, "key=value", string(content))
+	})
+	t.Run("secret values file", func(t *testing.T) {
+		dir := t.TempDir()
+		conf := config.Config{ChartDir: dir, ChartName: "chart", SecretValuesFile: "secret-values.yaml"}
+		err := NewOutput().Create(conf, []helmify.Template{secretTemplate{}})
+		assert.NoError(t, err)
+		values, err := ioutil.ReadFile(filepath.Join(dir, "chart", "values.yaml"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(values), "password: \"\"")
+		secretValues, err := ioutil.ReadFile(filepath.Join(dir, "chart", "secret-values.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, "secret:\n  password: p@ss\n", string(secretValues))
+	})
+	t.Run("ci values file", func(t *testing.T) {
+		dir := t.TempDir()
+		conf := config.Config{ChartDir: dir, ChartName: "chart", CIValues: true}
+		err := NewOutput().Create(conf, []helmify.Template{secretTemplate{}, valuesTemplate{}})
+		assert.NoError(t, err)
+		ciValues, err := ioutil.ReadFile(filepath.Join(dir, "chart", "ci", "default-values.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, "secret:\n  password: ci-placeholder\n", string(ciValues))
+	})
+	t.Run("scaffolding", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, nil)
+		assert.NoError(t, err)
+		info, err := os.Stat(filepath.Join(dir, "chart", "templates", "tests"))
+		assert.NoError(t, err)
+		assert.True(t, info.IsDir())
+
+		assert.NoError(t, os.Remove(filepath.Join(dir, "chart", ".helmignore")))
+		err = NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, nil)
+		assert.NoError(t, err)
+		helmignore, err := ioutil.ReadFile(filepath.Join(dir, "chart", ".helmignore"))
+		assert.NoError(t, err)
+		assert.Equal(t, helmIgnore, string(helmignore))
+	})
+}
+
+type valuesTemplate struct{}
+
+func (v valuesTemplate) Filename() string {
+	return "deployment.yaml"
+}
+
+func (v valuesTemplate) Values() helmify.Values {
+	return helmify.Values{"app": map[string]interface{}{
+		"replicas":  int64(2),
+		"podLabels": map[string]interface{}{},
+		"args":      []interface{}{"--a|b"},
+		"ratio":     0.5,
+		"debug":     false,
+	}}
+}
+
+func (v valuesTemplate) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(`replicas: {{ .Values.app.replicas }}`))
+	return err
+}
+
+func Test_output_Create_readme(t *testing.T) {
+	dir := t.TempDir()
+	err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart", ValuesReadme: true, SecretValues: true},
+		[]helmify.Template{valuesTemplate{}, secretTemplate{}})
+	assert.NoError(t, err)
+	readme, err := ioutil.ReadFile(filepath.Join(dir, "chart", "README.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# chart\n\nThis chart was generated by [helmify](https://github.com/arttor/helmify).\n\n## Values\n\n"+
+		"| Key | Type | Default | Template |\n"+
+		"|-----|------|---------|----------|\n"+
+		"| `app.args` | list | `[\"--a\\|b\"]` | templates/deployment.yaml |\n"+
+		"| `app.debug` | bool | `false` | templates/deployment.yaml |\n"+
+		"| `app.podLabels` | object | `{}` | templates/deployment.yaml |\n"+
+		"| `app.ratio` | float | `0.5` | templates/deployment.yaml |\n"+
+		"| `app.replicas` | int | `2` | templates/deployment.yaml |\n"+
+		"| `kubernetesClusterDomain` | string | `\"cluster.local\"` |  |\n"+
+		"| `secret.password` | string | `\"\"` | templates/secret.yaml |\n", string(readme))
+
+	t.Run("secret defaults masked", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart", ValuesReadme: true},
+			[]helmify.Template{secretWithValuesTemplate{}})
+		assert.NoError(t, err)
+		readme, err := ioutil.ReadFile(filepath.Join(dir, "chart", "README.md"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(readme), "| `secret.password` | string | `\"<secret>\"` | templates/secret.yaml |\n")
+		assert.NotContains(t, string(readme), "p@ss")
+	})
+}
+
+type secretWithValuesTemplate struct {
+	secretTemplate
+}
+
+func (s secretWithValuesTemplate) Values() helmify.Values {
+	return s.SecretValues()
+}
@@ -0,0 +1,146 @@
+package helm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// maintainerRegexp - matches maintainer in form 'name', 'name <email>' or 'name <email> (url)'.
+var maintainerRegexp = regexp.MustCompile(`^([^<(]+?)\s*(?:<([^>]*)>)?\s*(?:\(([^)]*)\))?$`)
+
+// updateChartfile - sets Chart.yaml fields given in config. Other fields and comments are kept as is.
+func updateChartfile(chartDir string, conf config.Config) error {
+	fields, err := chartfileFields(conf)
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+	file := filepath.Join(chartDir, "Chart.yaml")
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrap(err, "unable to read Chart.yaml")
+	}
+	doc := yaml.Node{}
+	err = yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return errors.Wrap(err, "unable to parse Chart.yaml")
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return errors.New("unable to update Chart.yaml: not a yaml map")
+	}
+	for _, f := range fields {
+		setField(doc.Content[0], f.key, f.value)
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&doc)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal Chart.yaml")
+	}
+	err = ioutil.WriteFile(file, buf.Bytes(), 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write Chart.yaml")
+	}
+	meta, err := chartutil.LoadChartfile(file)
+	if err != nil {
+		return errors.Wrap(err, "unable to load Chart.yaml")
+	}
+	err = meta.Validate()
+	if err != nil {
+		return errors.Wrap(err, "invalid Chart.yaml")
+	}
+	logrus.WithField("file", file).Info("updated")
+	return nil
+}
+
+type chartfileField struct {
+	key   string
+	value *yaml.Node
+}
+
+// chartfileFields - returns Chart.yaml fields set in config.
+func chartfileFields(conf config.Config) ([]chartfileField, error) {
+	var res []chartfileField
+	addString := func(key, value string, style yaml.Style) {
+		if value != "" {
+			res = append(res, chartfileField{key: key, value: &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: style}})
+		}
+	}
+	addString("version", conf.ChartVersion, 0)
+	addString("appVersion", conf.ChartAppVersion, yaml.DoubleQuotedStyle)
+	addString("description", conf.ChartDescription, 0)
+	addString("home", conf.ChartHome, 0)
+	addString("icon", conf.ChartIcon, 0)
+	if len(conf.ChartKeywords) != 0 {
+		keywords := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, k := range conf.ChartKeywords {
+			keywords.Content = append(keywords.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k})
+		}
+		res = append(res, chartfileField{key: "keywords", value: keywords})
+	}
+	if len(conf.ChartMaintainers) != 0 {
+		maintainers := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, m := range conf.ChartMaintainers {
+			match := maintainerRegexp.FindStringSubmatch(strings.TrimSpace(m))
+			if match == nil {
+				return nil, errors.Errorf("invalid chart maintainer %s: must be in form 'name <email> (url)' with optional email and url", m)
+			}
+			maintainer := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for i, key := range []string{"name", "email", "url"} {
+				if match[i+1] != "" {
+					setField(maintainer, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: match[i+1]})
+				}
+			}
+			maintainers.Content = append(maintainers.Content, maintainer)
+		}
+		res = append(res, chartfileField{key: "maintainers", value: maintainers})
+	}
+	if len(conf.Dependencies) != 0 {
+		dependencies := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, d := range conf.Dependencies {
+			dependency := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for _, f := range [][2]string{{"name", d.Name}, {"version", d.Version}, {"repository", d.Repository}, {"condition", d.Condition}} {
+				if f[1] != "" {
+					setField(dependency, f[0], &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f[1]})
+				}
+			}
+			dependencies.Content = append(dependencies.Content, dependency)
+		}
+		res = append(res, chartfileField{key: "dependencies", value: dependencies})
+	}
+	if len(conf.ChartAnnotations) != 0 {
+		keys := make([]string, 0, len(conf.ChartAnnotations))
+		for k := range conf.ChartAnnotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		annotations := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range keys {
+			setField(annotations, k, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: conf.ChartAnnotations[k]})
+		}
+		res = append(res, chartfileField{key: "annotations", value: annotations})
+	}
+	return res, nil
+}
+
+// setField - replaces value of the key in yaml map node or appends the key if not present.
+func setField(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
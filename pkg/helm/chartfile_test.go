@@ -0,0 +1,93 @@
+package helm
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func Test_updateChartfile(t *testing.T) {
+	conf := config.Config{
+		ChartName:        "chart",
+		ChartVersion:     "1.2.0",
+		ChartAppVersion:  "1.10",
+		ChartDescription: "My application",
+		ChartHome:        "https://example.com",
+		ChartKeywords:    []string{"web", "database"},
+		ChartMaintainers: []string{"Jane Doe <jane@example.com> (https://example.com/jane)", "John"},
+		ChartAnnotations: map[string]string{"category": "Database", "licenses": "Apache-2.0"},
+	}
+	t.Run("new chart", func(t *testing.T) {
+		dir := t.TempDir()
+		conf := conf
+		conf.ChartDir = dir
+		err := NewOutput().Create(conf, nil)
+		assert.NoError(t, err)
+		file := filepath.Join(dir, "chart", "Chart.yaml")
+		meta, err := chartutil.LoadChartfile(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "chart", meta.Name)
+		assert.Equal(t, "1.2.0", meta.Version)
+		assert.Equal(t, "1.10", meta.AppVersion)
+		assert.Equal(t, "My application", meta.Description)
+		assert.Equal(t, "https://example.com", meta.Home)
+		assert.Equal(t, "", meta.Icon)
+		assert.Equal(t, []string{"web", "database"}, meta.Keywords)
+		assert.Equal(t, []*chart.Maintainer{
+			{Name: "Jane Doe", Email: "jane@example.com", URL: "https://example.com/jane"},
+			{Name: "John"},
+		}, meta.Maintainers)
+		assert.Equal(t, map[string]string{"category": "Database", "licenses": "Apache-2.0"}, meta.Annotations)
+		content, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "# This is the chart version.")
+		assert.Contains(t, string(content), `appVersion: "1.10"`)
+	})
+	t.Run("existing chart updated", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, nil)
+		assert.NoError(t, err)
+		err = NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart", ChartVersion: "2.0.0"}, nil)
+		assert.NoError(t, err)
+		meta, err := chartutil.LoadChartfile(filepath.Join(dir, "chart", "Chart.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", meta.Version)
+		assert.Equal(t, "0.1.0", meta.AppVersion)
+		assert.Equal(t, "A Helm chart for Kubernetes", meta.Description)
+	})
+	t.Run("dependencies", func(t *testing.T) {
+		dir := t.TempDir()
+		deps := []config.Dependency{
+			{Name: "postgresql", Version: "12.x", Repository: "@bitnami", Condition: "postgresql.enabled"},
+			{Name: "redis", Version: "17.3.2", Repository: "https://charts.bitnami.com/bitnami"},
+		}
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart", Dependencies: deps}, nil)
+		assert.NoError(t, err)
+		meta, err := chartutil.LoadChartfile(filepath.Join(dir, "chart", "Chart.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, []*chart.Dependency{
+			{Name: "postgresql", Version: "12.x", Repository: "@bitnami", Condition: "postgresql.enabled"},
+			{Name: "redis", Version: "17.3.2", Repository: "https://charts.bitnami.com/bitnami"},
+		}, meta.Dependencies)
+		values, err := chartutil.ReadValuesFile(filepath.Join(dir, "chart", "values.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"enabled": true}, values["postgresql"])
+	})
+	t.Run("invalid version", func(t *testing.T) {
+		err := NewOutput().Create(config.Config{ChartDir: t.TempDir(), ChartName: "chart", ChartVersion: "latest"}, nil)
+		assert.Error(t, err)
+	})
+	t.Run("not changed without fields", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewOutput().Create(config.Config{ChartDir: dir, ChartName: "chart"}, nil)
+		assert.NoError(t, err)
+		content, err := ioutil.ReadFile(filepath.Join(dir, "chart", "Chart.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, string(chartYAML("chart")), string(content))
+	})
+}
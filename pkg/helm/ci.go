@@ -0,0 +1,65 @@
+package helm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ciValuesFile - values file installed by chart-testing 'ct install' in addition to values.yaml.
+const ciValuesFile = "ci/default-values.yaml"
+
+// ciPlaceholder - value set for required values in CI values file.
+const ciPlaceholder = "ci-placeholder"
+
+// requiredRegexp - matches 'required' action message with values path, e.g. 'required "secret.password is required"'.
+var requiredRegexp = regexp.MustCompile(`required "([A-Za-z0-9_.-]+) is required"`)
+
+// overwriteCIValues - writes chart-testing values file with placeholders for values required on install,
+// e.g. Secret data, so 'ct install' works without manual changes.
+func overwriteCIValues(chartDir string, templates []helmify.Template) error {
+	required, err := requiredValues(templates)
+	if err != nil {
+		return err
+	}
+	values := helmify.Values{}
+	for _, path := range required {
+		err = unstructured.SetNestedField(values, ciPlaceholder, strings.Split(path, ".")...)
+		if err != nil {
+			return errors.Wrapf(err, "unable to set %s value in %s", path, ciValuesFile)
+		}
+	}
+	err = os.MkdirAll(filepath.Join(chartDir, filepath.Dir(ciValuesFile)), 0750)
+	if err != nil {
+		return errors.Wrap(err, "unable to create ci dir")
+	}
+	return overwriteValuesFile(chartDir, ciValuesFile, values)
+}
+
+// requiredValues - returns sorted paths of values required by templates.
+func requiredValues(templates []helmify.Template) ([]string, error) {
+	paths := map[string]struct{}{}
+	for _, t := range templates {
+		var buf bytes.Buffer
+		err := t.Write(&buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to write "+t.Filename())
+		}
+		for _, match := range requiredRegexp.FindAllStringSubmatch(buf.String(), -1) {
+			paths[match[1]] = struct{}{}
+		}
+	}
+	res := make([]string, 0, len(paths))
+	for path := range paths {
+		res = append(res, path)
+	}
+	sort.Strings(res)
+	return res, nil
+}
@@ -0,0 +1,144 @@
+package helm
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diff - creates chart in a temporary copy of the existing chart and writes unified diff of changed files.
+// The chart on disk is not modified.
+func (o output) diff(conf config.Config, templates []helmify.Template, writer io.Writer) error {
+	tmpDir, err := ioutil.TempDir("", "helmify-diff")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary dir")
+	}
+	defer os.RemoveAll(tmpDir)
+	chartDir := filepath.Join(conf.ChartDir, conf.ChartName)
+	newChartDir := filepath.Join(tmpDir, conf.ChartName)
+	err = copyDir(chartDir, newChartDir)
+	if err != nil {
+		return err
+	}
+	conf.ChartDir, conf.Diff = tmpDir, false
+	err = o.Create(conf, templates)
+	if err != nil {
+		return err
+	}
+	return diffDirs(chartDir, newChartDir, writer)
+}
+
+// diffDirs - writes unified diffs of files added, removed or changed in newDir compared to oldDir.
+func diffDirs(oldDir, newDir string, writer io.Writer) error {
+	oldFiles, err := readDir(oldDir)
+	if err != nil {
+		return err
+	}
+	newFiles, err := readDir(newDir)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(newFiles))
+	for path := range newFiles {
+		paths = append(paths, path)
+	}
+	for path := range oldFiles {
+		if _, ok := newFiles[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		oldContent, oldOk := oldFiles[path]
+		newContent, newOk := newFiles[path]
+		if oldContent == newContent && oldOk == newOk {
+			continue
+		}
+		fromFile, toFile := "a/"+path, "b/"+path
+		if !oldOk {
+			fromFile = "/dev/null"
+		}
+		if !newOk {
+			toFile = "/dev/null"
+		}
+		err = difflib.WriteUnifiedDiff(writer, difflib.UnifiedDiff{
+			A:        splitLines(oldContent),
+			B:        splitLines(newContent),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return errors.Wrap(err, "unable to write diff of "+path)
+		}
+	}
+	return nil
+}
+
+// splitLines - splits content into lines keeping line ends. Line end is added to the last line if missing.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// readDir - returns contents of files in dir by slash-separated path relative to dir. Missing dir is empty.
+func readDir(dir string) (map[string]string, error) {
+	res := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == dir {
+			return filepath.SkipDir
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		res[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read "+dir)
+	}
+	return res, nil
+}
+
+// copyDir - copies files of srcDir into dstDir. Missing srcDir is not copied.
+func copyDir(srcDir, dstDir string) error {
+	files, err := readDir(srcDir)
+	if err != nil {
+		return err
+	}
+	for path, content := range files {
+		file := filepath.Join(dstDir, filepath.FromSlash(path))
+		err = os.MkdirAll(filepath.Dir(file), 0750)
+		if err != nil {
+			return errors.Wrap(err, "unable to create dir for "+file)
+		}
+		err = ioutil.WriteFile(file, []byte(content), 0600)
+		if err != nil {
+			return errors.Wrap(err, "unable to copy "+path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package helm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffDirs(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(oldDir, "same.yaml"), []byte("a: 1\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(newDir, "same.yaml"), []byte("a: 1\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(oldDir, "changed.yaml"), []byte("a: 1\nb: 2\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(newDir, "changed.yaml"), []byte("a: 1\nb: 3\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(oldDir, "removed.yaml"), []byte("a: 1\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(newDir, "added.yaml"), []byte("a: 1\n"), 0600))
+
+	var buf bytes.Buffer
+	assert.NoError(t, diffDirs(oldDir, newDir, &buf))
+	assert.Equal(t, `--- /dev/null
++++ b/added.yaml
+@@ -0,0 +1 @@
++a: 1
+--- a/changed.yaml
++++ b/changed.yaml
+@@ -1,2 +1,2 @@
+ a: 1
+-b: 2
++b: 3
+--- a/removed.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-a: 1
+`, buf.String())
+}
+
+func Test_output_diff(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.Config{ChartDir: dir, ChartName: "chart"}
+	assert.NoError(t, NewOutput().Create(conf, []helmify.Template{valuesTemplate{}}))
+	values, err := ioutil.ReadFile(filepath.Join(dir, "chart", "values.yaml"))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = output{}.diff(conf, []helmify.Template{valuesTemplate{}, secretTemplate{}}, &buf)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "+++ b/templates/secret.yaml")
+	assert.Contains(t, buf.String(), "+++ b/values.yaml")
+	assert.NotContains(t, buf.String(), "Chart.yaml")
+
+	_, err = os.Stat(filepath.Join(dir, "chart", "templates", "secret.yaml"))
+	assert.True(t, os.IsNotExist(err))
+	unchanged, err := ioutil.ReadFile(filepath.Join(dir, "chart", "values.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, values, unchanged)
+}
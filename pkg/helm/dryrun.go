@@ -0,0 +1,65 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+)
+
+// dryRun - creates chart in a temporary copy of the existing chart and writes all chart files with '# Source' headers
+// like 'helm template' does. The chart on disk is not modified.
+func (o output) dryRun(conf config.Config, templates []helmify.Template, writer io.Writer) error {
+	tmpDir, err := ioutil.TempDir("", "helmify-dry-run")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary dir")
+	}
+	defer os.RemoveAll(tmpDir)
+	newChartDir := filepath.Join(tmpDir, conf.ChartName)
+	err = copyDir(filepath.Join(conf.ChartDir, conf.ChartName), newChartDir)
+	if err != nil {
+		return err
+	}
+	prefix := conf.ChartName
+	if conf.ParentChartName != "" {
+		prefix = path.Join(conf.ParentChartName, "charts", conf.ChartName)
+	}
+	conf.ChartDir, conf.DryRun = tmpDir, false
+	err = o.Create(conf, templates)
+	if err != nil {
+		return err
+	}
+	return writeDir(newChartDir, prefix, writer)
+}
+
+// writeDir - writes files of dir sorted by path. Every file starts with '# Source: <prefix>/<path>' header.
+func writeDir(dir, prefix string, writer io.Writer) error {
+	files, err := readDir(dir)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		content := files[p]
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		_, err = fmt.Fprintf(writer, "---\n# Source: %s\n%s", path.Join(prefix, p), content)
+		if err != nil {
+			return errors.Wrap(err, "unable to write "+p)
+		}
+	}
+	return nil
+}
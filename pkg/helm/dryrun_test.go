@@ -0,0 +1,45 @@
+package helm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_writeDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.yaml"), []byte("b: 1"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.yaml"), []byte("a: 1\n"), 0600))
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeDir(dir, "chart", &buf))
+	assert.Equal(t, `---
+# Source: chart/a.yaml
+a: 1
+---
+# Source: chart/b.yaml
+b: 1
+`, buf.String())
+}
+
+func Test_output_dryRun(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	conf := config.Config{ChartDir: dir, ChartName: "chart", DryRun: true}
+	err := output{}.dryRun(conf, []helmify.Template{valuesTemplate{}, secretTemplate{}}, &buf)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "# Source: chart/Chart.yaml\n")
+	assert.Contains(t, buf.String(), "# Source: chart/templates/secret.yaml\n")
+	assert.Contains(t, buf.String(), "# Source: chart/values.yaml\n")
+	assert.NoDirExists(t, filepath.Join(dir, "chart"))
+
+	buf.Reset()
+	conf.ChartName, conf.ParentChartName = "backend", "app"
+	assert.NoError(t, output{}.dryRun(conf, []helmify.Template{valuesTemplate{}}, &buf))
+	assert.Contains(t, buf.String(), "# Source: app/charts/backend/values.yaml\n")
+}
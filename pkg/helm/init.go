@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -35,6 +36,8 @@ const helmIgnore = `# Patterns to ignore when building packages.
 .idea/
 *.tmproj
 .vscode/
+# helmify update mode lock
+.helmify-lock
 `
 
 const defaultHelpers = `{{/*
@@ -129,18 +132,38 @@ var chartName = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
 const maxChartNameLength = 250
 
 // initChartDir - creates Helm chart structure in chartName directory if not presented.
-func initChartDir(chartDir, chartName string, crd bool) error {
-	if err := validateChartName(chartName); err != nil {
+// Helpers of a subchart use parent chart name to render the same resource names as its parent chart.
+// If library chart is set, chart helpers delegate to the library chart helpers.
+func initChartDir(conf config.Config) error {
+	if err := validateChartName(conf.ChartName); err != nil {
 		return err
 	}
 
-	cDir := filepath.Join(chartDir, chartName)
+	cDir := filepath.Join(conf.ChartDir, conf.ChartName)
 	_, err := os.Stat(filepath.Join(cDir, "Chart.yaml"))
 	if os.IsNotExist(err) {
-		return createCommonFiles(chartDir, chartName, crd)
+		return createCommonFiles(conf)
+	}
+	if err != nil {
+		return err
 	}
 	logrus.Info("Skip creating Chart skeleton: Chart.yaml already exists.")
-	return err
+	return createHelmIgnore(cDir)
+}
+
+// createHelmIgnore - creates default .helmignore if it is missing in existing chart.
+func createHelmIgnore(cDir string) error {
+	file := filepath.Join(cDir, ".helmignore")
+	_, err := os.Stat(file)
+	if !os.IsNotExist(err) {
+		return err
+	}
+	err = ioutil.WriteFile(file, []byte(helmIgnore), 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write .helmignore")
+	}
+	logrus.WithField("file", file).Info("created")
+	return nil
 }
 
 func validateChartName(name string) error {
@@ -153,13 +176,14 @@ func validateChartName(name string) error {
 	return nil
 }
 
-func createCommonFiles(chartDir, chartName string, crd bool) error {
-	cDir := filepath.Join(chartDir, chartName)
-	err := os.MkdirAll(filepath.Join(cDir, "templates"), 0750)
+func createCommonFiles(conf config.Config) error {
+	cDir := filepath.Join(conf.ChartDir, conf.ChartName)
+	// templates/tests is a place for 'helm test' hooks like in 'helm create' charts
+	err := os.MkdirAll(filepath.Join(cDir, "templates", "tests"), 0750)
 	if err != nil {
 		return errors.Wrap(err, "unable create chart/templates dir")
 	}
-	if crd {
+	if conf.Crd {
 		err = os.MkdirAll(filepath.Join(cDir, "crds"), 0750)
 		if err != nil {
 			return errors.Wrap(err, "unable create crds dir")
@@ -175,9 +199,9 @@ func createCommonFiles(chartDir, chartName string, crd bool) error {
 			logrus.WithField("file", file).Info("created")
 		}
 	}
-	createFile(chartYAML(chartName), cDir, "Chart.yaml")
+	createFile(chartYAML(conf.ChartName), cDir, "Chart.yaml")
 	createFile([]byte(helmIgnore), cDir, ".helmignore")
-	createFile(helpersYAML(chartName), cDir, "templates", "_helpers.tpl")
+	createFile(helpersYAML(conf.ChartName, conf.ParentChartName, conf.LibraryChart), cDir, "templates", "_helpers.tpl")
 	return err
 }
 
@@ -185,6 +209,17 @@ func chartYAML(appName string) []byte {
 	return []byte(fmt.Sprintf(defaultChartfile, appName))
 }
 
-func helpersYAML(chartName string) []byte {
-	return []byte(strings.ReplaceAll(defaultHelpers, "<CHARTNAME>", chartName))
+func helpersYAML(chartName, parentName, library string) []byte {
+	if library != "" {
+		return []byte(strings.ReplaceAll(strings.ReplaceAll(libraryDelegateHelpers, "<LIBRARY>", library), "<CHARTNAME>", chartName))
+	}
+	return []byte(strings.ReplaceAll(withParentName(defaultHelpers, parentName), "<CHARTNAME>", chartName))
+}
+
+// withParentName - replaces chart name used in name helpers with parent chart name if set.
+func withParentName(helpers, parentName string) string {
+	if parentName == "" {
+		return helpers
+	}
+	return strings.ReplaceAll(helpers, "default .Chart.Name .Values.nameOverride", fmt.Sprintf("default %q .Values.nameOverride", parentName))
 }
@@ -0,0 +1,68 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+func Test_helpersYAML(t *testing.T) {
+	t.Run("fullname truncated", func(t *testing.T) {
+		helpers := string(helpersYAML("my-chart", "", ""))
+		assert.Contains(t, helpers, `{{- .Values.fullnameOverride | trunc 63 | trimSuffix "-" }}`)
+		assert.Contains(t, helpers, `{{- printf "%s-%s" .Release.Name $name | trunc 63 | trimSuffix "-" }}`)
+	})
+	longName := strings.Repeat("a", 60) + "-" + strings.Repeat("b", 10)
+	tests := []struct {
+		name        string
+		releaseName string
+		values      map[string]interface{}
+	}{
+		{name: "long release name", releaseName: longName, values: map[string]interface{}{}},
+		{name: "long fullnameOverride", releaseName: "rel", values: map[string]interface{}{"fullnameOverride": longName}},
+		{name: "trailing dash removed", releaseName: strings.Repeat("a", 62) + "-b", values: map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fullname := renderFullname(t, tt.releaseName, tt.values)
+			assert.LessOrEqual(t, len(fullname), 63)
+			assert.NotEmpty(t, fullname)
+			assert.False(t, strings.HasSuffix(fullname, "-"))
+		})
+	}
+}
+
+func Test_helpersYAML_subchart(t *testing.T) {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "backend", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: helpersYAML("backend", "my-chart", "")},
+			{Name: "templates/fullname.txt", Data: []byte(`{{ include "backend.fullname" . }} {{ include "backend.name" . }}`)},
+		},
+	}
+	vals, err := chartutil.ToRenderValues(chrt, map[string]interface{}{}, chartutil.ReleaseOptions{Name: "rel"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	assert.Equal(t, "rel-my-chart my-chart", out["backend/templates/fullname.txt"])
+	assert.Equal(t, renderFullname(t, "rel", map[string]interface{}{}), "rel-my-chart")
+}
+
+func renderFullname(t *testing.T, releaseName string, values map[string]interface{}) string {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "my-chart", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: helpersYAML("my-chart", "", "")},
+			{Name: "templates/fullname.txt", Data: []byte(`{{ include "my-chart.fullname" . }}`)},
+		},
+	}
+	vals, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{Name: releaseName}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	return out["my-chart/templates/fullname.txt"]
+}
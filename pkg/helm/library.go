@@ -0,0 +1,110 @@
+package helm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// libraryChartVersion - version of created library chart used in chart dependencies.
+const libraryChartVersion = "0.1.0"
+
+const libraryChartfile = `apiVersion: v2
+name: %s
+description: Shared helpers of charts generated by helmify
+# Library charts provide templates used by application charts and can not be installed.
+type: library
+version: ` + libraryChartVersion + `
+`
+
+// libraryHelpers - helpers shared by application charts in addition to default helpers.
+const libraryHelpers = `
+{{/*
+Container image from image values. Chart-wide global.imageRegistry value overrides image registry.
+Image is pinned by digest if digest value is set.
+Usage: {{ include "<CHARTNAME>.image" (dict "image" .Values.web.app.image "context" $) }}
+*/}}
+{{- define "<CHARTNAME>.image" -}}
+{{- with .context.Values.global.imageRegistry | default .image.registry }}{{ . }}/{{ end }}{{ .image.repository }}
+{{- with .image.digest }}@{{ . }}{{ else }}:{{ .image.tag | default $.context.Chart.AppVersion }}{{ end }}
+{{- end }}
+
+{{/*
+Pod or container securityContext from values.
+Usage: {{ include "<CHARTNAME>.securityContext" .Values.web.podSecurityContext | nindent 8 }}
+*/}}
+{{- define "<CHARTNAME>.securityContext" -}}
+{{- toYaml . }}
+{{- end }}
+`
+
+// libraryDelegateHelpers - application chart helpers delegating to library chart helpers.
+const libraryDelegateHelpers = `{{/*
+Helpers are defined in <LIBRARY> library chart.
+*/}}
+{{- define "<CHARTNAME>.name" -}}
+{{- include "<LIBRARY>.name" . }}
+{{- end }}
+
+{{- define "<CHARTNAME>.fullname" -}}
+{{- include "<LIBRARY>.fullname" . }}
+{{- end }}
+
+{{- define "<CHARTNAME>.chart" -}}
+{{- include "<LIBRARY>.chart" . }}
+{{- end }}
+
+{{- define "<CHARTNAME>.labels" -}}
+{{- include "<LIBRARY>.labels" . }}
+{{- end }}
+
+{{- define "<CHARTNAME>.selectorLabels" -}}
+{{- include "<LIBRARY>.selectorLabels" . }}
+{{- end }}
+
+{{- define "<CHARTNAME>.serviceAccountName" -}}
+{{- include "<LIBRARY>.serviceAccountName" . }}
+{{- end }}
+`
+
+// initLibraryChart - creates library chart with shared helpers in chartsDir if not presented.
+func initLibraryChart(chartsDir, library, parentName string) error {
+	cDir := filepath.Join(chartsDir, library)
+	_, err := os.Stat(filepath.Join(cDir, "Chart.yaml"))
+	if !os.IsNotExist(err) {
+		if err == nil {
+			logrus.Info("Skip creating library chart: Chart.yaml already exists.")
+		}
+		return err
+	}
+	err = os.MkdirAll(filepath.Join(cDir, "templates"), 0750)
+	if err != nil {
+		return errors.Wrap(err, "unable create library chart templates dir")
+	}
+	for file, content := range map[string][]byte{
+		filepath.Join(cDir, "Chart.yaml"):                []byte(fmt.Sprintf(libraryChartfile, library)),
+		filepath.Join(cDir, "templates", "_helpers.tpl"): libraryHelpersYAML(library, parentName),
+	} {
+		err = ioutil.WriteFile(file, content, 0600)
+		if err != nil {
+			return errors.Wrap(err, "unable to write library chart")
+		}
+		logrus.WithField("file", file).Info("created")
+	}
+	return nil
+}
+
+// libraryDependency - returns chart dependency on library chart in 'charts' dir.
+func libraryDependency(library string) config.Dependency {
+	return config.Dependency{Name: library, Version: libraryChartVersion}
+}
+
+func libraryHelpersYAML(library, parentName string) []byte {
+	return []byte(strings.ReplaceAll(withParentName(defaultHelpers+libraryHelpers, parentName), "<CHARTNAME>", library))
+}
@@ -0,0 +1,41 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+func Test_libraryHelpersYAML(t *testing.T) {
+	library := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "common", Version: libraryChartVersion, Type: "library"},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: libraryHelpersYAML("common", "")},
+		},
+	}
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "my-chart", Version: "0.1.0", AppVersion: "1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: helpersYAML("my-chart", "", "common")},
+			{Name: "templates/out.txt", Data: []byte(`{{ include "my-chart.fullname" . }}
+{{ include "common.image" (dict "image" .Values.web.image "context" $) }}
+{{ include "common.securityContext" .Values.web.securityContext }}`)},
+		},
+	}
+	chrt.AddDependency(library)
+	values := map[string]interface{}{
+		"global": map[string]interface{}{"imageRegistry": ""},
+		"web": map[string]interface{}{
+			"image":           map[string]interface{}{"registry": "gcr.io", "repository": "app", "tag": "", "digest": ""},
+			"securityContext": map[string]interface{}{"runAsNonRoot": true},
+		},
+	}
+	vals, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{Name: "rel"}, nil)
+	assert.NoError(t, err)
+	out, err := engine.Render(chrt, vals)
+	assert.NoError(t, err)
+	assert.Equal(t, "rel-my-chart\ngcr.io/app:1.0\nrunAsNonRoot: true", out["my-chart/templates/out.txt"])
+}
@@ -0,0 +1,81 @@
+package helm
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+// installTimeAction matches template actions which can only be evaluated on chart install
+// and have to be kept in a template as is.
+var installTimeAction = regexp.MustCompile(`\binclude\b|\brequired\b|\blookup\b|\btpl\b|\.Release\b|\.Capabilities\b|\.Files\b|\.Template\b`)
+
+// inlineValues - renders all template actions using values and keeps install-time actions as is.
+// Used to produce a static chart without values.
+func inlineValues(content []byte, meta *chart.Metadata, values helmify.Values) ([]byte, error) {
+	tpl := escapeInstallTimeActions(string(content))
+	chrt := &chart.Chart{
+		Metadata:  meta,
+		Templates: []*chart.File{{Name: "templates/inline", Data: []byte(tpl)}},
+	}
+	vals, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to inline values")
+	}
+	out, err := engine.Render(chrt, vals)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to inline values")
+	}
+	return []byte(out[meta.Name+"/templates/inline"]), nil
+}
+
+// escapeInstallTimeActions - wraps install-time actions into string literals printing the action itself.
+func escapeInstallTimeActions(tpl string) string {
+	var res strings.Builder
+	for {
+		start := strings.Index(tpl, "{{")
+		if start < 0 {
+			res.WriteString(tpl)
+			return res.String()
+		}
+		end := actionEnd(tpl, start)
+		if end < 0 {
+			res.WriteString(tpl)
+			return res.String()
+		}
+		res.WriteString(tpl[:start])
+		action := tpl[start:end]
+		if installTimeAction.MatchString(action) {
+			action = "{{ " + strconv.Quote(action) + " }}"
+		}
+		res.WriteString(action)
+		tpl = tpl[end:]
+	}
+}
+
+// actionEnd - returns index after closing delimiter of the action started at start or -1.
+// Delimiters inside quoted strings are ignored.
+func actionEnd(tpl string, start int) int {
+	var quote byte
+	for i := start + 2; i < len(tpl); i++ {
+		switch {
+		case quote != 0:
+			if tpl[i] == '\\' && quote == '"' {
+				i++
+			} else if tpl[i] == quote {
+				quote = 0
+			}
+		case tpl[i] == '"' || tpl[i] == '`':
+			quote = tpl[i]
+		case strings.HasPrefix(tpl[i:], "}}"):
+			return i + 2
+		}
+	}
+	return -1
+}
@@ -0,0 +1,38 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func Test_inlineValues(t *testing.T) {
+	tpl := `metadata:
+  name: {{ include "chart.fullname" . }}-app
+  labels:
+  {{- include "chart.labels" . | nindent 4 }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  replicas: {{ .Values.app.replicas }}
+  image: {{ .Values.app.image | quote }}
+  {{- with .Values.app.missing }}
+  missing: {{ . }}
+  {{- end }}
+  text: {{ "}}" }}
+  secret: {{ required "app.secret is required" .Values.app.secret | b64enc | quote }}`
+	values := helmify.Values{"app": map[string]interface{}{"replicas": int64(2), "image": "nginx:1.21", "secret": ""}}
+	res, err := inlineValues([]byte(tpl), &chart.Metadata{Name: "chart", Version: "0.1.0"}, values)
+	assert.NoError(t, err)
+	assert.Equal(t, `metadata:
+  name: {{ include "chart.fullname" . }}-app
+  labels:
+  {{- include "chart.labels" . | nindent 4 }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  replicas: 2
+  image: "nginx:1.21"
+  text: }}
+  secret: {{ required "app.secret is required" .Values.app.secret | b64enc | quote }}`, string(res))
+}
@@ -0,0 +1,196 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// lockFile - file in chart directory with hashes of templates and values managed by helmify.
+const lockFile = ".helmify-lock"
+
+// chartLock - hashes of generated template files by path relative to chart directory
+// and hashes of generated values defaults by values path.
+type chartLock struct {
+	Templates map[string]string `json:"templates"`
+	Values    map[string]string `json:"values"`
+}
+
+// updater - merges generated chart with existing chart in update mode. Template files and values defaults
+// changed since previous run are kept, other managed files and values are replaced by generated ones.
+type updater struct {
+	chartDir string
+	previous chartLock
+	current  chartLock
+}
+
+// newUpdater - returns updater with lock of the previous run. All existing files and values are treated as
+// modified if chart has no lock file.
+func newUpdater(chartDir string) (*updater, error) {
+	res := &updater{
+		chartDir: chartDir,
+		previous: chartLock{Templates: map[string]string{}, Values: map[string]string{}},
+		current:  chartLock{Templates: map[string]string{}, Values: map[string]string{}},
+	}
+	content, err := ioutil.ReadFile(filepath.Join(chartDir, lockFile))
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read "+lockFile)
+	}
+	err = yaml.Unmarshal(content, &res.previous)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse "+lockFile)
+	}
+	return res, nil
+}
+
+// writeTemplate - records generated file content and returns false if existing file was modified by user
+// and must be kept.
+func (u *updater) writeTemplate(path string, content []byte) bool {
+	u.current.Templates[path] = hash(content)
+	existing, err := ioutil.ReadFile(filepath.Join(u.chartDir, path))
+	if err != nil || bytes.Equal(existing, content) || hash(existing) == u.previous.Templates[path] {
+		return true
+	}
+	logrus.WithField("file", filepath.Join(u.chartDir, path)).Warn("Skipping: template was modified since previous run.")
+	return false
+}
+
+// removeStale - removes unmodified template files generated by previous run and not generated anymore.
+func (u *updater) removeStale() error {
+	for path, prevHash := range u.previous.Templates {
+		if _, ok := u.current.Templates[path]; ok {
+			continue
+		}
+		file := filepath.Join(u.chartDir, path)
+		existing, err := ioutil.ReadFile(file)
+		if err != nil || hash(existing) != prevHash {
+			continue
+		}
+		err = os.Remove(file)
+		if err != nil {
+			return errors.Wrap(err, "unable to remove "+file)
+		}
+		logrus.WithField("file", file).Info("removed")
+	}
+	return nil
+}
+
+// mergeValues - returns generated values merged with existing values file. Existing defaults changed since
+// previous run and keys added by user are kept, keys removed from generated values since previous run are dropped.
+func (u *updater) mergeValues(filename string, values helmify.Values) (helmify.Values, error) {
+	generated := valuesLeaves(nil, helmify.RestoreValues(values))
+	for key, leaf := range generated {
+		u.current.Values[key] = hash(leaf.json)
+	}
+	existingValues := map[string]interface{}{}
+	content, err := ioutil.ReadFile(filepath.Join(u.chartDir, filename))
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read "+filename)
+	}
+	err = yaml.Unmarshal(content, &existingValues)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse "+filename)
+	}
+	res := helmify.RestoreValues(values)
+	existing := valuesLeaves(nil, existingValues)
+	keys := make([]string, 0, len(existing))
+	for key := range existing {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		leaf := existing[key]
+		prevHash, managed := u.previous.Values[key]
+		_, stillGenerated := generated[key]
+		if managed && (hash(leaf.json) == prevHash || !stillGenerated) {
+			// unchanged default or removed key is replaced by generated values
+			continue
+		}
+		if m, ok := leaf.value.(map[string]interface{}); ok && len(m) == 0 {
+			continue
+		}
+		err = unstructured.SetNestedField(res, leaf.value, leaf.path...)
+		if err != nil {
+			logrus.WithError(err).Warnf("Skipping: unable to keep %s value", key)
+		}
+	}
+	return res, nil
+}
+
+// save - writes lock file of the current run.
+func (u *updater) save() error {
+	content, err := yaml.Marshal(u.current)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal "+lockFile)
+	}
+	file := filepath.Join(u.chartDir, lockFile)
+	err = ioutil.WriteFile(file, content, 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write "+lockFile)
+	}
+	logrus.WithField("file", file).Info("overwritten")
+	return nil
+}
+
+// valuesLeaf - values leaf with path and JSON encoded value.
+type valuesLeaf struct {
+	path  []string
+	value interface{}
+	json  []byte
+}
+
+// valuesLeaves - returns values leaves by dot-separated key. Lists and empty maps are leaves.
+func valuesLeaves(path []string, values map[string]interface{}) map[string]valuesLeaf {
+	res := map[string]valuesLeaf{}
+	for k, v := range values {
+		leafPath := append(append([]string{}, path...), k)
+		if m, ok := v.(map[string]interface{}); ok && len(m) != 0 {
+			for key, leaf := range valuesLeaves(leafPath, m) {
+				res[key] = leaf
+			}
+			continue
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			encoded = []byte(strconv.Quote(jsonDefault(v)))
+		}
+		res[valuesKeyName(leafPath)] = valuesLeaf{path: leafPath, value: v, json: encoded}
+	}
+	return res
+}
+
+// valuesKeyName - joins values path with dots, keys containing dots are quoted.
+func valuesKeyName(path []string) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = p
+		if strings.Contains(p, ".") {
+			parts[i] = strconv.Quote(p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
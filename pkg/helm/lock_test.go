@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+type updateTemplate struct {
+	filename string
+	values   helmify.Values
+}
+
+func (u updateTemplate) Filename() string {
+	return u.filename
+}
+
+func (u updateTemplate) Values() helmify.Values {
+	return u.values
+}
+
+func (u updateTemplate) Write(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "# %s\nreplicas: {{ .Values.web.replicas }}\n", u.filename)
+	return err
+}
+
+func Test_output_Create_update(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.Config{ChartDir: dir, ChartName: "chart", Update: true}
+	cDir := filepath.Join(dir, "chart")
+	web := func(replicas, port int64) helmify.Values {
+		return helmify.Values{"web": map[string]interface{}{"replicas": replicas, "port": port}}
+	}
+	err := NewOutput().Create(conf, []helmify.Template{
+		updateTemplate{filename: "web.yaml", values: web(1, 80)},
+		updateTemplate{filename: "worker.yaml"},
+		updateTemplate{filename: "old.yaml"},
+	})
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(cDir, lockFile))
+	assert.NoError(t, err)
+
+	// user edits
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cDir, "templates", "web.yaml"), []byte("# edited\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cDir, "values.yaml"), []byte("web:\n  replicas: 5\n  port: 80\n  extra: user\n"), 0600))
+
+	err = NewOutput().Create(conf, []helmify.Template{
+		updateTemplate{filename: "web.yaml", values: helmify.Values{"web": map[string]interface{}{"replicas": int64(2), "port": int64(8080), "image": "nginx"}}},
+		updateTemplate{filename: "worker.yaml", values: helmify.Values{"worker": map[string]interface{}{"enabled": true}}},
+	})
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(cDir, "templates", "web.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# edited\n", string(content))
+	_, err = os.Stat(filepath.Join(cDir, "templates", "old.yaml"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cDir, "templates", "worker.yaml"))
+	assert.NoError(t, err)
+
+	content, err = ioutil.ReadFile(filepath.Join(cDir, "values.yaml"))
+	assert.NoError(t, err)
+	values := map[string]interface{}{}
+	assert.NoError(t, yaml.Unmarshal(content, &values))
+	assert.Equal(t, map[string]interface{}{
+		"replicas": 5.0,
+		"port":     8080.0,
+		"image":    "nginx",
+		"extra":    "user",
+	}, values["web"])
+	assert.Equal(t, map[string]interface{}{"enabled": true}, values["worker"])
+}
@@ -0,0 +1,134 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const readmeHeader = `# %[1]s
+
+This chart was generated by [helmify](https://github.com/arttor/helmify).
+
+## Values
+
+| Key | Type | Default | Template |
+|-----|------|---------|----------|
+`
+
+// secretMask - default shown instead of decoded Secret data.
+const secretMask = `"<secret>"`
+
+// overwriteReadme - writes chart README.md with a table of all values keys, their types, defaults and templates using them.
+// Defaults of Secret values are masked.
+func overwriteReadme(chartDir, chartName string, values helmify.Values, templates []helmify.Template) error {
+	sources := map[string][]string{}
+	secrets := map[string]bool{}
+	for _, t := range templates {
+		_, isSecret := t.(helmify.SecretValuesTemplate)
+		for _, key := range flattenValues("", t.Values()) {
+			if !containsString(sources[key.name], t.Filename()) {
+				sources[key.name] = append(sources[key.name], t.Filename())
+			}
+			if isSecret {
+				secrets[key.name] = true
+			}
+		}
+	}
+	var res strings.Builder
+	res.WriteString(fmt.Sprintf(readmeHeader, chartName))
+	for _, key := range flattenValues("", helmify.RestoreValues(values)) {
+		def := key.def
+		if secrets[key.name] && def != `""` {
+			def = secretMask
+		}
+		files := make([]string, 0, len(sources[key.name]))
+		for _, file := range sources[key.name] {
+			files = append(files, "templates/"+file)
+		}
+		res.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", markdownCode(key.name), key.typ, markdownCode(def), strings.Join(files, ", ")))
+	}
+	file := filepath.Join(chartDir, "README.md")
+	err := ioutil.WriteFile(file, []byte(res.String()), 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write README.md")
+	}
+	logrus.WithField("file", file).Info("overwritten")
+	return nil
+}
+
+// valuesKey - values leaf with dot-separated name, type and JSON encoded default.
+type valuesKey struct {
+	name, typ, def string
+}
+
+// flattenValues - returns sorted values leaves. Lists and empty maps are leaves.
+func flattenValues(prefix string, values map[string]interface{}) []valuesKey {
+	var res []valuesKey
+	for k, v := range values {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		if m, ok := v.(map[string]interface{}); ok && len(m) != 0 {
+			res = append(res, flattenValues(name, m)...)
+			continue
+		}
+		res = append(res, valuesKey{name: name, typ: valueType(v), def: jsonDefault(v)})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].name < res[j].name
+	})
+	return res
+}
+
+// jsonDefault - returns value encoded as compact JSON without HTML escaping.
+func jsonDefault(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int32, int64:
+		return "int"
+	case float32, float64:
+		return "float"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
+
+// markdownCode - formats value as inline code in a table cell.
+func markdownCode(s string) string {
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
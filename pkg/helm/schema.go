@@ -0,0 +1,81 @@
+package helm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// valuesSchemaFile - name of JSON schema file validating chart values on install.
+const valuesSchemaFile = "values.schema.json"
+
+// overwriteValuesSchema - writes JSON schema derived from types of given values.
+func overwriteValuesSchema(chartDir string, values helmify.Values) error {
+	schema := objectSchema(values)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	res, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal "+valuesSchemaFile)
+	}
+	file := filepath.Join(chartDir, valuesSchemaFile)
+	err = ioutil.WriteFile(file, append(res, '\n'), 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to write "+valuesSchemaFile)
+	}
+	logrus.WithField("file", file).Info("overwritten")
+	return nil
+}
+
+// objectSchema - returns schema of object with properties of given values.
+// Image values require non-empty repository.
+func objectSchema(values map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for k, v := range values {
+		properties[k] = valueSchema(k, v)
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if _, ok := values["repository"].(string); ok && isImage(values) {
+		schema["required"] = []string{"repository"}
+		properties["repository"] = map[string]interface{}{"type": "string", "minLength": 1}
+	}
+	return schema
+}
+
+// valueSchema - returns schema of a single value. Container resources are quantities given either
+// as strings or numbers, so their fields are not typed.
+func valueSchema(name string, value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if name == "resources" {
+			return map[string]interface{}{"type": "object"}
+		}
+		return objectSchema(v)
+	case helmify.Values:
+		return objectSchema(v)
+	case []interface{}:
+		return map[string]interface{}{"type": "array"}
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case int, int32, int64:
+		return map[string]interface{}{"type": "integer"}
+	case float32, float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// isImage - returns true for values created by processor.TemplateImage.
+func isImage(values map[string]interface{}) bool {
+	for _, field := range []string{"registry", "tag", "digest"} {
+		if _, ok := values[field]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package helm
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_objectSchema(t *testing.T) {
+	values := helmify.Values{
+		"kubernetesClusterDomain": "cluster.local",
+		"myApp": map[string]interface{}{
+			"replicas":    int64(3),
+			"podLabels":   map[string]interface{}{},
+			"tolerations": []interface{}{},
+			"app": map[string]interface{}{
+				"image": map[string]interface{}{
+					"registry": "", "repository": "nginx", "tag": "1.25", "digest": "",
+				},
+				"resources": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "500m"},
+				},
+			},
+		},
+		"metrics": map[string]interface{}{"enabled": true, "threshold": 0.9},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"kubernetesClusterDomain": map[string]interface{}{"type": "string"},
+			"myApp": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"replicas":    map[string]interface{}{"type": "integer"},
+					"podLabels":   map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+					"tolerations": map[string]interface{}{"type": "array"},
+					"app": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"image": map[string]interface{}{
+								"type":     "object",
+								"required": []string{"repository"},
+								"properties": map[string]interface{}{
+									"registry":   map[string]interface{}{"type": "string"},
+									"repository": map[string]interface{}{"type": "string", "minLength": 1},
+									"tag":        map[string]interface{}{"type": "string"},
+									"digest":     map[string]interface{}{"type": "string"},
+								},
+							},
+							"resources": map[string]interface{}{"type": "object"},
+						},
+					},
+				},
+			},
+			"metrics": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"enabled":   map[string]interface{}{"type": "boolean"},
+					"threshold": map[string]interface{}{"type": "number"},
+				},
+			},
+		},
+	}, objectSchema(values))
+}
+
+func Test_overwriteValuesSchema(t *testing.T) {
+	dir := t.TempDir()
+	err := overwriteValuesSchema(dir, helmify.Values{"enabled": true})
+	assert.NoError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(dir, "values.schema.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "properties": {
+    "enabled": {
+      "type": "boolean"
+    }
+  },
+  "type": "object"
+}
+`, string(content))
+}
@@ -0,0 +1,131 @@
+package helmify
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// Template action delimiters found in source manifests are replaced with marks before processing,
+// so processors never confuse them with generated template actions. Marks are replaced back on chart output:
+// escaped in templates and restored to original text in values and chart files.
+const (
+	// openMark and closeMark replace '{{' and '}}' in multi-line strings.
+	openMark  = "\uE000"
+	closeMark = "\uE001"
+	// lineStartMark and lineEndMark enclose base64 encoded single-line strings. Single-line strings are escaped
+	// as a whole into quoted string, because yaml scalar starting with '{' must be quoted.
+	lineStartMark = "\uE002"
+	lineEndMark   = "\uE003"
+)
+
+// MarkTemplates - replaces template action delimiters in string fields of given object with marks.
+func MarkTemplates(obj map[string]interface{}) {
+	for k, v := range obj {
+		obj[k] = markValue(v)
+	}
+}
+
+func markValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return markString(v)
+	case map[string]interface{}:
+		MarkTemplates(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = markValue(v[i])
+		}
+	}
+	return value
+}
+
+func markString(s string) string {
+	if !strings.Contains(s, "{{") && !strings.Contains(s, "}}") {
+		return s
+	}
+	if !strings.Contains(s, "\n") {
+		return lineStartMark + base64.StdEncoding.EncodeToString([]byte(s)) + lineEndMark
+	}
+	s = strings.ReplaceAll(s, "{{", openMark)
+	return strings.ReplaceAll(s, "}}", closeMark)
+}
+
+// RestoreTemplates - replaces marks in given string with original text.
+func RestoreTemplates(s string) string {
+	return replaceMarks(s, func(line string) string { return line }, "{{", "}}")
+}
+
+// RestoreValues - returns values with marks in strings replaced with original text.
+func RestoreValues(values Values) Values {
+	return restoreValue(map[string]interface{}(values)).(map[string]interface{})
+}
+
+func restoreValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return RestoreTemplates(v)
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[key] = restoreValue(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = restoreValue(v[i])
+		}
+		return res
+	}
+	return value
+}
+
+// EscapeTemplates - replaces marks in chart template content with template actions printing original text.
+func EscapeTemplates(content string) string {
+	return replaceMarks(content, func(line string) string {
+		return "{{ " + strconv.Quote(line) + " | quote }}"
+	}, `{{"{{"}}`, `{{"}}"}}`)
+}
+
+// QuoteTemplates - replaces marks in non-templated yaml content, e.g. CRDs, with original text.
+func QuoteTemplates(content string) string {
+	return replaceMarks(content, strconv.Quote, "{{", "}}")
+}
+
+// EscapeActions - escapes template action delimiters in given text to be rendered literally.
+func EscapeActions(s string) string {
+	s = RestoreTemplates(s)
+	s = strings.ReplaceAll(s, "{{", openMark)
+	s = strings.ReplaceAll(s, "}}", `{{"}}"}}`)
+	return strings.ReplaceAll(s, openMark, `{{"{{"}}`)
+}
+
+// replaceMarks - replaces single-line strings with result of line func and open and close marks with given strings.
+func replaceMarks(s string, line func(string) string, open, close string) string {
+	if !strings.ContainsAny(s, openMark+closeMark+lineStartMark) {
+		return s
+	}
+	var res strings.Builder
+	for {
+		start := strings.Index(s, lineStartMark)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], lineEndMark)
+		if end < 0 {
+			break
+		}
+		end += start
+		decoded, err := base64.StdEncoding.DecodeString(s[start+len(lineStartMark) : end])
+		if err != nil {
+			break
+		}
+		res.WriteString(s[:start])
+		res.WriteString(line(string(decoded)))
+		s = s[end+len(lineEndMark):]
+	}
+	res.WriteString(s)
+	out := strings.ReplaceAll(res.String(), openMark, open)
+	return strings.ReplaceAll(out, closeMark, close)
+}
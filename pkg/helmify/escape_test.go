@@ -0,0 +1,48 @@
+package helmify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkTemplates(t *testing.T) {
+	obj := map[string]interface{}{
+		"plain": "no templates",
+		"line":  "{{ $labels.instance }} is down",
+		"multi": "a: {{ .A }}\nb: {{ .B }}\n",
+		"list":  []interface{}{"{{ x }}", int64(1)},
+		"map":   map[string]interface{}{"nested": "}}"},
+	}
+	MarkTemplates(obj)
+	assert.Equal(t, "no templates", obj["plain"])
+	assert.NotContains(t, obj["line"], "{{")
+	assert.NotContains(t, obj["multi"], "{{")
+	assert.NotContains(t, obj["list"].([]interface{})[0], "{{")
+	assert.Equal(t, int64(1), obj["list"].([]interface{})[1])
+	assert.NotContains(t, obj["map"].(map[string]interface{})["nested"], "}}")
+
+	t.Run("restore", func(t *testing.T) {
+		assert.Equal(t, "{{ $labels.instance }} is down", RestoreTemplates(obj["line"].(string)))
+		assert.Equal(t, "a: {{ .A }}\nb: {{ .B }}\n", RestoreTemplates(obj["multi"].(string)))
+		values := RestoreValues(Values{"list": obj["list"], "map": obj["map"]})
+		assert.Equal(t, Values{
+			"list": []interface{}{"{{ x }}", int64(1)},
+			"map":  map[string]interface{}{"nested": "}}"},
+		}, values)
+	})
+	t.Run("escape", func(t *testing.T) {
+		assert.Equal(t, `summary: {{ "{{ $labels.instance }} is down" | quote }}`, EscapeTemplates("summary: "+obj["line"].(string)))
+		assert.Equal(t, `a: {{"{{"}} .A {{"}}"}}`+"\n"+`b: {{"{{"}} .B {{"}}"}}`+"\n", EscapeTemplates(obj["multi"].(string)))
+	})
+	t.Run("quote", func(t *testing.T) {
+		assert.Equal(t, `summary: "{{ $labels.instance }} is down"`, QuoteTemplates("summary: "+obj["line"].(string)))
+	})
+}
+
+func TestEscapeActions(t *testing.T) {
+	assert.Equal(t, `{{"{{"}} .A {{"}}"}}`, EscapeActions("{{ .A }}"))
+	marked := map[string]interface{}{"a": "{{ .A }}"}
+	MarkTemplates(marked)
+	assert.Equal(t, `{{"{{"}} .A {{"}}"}}`, EscapeActions(marked["a"].(string)))
+}
@@ -25,9 +25,23 @@ type Template interface {
 	Write(writer io.Writer) error
 }
 
+// FilesTemplate - Template providing additional chart files, e.g. files read with '.Files.Get'.
+type FilesTemplate interface {
+	Template
+	// Files - returns file contents by file path relative to chart directory.
+	Files() map[string][]byte
+}
+
+// SecretValuesTemplate - Template providing secret values written into a separate values file.
+type SecretValuesTemplate interface {
+	Template
+	// SecretValues - returns decoded secret values.
+	SecretValues() Values
+}
+
 // Output - converts Template into helm chart on disk.
 type Output interface {
-	Create(chartName, chartDir string, Crd bool, templates []Template) error
+	Create(config config.Config, templates []Template) error
 }
 
 // AppMetadata handle common information about K8s objects in the chart.
@@ -41,11 +55,27 @@ type AppMetadata interface {
 	//				"my-app-secret"		-> "{{ include "chart.fullname" . }}-secret"
 	//				etc...
 	TemplatedName(objName string) string
+	// TemplatedServiceAccountName converts ServiceAccount name to templated Helm name.
+	// Example: 	"my-app-sa"	-> "{{ include "chart.serviceAccountName" . }}" if it is the only chart ServiceAccount.
+	TemplatedServiceAccountName(name string) string
+	// TemplatedSecretName converts Secret name to templated Helm name.
+	// Example: 	"my-app-secret"	-> "{{ .Values.secret.existingSecret | default (print (include "chart.fullname" .) "-secret") }}"
+	//				if existing secrets are enabled and it is a chart Secret.
+	TemplatedSecretName(name string) string
+	// TemplatedNamespace converts namespace reference to templated namespace according to namespace mode.
+	// Example: 	"my-ns"	-> "{{ .Release.Namespace }}" if "my-ns" is the app namespace and namespaces are dropped.
+	TemplatedNamespace(ns string) string
 	// TemplatedString converts a string to templated string with chart name.
 	TemplatedString(str string) string
 	// TrimName trims common prefix from object name if exists.
 	// We trim common prefix because helm already using release for this purpose.
 	TrimName(objName string) string
+	// HasConfig returns true if the chart contains ConfigMap or Secret of given kind and name.
+	HasConfig(kind, name string) bool
+	// IsAutoscaled returns true if the chart contains HorizontalPodAutoscaler targeting workload of given kind and name.
+	IsAutoscaled(kind, name string) bool
+	// KindCount returns number of chart objects of given kind.
+	KindCount(kind string) int
 
 	Config() config.Config
 }
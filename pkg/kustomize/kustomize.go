@@ -0,0 +1,48 @@
+// Package kustomize builds kustomizations with kustomize Go API.
+package kustomize
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Build - builds kustomization in the given directory like 'kubectl kustomize' does and returns yaml manifests.
+func Build(dir string) ([]byte, error) {
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to build kustomization %s", dir)
+	}
+	manifests, err := resMap.AsYaml()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to build kustomization %s", dir)
+	}
+	return manifests, nil
+}
+
+// Load - reads kustomization file from the given directory.
+func Load(dir string) (*types.Kustomization, error) {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read kustomization")
+		}
+		kustomization := &types.Kustomization{}
+		err = yaml.Unmarshal(content, kustomization)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse %s", filepath.Join(dir, name))
+		}
+		return kustomization, nil
+	}
+	return nil, errors.Errorf("kustomization file not found in %s", dir)
+}
@@ -0,0 +1,84 @@
+package kustomize
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	baseKustomization = `resources:
+- deployment.yaml
+`
+	baseDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+`
+	overlayKustomization = `namePrefix: prod-
+resources:
+- ../base
+patchesStrategicMerge:
+- replicas.yaml
+`
+	overlayPatch = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`
+)
+
+func writeKustomization(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base/kustomization.yaml":   baseKustomization,
+		"base/deployment.yaml":      baseDeployment,
+		"overlay/kustomization.yml": overlayKustomization,
+		"overlay/replicas.yaml":     overlayPatch,
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+		assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	}
+	return filepath.Join(dir, "overlay")
+}
+
+func TestBuild(t *testing.T) {
+	dir := writeKustomization(t)
+	manifests, err := Build(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, string(manifests), "name: prod-web")
+	assert.Contains(t, string(manifests), "replicas: 3")
+
+	_, err = Build(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeKustomization(t)
+	kustomization, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod-", kustomization.NamePrefix)
+
+	_, err = Load(t.TempDir())
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -13,12 +14,57 @@ import (
 
 const nameTeml = `{{ include "%s.fullname" . }}-%s`
 
+const existingSecretNameTeml = `{{ .Values.%s.existingSecret | default (print (include "%s.fullname" .) "-%s") }}`
+
+const releaseNamespace = "{{ .Release.Namespace }}"
+
+const serviceAccountNameTeml = `{{ include "%s.serviceAccountName" . }}`
+
+var serviceAccountGVK = schema.GroupVersionKind{
+	Group:   "",
+	Version: "v1",
+	Kind:    "ServiceAccount",
+}
+
 var nsGVK = schema.GroupVersionKind{
 	Group:   "",
 	Version: "v1",
 	Kind:    "Namespace",
 }
 
+var certGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+var externalSecretGK = schema.GroupKind{
+	Group: "external-secrets.io",
+	Kind:  "ExternalSecret",
+}
+
+var sealedSecretGK = schema.GroupKind{
+	Group: "bitnami.com",
+	Kind:  "SealedSecret",
+}
+
+var configMapGVK = schema.GroupVersionKind{
+	Group:   "",
+	Version: "v1",
+	Kind:    "ConfigMap",
+}
+
+var secretGVK = schema.GroupVersionKind{
+	Group:   "",
+	Version: "v1",
+	Kind:    "Secret",
+}
+
+var hpaGK = schema.GroupKind{
+	Group: "autoscaling",
+	Kind:  "HorizontalPodAutoscaler",
+}
+
 var crdGVK = schema.GroupVersionKind{
 	Group:   "apiextensions.k8s.io",
 	Version: "v1",
@@ -26,14 +72,18 @@ var crdGVK = schema.GroupVersionKind{
 }
 
 func New(conf config.Config) *Service {
-	return &Service{names: make(map[string]struct{}), conf: conf}
+	return &Service{names: make(map[string]struct{}), configs: make(map[string]struct{}), autoscaled: make(map[string]struct{}), kinds: make(map[string]int), conf: conf}
 }
 
 type Service struct {
-	commonPrefix string
-	namespace    string
-	names        map[string]struct{}
-	conf         config.Config
+	commonPrefix    string
+	namespace       string
+	names           map[string]struct{}
+	configs         map[string]struct{}
+	autoscaled      map[string]struct{}
+	kinds           map[string]int
+	serviceAccounts []string
+	conf            config.Config
 }
 
 func (a *Service) Config() config.Config {
@@ -43,8 +93,16 @@ func (a *Service) Config() config.Config {
 // TrimName - tries to trim app common prefix for object name if detected.
 // If no common prefix - returns name as it is.
 // It is better to trim common prefix because Helm also adds release name as common prefix.
+// Name prefix and suffix set in config, e.g. by kustomization, are trimmed instead of detected prefix.
 func (a *Service) TrimName(objName string) string {
-	trimmed := strings.TrimPrefix(objName, a.commonPrefix)
+	prefix := a.commonPrefix
+	if a.conf.NamePrefix != "" {
+		prefix = a.conf.NamePrefix
+	}
+	trimmed := strings.TrimPrefix(objName, prefix)
+	if a.conf.NameSuffix != "" && strings.HasSuffix(trimmed, a.conf.NameSuffix) {
+		trimmed = strings.TrimRight(strings.TrimSuffix(trimmed, a.conf.NameSuffix), "-./_ ")
+	}
 	trimmed = strings.TrimLeft(trimmed, "-./_ ")
 	if trimmed == "" {
 		return objName
@@ -57,8 +115,19 @@ var _ helmify.AppMetadata = &Service{}
 // Load processed objects one-by-one before actual processing to define app namespace, name common prefix and
 // other app meta information.
 func (a *Service) Load(obj *unstructured.Unstructured) {
-	a.names[obj.GetName()] = struct{}{}
-	a.commonPrefix = detectCommonPrefix(obj, a.commonPrefix)
+	a.LoadReference(obj)
+	a.kinds[obj.GetKind()]++
+	if obj.GroupVersionKind() == serviceAccountGVK {
+		a.serviceAccounts = append(a.serviceAccounts, obj.GetName())
+	}
+	if obj.GroupVersionKind() == configMapGVK || obj.GroupVersionKind() == secretGVK {
+		a.configs[obj.GetKind()+"/"+obj.GetName()] = struct{}{}
+	}
+	if obj.GroupVersionKind().GroupKind() == hpaGK {
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+		a.autoscaled[kind+"/"+name] = struct{}{}
+	}
 	objNs := extractAppNamespace(obj)
 	if objNs == "" {
 		return
@@ -69,6 +138,41 @@ func (a *Service) Load(obj *unstructured.Unstructured) {
 	a.namespace = objNs
 }
 
+// LoadReference loads object names and common name prefix only. Used for objects of other charts,
+// e.g. sibling subcharts, referenced by chart objects. Load calls it for chart objects.
+func (a *Service) LoadReference(obj *unstructured.Unstructured) {
+	if obj.GroupVersionKind().GroupKind() != sealedSecretGK || IsSealedSecretRenamable(obj) {
+		a.names[ObjectName(obj)] = struct{}{}
+	}
+	if obj.GroupVersionKind() == certGVK {
+		// secret is created by cert-manager and referenced by chart workloads
+		if secretName, ok, _ := unstructured.NestedString(obj.Object, "spec", "secretName"); ok {
+			a.names[secretName] = struct{}{}
+		}
+	}
+	if obj.GroupVersionKind().GroupKind() == externalSecretGK {
+		// target secret is created by external-secrets operator
+		if secretName, ok, _ := unstructured.NestedString(obj.Object, "spec", "target", "name"); ok {
+			a.names[secretName] = struct{}{}
+		}
+	}
+	a.commonPrefix = detectCommonPrefix(obj, a.commonPrefix)
+}
+
+// TemplatedNamespace - converts namespace reference to its Helm templated representation according to namespace mode.
+// Empty namespace refers to the release namespace where resources without namespace are installed.
+func (a *Service) TemplatedNamespace(ns string) string {
+	switch {
+	case ns == "" || a.conf.NamespaceMode == config.NamespaceRelease:
+		return releaseNamespace
+	case a.conf.NamespaceMode == config.NamespaceKeep:
+		return ns
+	case ns == a.namespace:
+		return releaseNamespace
+	}
+	return ns
+}
+
 // Namespace returns detected app namespace.
 func (a *Service) Namespace() string {
 	return a.namespace
@@ -91,11 +195,81 @@ func (a *Service) TemplatedName(name string) string {
 	return fmt.Sprintf(nameTeml, a.conf.ChartName, name)
 }
 
+// HasConfig - returns true if ConfigMap or Secret with given kind and name is loaded, i.e. it has its own chart template.
+func (a *Service) HasConfig(kind, name string) bool {
+	_, contains := a.configs[kind+"/"+name]
+	return contains && !a.IsDependency(name)
+}
+
+// IsDependency - returns true if object with given name is replaced by chart dependency, i.e. its trimmed name
+// equals dependency name or starts with dependency name followed by a dash.
+// Dependencies without repository are subcharts generated from chart resources and replace nothing.
+func (a *Service) IsDependency(name string) bool {
+	trimmed := a.TrimName(name)
+	for _, dep := range a.conf.Dependencies {
+		if dep.Repository == "" {
+			continue
+		}
+		if trimmed == dep.Name || strings.HasPrefix(trimmed, dep.Name+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAutoscaled - returns true if loaded HorizontalPodAutoscaler targets workload with given kind and name.
+func (a *Service) IsAutoscaled(kind, name string) bool {
+	_, contains := a.autoscaled[kind+"/"+name]
+	return contains
+}
+
+// KindCount - returns number of loaded objects of given kind.
+func (a *Service) KindCount(kind string) int {
+	return a.kinds[kind]
+}
+
+// TemplatedSecretName - converts Secret name to its Helm templated representation.
+// Chart Secret name is taken from <name>.existingSecret value if set and existing secrets are enabled.
+func (a *Service) TemplatedSecretName(name string) string {
+	if !a.conf.ExistingSecrets || !a.HasConfig("Secret", name) {
+		return a.TemplatedName(name)
+	}
+	trimmed := a.TrimName(name)
+	return fmt.Sprintf(existingSecretNameTeml, strcase.ToLowerCamel(trimmed), a.conf.ChartName, trimmed)
+}
+
+// TemplatedServiceAccountName - converts ServiceAccount name to its Helm templated representation.
+// If the chart has a single ServiceAccount, its name is defined by serviceAccountName helper from _helpers.tpl.
+// The helper reads serviceAccount values, so it is not used for charts without values.
+func (a *Service) TemplatedServiceAccountName(name string) string {
+	// ServiceAccount helper names would be the same in all subcharts
+	if len(a.serviceAccounts) == 1 && a.serviceAccounts[0] == name && !a.conf.Subcharts && !a.conf.NoValues {
+		return fmt.Sprintf(serviceAccountNameTeml, a.conf.ChartName)
+	}
+	return a.TemplatedName(name)
+}
+
 func (a *Service) TemplatedString(str string) string {
 	name := a.TrimName(str)
 	return fmt.Sprintf(nameTeml, a.conf.ChartName, name)
 }
 
+// ObjectName returns object name. For objects with metadata.generateName returns generated name prefix without trailing dash.
+func ObjectName(obj *unstructured.Unstructured) string {
+	if obj.GetName() == "" && obj.GetGenerateName() != "" {
+		return strings.TrimRight(obj.GetGenerateName(), "-")
+	}
+	return obj.GetName()
+}
+
+// IsSealedSecretRenamable returns true if SealedSecret encrypted data is not bound to the secret name.
+// Names of strict scope SealedSecrets are not templated.
+func IsSealedSecretRenamable(obj *unstructured.Unstructured) bool {
+	annotations := obj.GetAnnotations()
+	return annotations["sealedsecrets.bitnami.com/cluster-wide"] == "true" ||
+		annotations["sealedsecrets.bitnami.com/namespace-wide"] == "true"
+}
+
 func extractAppNamespace(obj *unstructured.Unstructured) string {
 	if obj.GroupVersionKind() == nsGVK {
 		return obj.GetName()
@@ -108,9 +282,9 @@ func detectCommonPrefix(obj *unstructured.Unstructured, prevName string) string
 		return prevName
 	}
 	if prevName == "" {
-		return obj.GetName()
+		return ObjectName(obj)
 	}
-	return commonPrefix(obj.GetName(), prevName)
+	return commonPrefix(ObjectName(obj), prevName)
 }
 
 func commonPrefix(one, two string) string {
@@ -115,9 +115,137 @@ func Test_Service(t *testing.T) {
 		assert.Equal(t, "qwe", testSvc.TemplatedName("qwe"))
 		assert.NotEqual(t, "abc", testSvc.TemplatedName("abc"))
 	})
+	t.Run("template service account name", func(t *testing.T) {
+		testSvc := New(config.Config{ChartName: "chart-name"})
+		sa := createRes("abc-sa", "ns")
+		sa.SetKind("ServiceAccount")
+		testSvc.Load(sa)
+		testSvc.Load(createRes("abc-deploy", "ns"))
+		assert.Equal(t, `{{ include "chart-name.serviceAccountName" . }}`, testSvc.TemplatedServiceAccountName("abc-sa"))
+		assert.Equal(t, "default", testSvc.TemplatedServiceAccountName("default"))
+
+		sa2 := createRes("abc-sa2", "ns")
+		sa2.SetKind("ServiceAccount")
+		testSvc.Load(sa2)
+		assert.Equal(t, `{{ include "chart-name.fullname" . }}-sa`, testSvc.TemplatedServiceAccountName("abc-sa"))
+	})
+	t.Run("template service account name without values", func(t *testing.T) {
+		testSvc := New(config.Config{ChartName: "chart-name", NoValues: true})
+		sa := createRes("abc-sa", "ns")
+		sa.SetKind("ServiceAccount")
+		testSvc.Load(sa)
+		testSvc.Load(createRes("abc-deploy", "ns"))
+		assert.Equal(t, `{{ include "chart-name.fullname" . }}-sa`, testSvc.TemplatedServiceAccountName("abc-sa"))
+	})
+	t.Run("template certificate secret name", func(t *testing.T) {
+		testSvc := New(config.Config{ChartName: "chart-name"})
+		testSvc.Load(internal.GenerateObj(`apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: abc-cert
+  namespace: ns
+spec:
+  secretName: abc-tls`))
+		testSvc.Load(createRes("abc-deploy", "ns"))
+		assert.Equal(t, `{{ include "chart-name.fullname" . }}-tls`, testSvc.TemplatedName("abc-tls"))
+		assert.False(t, testSvc.HasConfig("Secret", "abc-tls"))
+	})
+	t.Run("has config", func(t *testing.T) {
+		testSvc := New(config.Config{ChartName: "chart-name"})
+		testSvc.Load(createRes("abc-secret", "ns"))
+		assert.True(t, testSvc.HasConfig("Secret", "abc-secret"))
+		assert.False(t, testSvc.HasConfig("ConfigMap", "abc-secret"))
+		assert.False(t, testSvc.HasConfig("Secret", "abc"))
+	})
 }
 
 func createRes(name, ns string) *unstructured.Unstructured {
 	objYaml := fmt.Sprintf(res, name, ns)
 	return internal.GenerateObj(objYaml)
 }
+
+func Test_Service_IsAutoscaled(t *testing.T) {
+	testSvc := New(config.Config{ChartName: "chart-name"})
+	testSvc.Load(internal.GenerateObj(`apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: abc-hpa
+  namespace: ns
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: abc-web`))
+	assert.True(t, testSvc.IsAutoscaled("Deployment", "abc-web"))
+	assert.False(t, testSvc.IsAutoscaled("StatefulSet", "abc-web"))
+	assert.False(t, testSvc.IsAutoscaled("Deployment", "abc-db"))
+}
+
+func Test_Service_TemplatedSecretName(t *testing.T) {
+	testSvc := New(config.Config{ChartName: "chart-name", ExistingSecrets: true})
+	testSvc.Load(createRes("abc-secret", "ns"))
+	testSvc.Load(createRes("abc-deploy", "ns"))
+	assert.Equal(t, `{{ .Values.secret.existingSecret | default (print (include "chart-name.fullname" .) "-secret") }}`, testSvc.TemplatedSecretName("abc-secret"))
+	assert.Equal(t, "other", testSvc.TemplatedSecretName("other"))
+
+	testSvc = New(config.Config{ChartName: "chart-name"})
+	testSvc.Load(createRes("abc-secret", "ns"))
+	testSvc.Load(createRes("abc-deploy", "ns"))
+	assert.Equal(t, `{{ include "chart-name.fullname" . }}-secret`, testSvc.TemplatedSecretName("abc-secret"))
+}
+
+func Test_Service_IsDependency(t *testing.T) {
+	testSvc := New(config.Config{ChartName: "chart-name", Dependencies: []config.Dependency{{Name: "postgresql", Repository: "@bitnami"}, {Name: "web"}}})
+	testSvc.Load(createRes("abc-web", "ns"))
+	testSvc.Load(createRes("abc-postgresql", "ns"))
+	testSvc.Load(createRes("abc-postgresql-hl", "ns"))
+	testSvc.Load(createRes("abc-postgresqlx", "ns"))
+	assert.False(t, testSvc.IsDependency("abc-web"))
+	assert.True(t, testSvc.IsDependency("abc-postgresql"))
+	assert.True(t, testSvc.IsDependency("abc-postgresql-hl"))
+	assert.False(t, testSvc.IsDependency("abc-postgresqlx"))
+	assert.False(t, testSvc.HasConfig("Secret", "abc-postgresql"))
+	assert.True(t, testSvc.HasConfig("Secret", "abc-web"))
+	assert.Equal(t, `{{ include "chart-name.fullname" . }}-postgresql`, testSvc.TemplatedName("abc-postgresql"))
+}
+
+func Test_Service_LoadReference(t *testing.T) {
+	testSvc := New(config.Config{ChartName: "chart-name", Subcharts: true})
+	testSvc.Load(createRes("abc-web", "ns"))
+	testSvc.LoadReference(createRes("abc-api", "other-ns"))
+	assert.Equal(t, "ns", testSvc.Namespace())
+	assert.Equal(t, `{{ include "chart-name.fullname" . }}-api`, testSvc.TemplatedName("abc-api"))
+	assert.Equal(t, "api", testSvc.TrimName("abc-api"))
+}
+
+func Test_Service_TrimName_affixes(t *testing.T) {
+	testSvc := New(config.Config{ChartName: "chart-name", NamePrefix: "prod-", NameSuffix: "-v2"})
+	testSvc.Load(createRes("prod-web-v2", "ns"))
+	assert.Equal(t, "web", testSvc.TrimName("prod-web-v2"))
+	assert.Equal(t, "web-config-v2-5h2k8", testSvc.TrimName("prod-web-config-v2-5h2k8"))
+	assert.Equal(t, "other", testSvc.TrimName("other"))
+	assert.Equal(t, `{{ include "chart-name.fullname" . }}-web`, testSvc.TemplatedName("prod-web-v2"))
+}
+
+func Test_Service_TemplatedNamespace(t *testing.T) {
+	for mode, expected := range map[string][]string{
+		"":                      {"{{ .Release.Namespace }}", "{{ .Release.Namespace }}", "other"},
+		config.NamespaceDrop:    {"{{ .Release.Namespace }}", "{{ .Release.Namespace }}", "other"},
+		config.NamespaceKeep:    {"{{ .Release.Namespace }}", "ns", "other"},
+		config.NamespaceRelease: {"{{ .Release.Namespace }}", "{{ .Release.Namespace }}", "{{ .Release.Namespace }}"},
+	} {
+		testSvc := New(config.Config{ChartName: "chart-name", NamespaceMode: mode})
+		testSvc.Load(createRes("abc-web", "ns"))
+		assert.Equal(t, expected, []string{testSvc.TemplatedNamespace(""), testSvc.TemplatedNamespace("ns"), testSvc.TemplatedNamespace("other")}, mode)
+	}
+}
+
+func Test_Service_KindCount(t *testing.T) {
+	testSvc := New(config.Config{})
+	testSvc.Load(internal.GenerateObj("apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: web"))
+	testSvc.Load(internal.GenerateObj("apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: api"))
+	testSvc.Load(internal.TestNs)
+	assert.Equal(t, 2, testSvc.KindCount("Ingress"))
+	assert.Equal(t, 1, testSvc.KindCount("Namespace"))
+	assert.Equal(t, 0, testSvc.KindCount("Service"))
+}
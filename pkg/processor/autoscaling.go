@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"github.com/arttor/helmify/pkg/helmify"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// GuardAutoscaledReplicas - skips templated workload replicas if autoscaling.enabled value is set and
+// the workload is targeted by chart HorizontalPodAutoscaler, so Helm does not reset replicas managed by HPA.
+// replicas is expected to be a single marshaled replicas field with 2 spaces indent.
+func GuardAutoscaledReplicas(appMeta helmify.AppMetadata, obj *unstructured.Unstructured, replicas string, values *helmify.Values) (string, error) {
+	if replicas == "" || !appMeta.IsAutoscaled(obj.GetKind(), obj.GetName()) {
+		return replicas, nil
+	}
+	_, err := values.Add(true, "autoscaling", "enabled")
+	if err != nil {
+		return "", err
+	}
+	return "  {{- if not .Values.autoscaling.enabled }}\n" + replicas + "\n  {{- end }}", nil
+}
+
+// TemplateAutoscaling - renders HorizontalPodAutoscaler template only if autoscaling.enabled value is set.
+func TemplateAutoscaling(tpl string, values *helmify.Values) (string, error) {
+	_, err := values.Add(true, "autoscaling", "enabled")
+	if err != nil {
+		return "", err
+	}
+	return "{{- if .Values.autoscaling.enabled }}\n" + tpl + "\n{{- end }}", nil
+}
@@ -0,0 +1,28 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// InjectCAAnnotation - cert-manager annotation referencing a Certificate whose CA is injected into the object.
+const InjectCAAnnotation = "cert-manager.io/inject-ca-from"
+
+// CertsHelperName - returns name of the helper generating certificates in place of given cert-manager Certificate.
+func CertsHelperName(appMeta helmify.AppMetadata, certName string) string {
+	return fmt.Sprintf("%s.%s.certs", appMeta.ChartName(), appMeta.TrimName(certName))
+}
+
+// CABundle - returns caBundle template for the object with cert-manager CA injection annotation.
+// CA is taken from certificates generated by the Certificate helper. Returns empty string if the object has no such annotation.
+func CABundle(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) string {
+	certName := obj.GetAnnotations()[InjectCAAnnotation]
+	if certName == "" {
+		return ""
+	}
+	certName = strings.TrimPrefix(certName, appMeta.Namespace()+"/")
+	return fmt.Sprintf(`{{ index (include "%s" . | fromYaml) "ca.crt" }}`, CertsHelperName(appMeta, certName))
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// checksumAnnotationTempl - pod annotation with checksum of rendered chart template. %[1]s - indent, %[2]s - template name.
+const checksumAnnotationTempl = `
+%[1]schecksum/%[2]s: {{ include (print $.Template.BasePath "/%[2]s.yaml") . | sha256sum }}`
+
+// AddConfigChecksums - adds 'checksum/<name>' pod annotations for chart ConfigMaps and Secrets used by given pod spec,
+// so config changes trigger workload rollout. podAnnotations is expected to be marshaled with given indent
+// and start with a newline if not empty. Pod spec object names must not be templated yet.
+func AddConfigChecksums(appMeta helmify.AppMetadata, podSpec corev1.PodSpec, podAnnotations string, indent int) string {
+	if !appMeta.Config().ConfigChecksum {
+		return podAnnotations
+	}
+	files := map[string]struct{}{}
+	addConfig := func(kind, name string) {
+		if appMeta.HasConfig(kind, name) {
+			files[appMeta.TrimName(name)] = struct{}{}
+		}
+	}
+	for _, v := range podSpec.Volumes {
+		if v.ConfigMap != nil {
+			addConfig("ConfigMap", v.ConfigMap.Name)
+		}
+		if v.Secret != nil {
+			addConfig("Secret", v.Secret.SecretName)
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, s := range v.Projected.Sources {
+			if s.ConfigMap != nil {
+				addConfig("ConfigMap", s.ConfigMap.Name)
+			}
+			if s.Secret != nil {
+				addConfig("Secret", s.Secret.Name)
+			}
+		}
+	}
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, c := range containers {
+		for _, e := range c.EnvFrom {
+			if e.ConfigMapRef != nil {
+				addConfig("ConfigMap", e.ConfigMapRef.Name)
+			}
+			if e.SecretRef != nil {
+				addConfig("Secret", e.SecretRef.Name)
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if e.ValueFrom.ConfigMapKeyRef != nil {
+				addConfig("ConfigMap", e.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if e.ValueFrom.SecretKeyRef != nil {
+				addConfig("Secret", e.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return podAnnotations
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if podAnnotations == "" {
+		podAnnotations = "\n" + strings.Repeat(" ", indent) + "annotations:"
+	}
+	for _, name := range names {
+		podAnnotations += fmt.Sprintf(checksumAnnotationTempl, strings.Repeat(" ", indent+2), name)
+	}
+	return podAnnotations
+}
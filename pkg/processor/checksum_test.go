@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	checksumConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config
+  namespace: my-app-system`
+	checksumSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: my-app-secret
+  namespace: my-app-system`
+)
+
+func TestAddConfigChecksums(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "my-app-config"},
+			}}},
+			{Name: "ca", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+			}}},
+		},
+		Containers: []corev1.Container{{
+			Name: "app",
+			Env: []corev1.EnvVar{{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "my-app-secret"},
+				Key:                  "password",
+			}}}},
+			EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "my-app-config"},
+			}}},
+		}},
+	}
+	newMeta := func(conf config.Config) *metadata.Service {
+		meta := metadata.New(conf)
+		meta.Load(internal.GenerateObj(checksumConfigMap))
+		meta.Load(internal.GenerateObj(checksumSecret))
+		return meta
+	}
+	t.Run("disabled", func(t *testing.T) {
+		res := AddConfigChecksums(newMeta(config.Config{}), podSpec, "", 6)
+		assert.Equal(t, "", res)
+	})
+	t.Run("enabled", func(t *testing.T) {
+		res := AddConfigChecksums(newMeta(config.Config{ConfigChecksum: true}), podSpec, "", 6)
+		assert.Equal(t, `
+      annotations:
+        checksum/config: {{ include (print $.Template.BasePath "/config.yaml") . | sha256sum }}
+        checksum/secret: {{ include (print $.Template.BasePath "/secret.yaml") . | sha256sum }}`, res)
+	})
+	t.Run("append to existing annotations", func(t *testing.T) {
+		res := AddConfigChecksums(newMeta(config.Config{ConfigChecksum: true}), podSpec, "\n  annotations:\n    a: b", 2)
+		assert.Equal(t, `
+  annotations:
+    a: b
+    checksum/config: {{ include (print $.Template.BasePath "/config.yaml") . | sha256sum }}
+    checksum/secret: {{ include (print $.Template.BasePath "/secret.yaml") . | sha256sum }}`, res)
+	})
+	t.Run("no chart configs", func(t *testing.T) {
+		res := AddConfigChecksums(metadata.New(config.Config{ConfigChecksum: true}), podSpec, "", 6)
+		assert.Equal(t, "", res)
+	})
+}
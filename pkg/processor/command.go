@@ -0,0 +1,18 @@
+package processor
+
+import "github.com/arttor/helmify/pkg/helmify"
+
+// TemplateCommand - moves containers command and args to <name>.<container name>.{command,args} values
+// if enabled by config. Pod spec is expected to be marshaled with given indent.
+func TemplateCommand(appMeta helmify.AppMetadata, name string, podSpec map[string]interface{}, indent int, values *helmify.Values) error {
+	if !appMeta.Config().CommandValues {
+		return nil
+	}
+	for _, field := range []string{"command", "args"} {
+		err := templateContainersField(name, field, "", podSpec, indent, values)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
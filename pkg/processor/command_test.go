@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplateCommand(t *testing.T) {
+	newPodSpec := func() map[string]interface{} {
+		return map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":    "app",
+					"image":   "nginx",
+					"command": []interface{}{"/manager"},
+					"args":    []interface{}{"--leader-elect", "--metrics-bind-address=127.0.0.1:8080"},
+				},
+			},
+		}
+	}
+	t.Run("disabled", func(t *testing.T) {
+		podSpec, values := newPodSpec(), helmify.Values{}
+		err := TemplateCommand(metadata.New(config.Config{}), "web", podSpec, 0, &values)
+		assert.NoError(t, err)
+		assert.Empty(t, values)
+		assert.Equal(t, newPodSpec(), podSpec)
+	})
+	t.Run("enabled", func(t *testing.T) {
+		podSpec, values := newPodSpec(), helmify.Values{}
+		err := TemplateCommand(metadata.New(config.Config{CommandValues: true}), "web", podSpec, 0, &values)
+		assert.NoError(t, err)
+		assert.Equal(t, helmify.Values{"web": map[string]interface{}{"app": map[string]interface{}{
+			"command": []interface{}{"/manager"},
+			"args":    []interface{}{"--leader-elect", "--metrics-bind-address=127.0.0.1:8080"},
+		}}}, values)
+
+		spec, err := yaml.Marshal(podSpec, 0)
+		assert.NoError(t, err)
+		rendered, err := internal.RenderTemplate("chart-name", strings.ReplaceAll(spec, "'", ""), values)
+		assert.NoError(t, err)
+		res := corev1.PodSpec{}
+		assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+		assert.Equal(t, []string{"/manager"}, res.Containers[0].Command)
+		assert.Equal(t, []string{"--leader-elect", "--metrics-bind-address=127.0.0.1:8080"}, res.Containers[0].Args)
+	})
+}
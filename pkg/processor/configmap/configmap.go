@@ -1,8 +1,12 @@
 package configmap
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -68,8 +72,18 @@ func (d configMap) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstru
 
 	name := appMeta.TrimName(obj.GetName())
 	var values helmify.Values
+	var files map[string][]byte
 	if field, exists, _ := unstructured.NestedStringMap(obj.Object, "data"); exists {
-		field, values = parseMapData(field, name)
+		var filesData map[string]string
+		filesData, files = extractFiles(field, obj.GetName(), name, appMeta.Config().FilesGet, appMeta.Config().FilesGetSize)
+		if isRaw(obj.GetName(), appMeta.Config().RawConfigMaps) {
+			field, values = rawMapData(field, name)
+		} else {
+			field, values = parseMapData(field, name, appMeta.Config().ConfigMapTypes)
+		}
+		for key, templated := range filesData {
+			field[key] = templated
+		}
 		data, err = yamlformat.Marshal(map[string]interface{}{"data": field}, 0)
 		if err != nil {
 			return true, nil, err
@@ -86,10 +100,73 @@ func (d configMap) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstru
 			Data       string
 		}{Meta: meta, Immutable: immutable, BinaryData: binaryData, Data: data},
 		values: values,
+		files:  files,
 	}, nil
 }
 
-func parseMapData(data map[string]string, configName string) (map[string]string, helmify.Values) {
+// extractFiles - removes data keys listed in filesGet as '<configmap name>/<key>' from data.
+// Keys with content larger than sizeThreshold bytes are removed too if sizeThreshold is positive.
+// Returns templated data reading removed keys from chart files and the files content.
+func extractFiles(data map[string]string, objName, name string, filesGet []string, sizeThreshold int) (map[string]string, map[string][]byte) {
+	keys := map[string]struct{}{}
+	for _, ref := range filesGet {
+		key := strings.TrimPrefix(ref, objName+"/")
+		if key == ref {
+			continue
+		}
+		if _, ok := data[key]; !ok {
+			logrus.Warnf("configmap %s has no key %s", objName, key)
+			continue
+		}
+		keys[key] = struct{}{}
+	}
+	if sizeThreshold > 0 {
+		for key, value := range data {
+			if len(value) > sizeThreshold {
+				keys[key] = struct{}{}
+			}
+		}
+	}
+	templated := map[string]string{}
+	files := map[string][]byte{}
+	for key := range keys {
+		path := "files/" + name + "/" + key
+		files[path] = []byte(data[key])
+		templated[key] = fmt.Sprintf(`{{ .Files.Get "%s" | quote }}`, path)
+		delete(data, key)
+	}
+	return templated, files
+}
+
+// isRaw - returns true if ConfigMap is listed in rawConfigMaps or the list contains '*'.
+func isRaw(objName string, rawConfigMaps []string) bool {
+	for _, raw := range rawConfigMaps {
+		if raw == "*" || raw == objName {
+			return true
+		}
+	}
+	return false
+}
+
+// rawMapData - moves data values to values as is. Values are rendered with 'tpl', so template actions
+// in the original content are escaped to be rendered literally.
+func rawMapData(data map[string]string, configName string) (map[string]string, helmify.Values) {
+	values := helmify.Values{}
+	for key, value := range data {
+		templated, err := values.Add(helmify.EscapeActions(value), configName, key)
+		if err != nil {
+			logrus.WithError(err).Errorf("unable to process configmap data: %v", []string{configName, key})
+			continue
+		}
+		// templated is '{{ .Values.<path> | quote }}'
+		data[key] = "{{ tpl " + strings.Fields(templated)[1] + " . | quote }}"
+	}
+	return data, values
+}
+
+// parseMapData - moves data values to values. Set inferTypes to store numeric and boolean looking
+// values typed instead of strings. Templated data is always quoted because ConfigMap data is a string map.
+func parseMapData(data map[string]string, configName string, inferTypes bool) (map[string]string, helmify.Values) {
 	values := helmify.Values{}
 	for key, value := range data {
 		valuesNamePath := []string{configName, key}
@@ -102,8 +179,29 @@ func parseMapData(data map[string]string, configName string) (map[string]string,
 			data[key] = templated
 			continue
 		}
+		if strings.HasSuffix(key, ".json") {
+			templated, err := parseJSON(value, valuesNamePath, values)
+			if err != nil {
+				logrus.WithError(err).Errorf("unable to process configmap data: %v", valuesNamePath)
+				continue
+			}
+			data[key] = templated
+			continue
+		}
+		if strings.HasSuffix(key, ".toml") {
+			data[key] = parseTOML(value, valuesNamePath, values)
+			continue
+		}
+		if strings.HasSuffix(key, ".ini") {
+			data[key] = parseINI(value, valuesNamePath, values)
+			continue
+		}
+		if strings.HasSuffix(key, ".env") {
+			data[key] = parseEnv(value, valuesNamePath, values, inferTypes)
+			continue
+		}
 		if strings.HasSuffix(key, ".properties") {
-			templated, err := parseProperties(value, valuesNamePath, values)
+			templated, err := parseProperties(value, valuesNamePath, values, inferTypes)
 			if err != nil {
 				logrus.WithError(err).Errorf("unable to process configmap data: %v", valuesNamePath)
 				continue
@@ -111,22 +209,55 @@ func parseMapData(data map[string]string, configName string) (map[string]string,
 			data[key] = templated
 			continue
 		}
-		templatedVal, err := values.Add(value, valuesNamePath...)
+		if !inferTypes {
+			templatedVal, err := values.Add(value, valuesNamePath...)
+			if err != nil {
+				logrus.WithError(err).Errorf("unable to process configmap data: %v", valuesNamePath)
+				continue
+			}
+			data[key] = templatedVal
+			continue
+		}
+		templatedVal, err := values.Add(inferType(value), valuesNamePath...)
 		if err != nil {
 			logrus.WithError(err).Errorf("unable to process configmap data: %v", valuesNamePath)
 			continue
 		}
+		if !strings.HasSuffix(templatedVal, "| quote }}") {
+			templatedVal = strings.TrimSuffix(templatedVal, " }}") + " | quote }}"
+		}
 		data[key] = templatedVal
 	}
 	return data, values
 }
 
+// inferType - converts integer, float and boolean strings to typed values.
+// Values which would not be converted back to the same string, e.g. '0123' or '1.50', are kept as strings.
+func inferType(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(i, 10) == value {
+		return i
+	}
+	if !strings.ContainsAny(value, ".") {
+		return value
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == value {
+		return f
+	}
+	return value
+}
+
+// parseYaml - moves scalar fields of YAML config to values. Template marks are restored before parsing and
+// set again per string field, because marks of the whole config are not valid YAML.
 func parseYaml(value string, path []string, values helmify.Values) (string, error) {
 	config := map[string]interface{}{}
-	err := yaml.Unmarshal([]byte(value), &config)
+	err := yaml.Unmarshal([]byte(helmify.RestoreTemplates(value)), &config)
 	if err != nil {
 		return "", errors.Wrapf(err, "unable to unmarshal configmap %v", path)
 	}
+	helmify.MarkTemplates(config)
 	parseConfig(config, values, path)
 	confBytes, err := yaml.Marshal(config)
 	if err != nil {
@@ -135,30 +266,105 @@ func parseYaml(value string, path []string, values helmify.Values) (string, erro
 	return string(confBytes), nil
 }
 
-func parseProperties(properties string, path []string, values helmify.Values) (string, error) {
+// jsonTemplateRegexp - matches quoted templated values in marshaled JSON config.
+var jsonTemplateRegexp = regexp.MustCompile(`"(\{\{ \.Values\.\S+ \| toJson \}\})"`)
+
+// parseJSON - moves scalar and array fields of JSON object to values. Values are rendered with 'toJson' to keep JSON types.
+// Result is indented JSON with sorted keys. Template marks are restored before parsing like in parseYaml.
+func parseJSON(value string, path []string, values helmify.Values) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(helmify.RestoreTemplates(value)))
+	decoder.UseNumber()
+	config := map[string]interface{}{}
+	err := decoder.Decode(&config)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to unmarshal configmap %v", path)
+	}
+	config = jsonNumbers(config).(map[string]interface{})
+	helmify.MarkTemplates(config)
+	parseJSONConfig(config, values, path)
+	var res bytes.Buffer
+	encoder := json.NewEncoder(&res)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(config)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to marshal configmap %v", path)
+	}
+	return jsonTemplateRegexp.ReplaceAllString(res.String(), "$1"), nil
+}
+
+// jsonNumbers - converts json.Number values to int64 or float64.
+func jsonNumbers(value interface{}) interface{} {
+	switch t := value.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, v := range t {
+			t[k] = jsonNumbers(v)
+		}
+	case []interface{}:
+		for i, v := range t {
+			t[i] = jsonNumbers(v)
+		}
+	}
+	return value
+}
+
+func parseJSONConfig(config map[string]interface{}, values helmify.Values, path []string) {
+	for k, v := range config {
+		valuePath := append(append([]string{}, path...), k)
+		switch t := v.(type) {
+		case string, bool, int64, float64, []interface{}:
+		case map[string]interface{}:
+			if len(t) != 0 {
+				parseJSONConfig(t, values, valuePath)
+				continue
+			}
+		default:
+			continue
+		}
+		templated, err := values.Add(v, valuePath...)
+		if err != nil {
+			logrus.WithError(err).Error()
+			continue
+		}
+		// templated is '{{ .Values.<path> }}' optionally followed by quote
+		config[k] = "{{ " + strings.Fields(templated)[1] + " | toJson }}"
+	}
+}
+
+func parseProperties(properties string, path []string, values helmify.Values, inferTypes bool) (string, error) {
 	var res strings.Builder
 	for _, line := range strings.Split(strings.TrimSuffix(properties, "\n"), "\n") {
 		prop := strings.Split(line, "=")
 		if len(prop) != 2 {
 			//return "", errors.Errorf("wrong property format in %v: %s", path, line)
-			logrus.Warn("wrong d property format in %s: %s, ignore..", path, line)
-			_, err := res.WriteString( line + "\n")
+			logrus.Warnf("wrong property format in %s: %s, ignore..", path, line)
+			_, err := res.WriteString(line + "\n")
 			if err != nil {
 				return "", errors.Wrap(err, "unable to write to string builder")
-			}	
+			}
 			continue
 		}
 		propName, propVal := prop[0], prop[1]
 		propNamePath := strings.Split(propName, ".")
-		propVal = strings.ReplaceAll(propVal,"{{", "\"{{\"")
-		templatedVal, err := values.Add(propVal, append(path, propNamePath...)...)
+		propVal = strings.ReplaceAll(propVal, "{{", "\"{{\"")
+		var value interface{} = propVal
+		if inferTypes {
+			value = inferType(propVal)
+		}
+		templatedVal, err := values.Add(value, append(path, propNamePath...)...)
 		if err != nil {
-			logrus.Warn("Can't templatize %s:%s at line %s ignore..", path,propName, line)
-			_, err := res.WriteString( line + "\n")
+			logrus.Warnf("Can't templatize %s:%s at line %s ignore..", path, propName, line)
+			_, err := res.WriteString(line + "\n")
 			if err != nil {
 				return "", errors.Wrap(err, "unable to write to string builder")
-			}	
-			continue			
+			}
+			continue
 		}
 		_, err = res.WriteString(propName + "=" + templatedVal + "\n")
 		if err != nil {
@@ -182,7 +388,13 @@ func parseConfig(config map[string]interface{}, values helmify.Values, path []st
 			}
 			config[k] = templated
 		case []interface{}:
-			logrus.Warn("configmap: arrays not supported")
+			templated, err := values.Add(v, append(path, k)...)
+			if err != nil {
+				logrus.WithError(err).Error()
+				continue
+			}
+			// JSON is a valid YAML flow sequence
+			config[k] = strings.TrimSuffix(templated, " }}") + " | toJson }}"
 		case map[string]interface{}:
 			if len(t) == 0 {
 				templated, err := values.Add(v, append(path, k)...)
@@ -217,12 +429,17 @@ type result struct {
 		Data       string
 	}
 	values helmify.Values
+	files  map[string][]byte
 }
 
 func (r *result) Filename() string {
 	return r.name
 }
 
+func (r *result) Files() map[string][]byte {
+	return r.files
+}
+
 func (r *result) Values() helmify.Values {
 	return r.values
 }
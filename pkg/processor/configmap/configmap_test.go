@@ -1,12 +1,18 @@
 package configmap
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -22,6 +28,11 @@ data:
     kind: ControllerManagerConfig
     health:
       healthProbeBindAddress: :8081`
+	strEmptyConfigmap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-empty-config
+  namespace: my-operator-system`
 )
 
 func Test_configMap_Process(t *testing.T) {
@@ -40,3 +51,343 @@ func Test_configMap_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_configMap_Process_empty(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(strEmptyConfigmap)
+	processed, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, true, processed)
+	assert.Empty(t, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte(`{{- include "chart-name.labels" . | nindent 4 }}`)))
+
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ConfigMap{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, "ConfigMap", res.Kind)
+	assert.Empty(t, res.Data)
+}
+
+func Test_configMap_Process_filesGet(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-nginx
+data:
+  nginx.conf: |
+    server {
+      listen 80;
+    }
+  mode: prod`)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name", FilesGet: []string{"my-operator-nginx/nginx.conf", "other/nginx.conf"}})
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `nginx.conf: {{ .Files.Get "files/my-operator-nginx/nginx.conf" | quote }}`)
+	assert.Contains(t, buf.String(), "mode: {{ .Values.myOperatorNginx.mode | quote }}")
+	assert.NotContains(t, tpl.Values()["myOperatorNginx"], "nginx.conf")
+
+	filesTpl, ok := tpl.(helmify.FilesTemplate)
+	assert.True(t, ok)
+	assert.Equal(t, map[string][]byte{
+		"files/my-operator-nginx/nginx.conf": []byte("server {\n  listen 80;\n}\n"),
+	}, filesTpl.Files())
+}
+
+func Test_configMap_Process_configMapTypes(t *testing.T) {
+	const strTypedConfigmap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-typed-config
+  namespace: my-operator-system
+data:
+  port: "8080"
+  ratio: "0.5"
+  debug: "true"
+  zip: "0123"`
+	for _, tt := range []struct {
+		name           string
+		configMapTypes bool
+		port           interface{}
+		ratio          interface{}
+		debug          interface{}
+	}{
+		{name: "strings", configMapTypes: false, port: "8080", ratio: "0.5", debug: "true"},
+		{name: "typed", configMapTypes: true, port: int64(8080), ratio: 0.5, debug: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var testInstance configMap
+			obj := internal.GenerateObj(strTypedConfigmap)
+			testMeta := metadata.New(config.Config{ChartName: "chart-name", ConfigMapTypes: tt.configMapTypes})
+			testMeta.Load(obj)
+			_, tpl, err := testInstance.Process(testMeta, obj)
+			assert.NoError(t, err)
+			assert.Equal(t, helmify.Values{"myOperatorTypedConfig": map[string]interface{}{
+				"port": tt.port, "ratio": tt.ratio, "debug": tt.debug, "zip": "0123",
+			}}, tpl.Values())
+
+			buf := bytes.Buffer{}
+			assert.NoError(t, tpl.Write(&buf))
+			assert.Contains(t, buf.String(), "port: {{ .Values.myOperatorTypedConfig.port | quote }}")
+			assert.Contains(t, buf.String(), "debug: {{ .Values.myOperatorTypedConfig.debug | quote }}")
+		})
+	}
+}
+
+func Test_configMap_Process_json(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-json-config
+  namespace: my-operator-system
+data:
+  app.json: |
+    {"server": {"port": 8080, "host": "0.0.0.0", "tls": false}, "ratio": 0.5, "labels": {}, "name": "a \"quoted\" <name>", "hosts": ["a", 1]}`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorJsonConfig": map[string]interface{}{"appJson": map[string]interface{}{
+		"server": map[string]interface{}{"port": int64(8080), "host": "0.0.0.0", "tls": false},
+		"ratio":  0.5,
+		"labels": map[string]interface{}{},
+		"name":   `a "quoted" <name>`,
+		"hosts":  []interface{}{"a", int64(1)},
+	}}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `"labels": {{ .Values.myOperatorJsonConfig.appJson.labels | toJson }},`)
+	tpl.Values()["myOperatorJsonConfig"].(map[string]interface{})["appJson"].(map[string]interface{})["server"].(map[string]interface{})["port"] = int64(9090)
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ConfigMap{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.JSONEq(t, `{"server": {"port": 9090, "host": "0.0.0.0", "tls": false}, "ratio": 0.5, "labels": {}, "name": "a \"quoted\" <name>", "hosts": ["a", 1]}`, res.Data["app.json"])
+}
+
+func Test_configMap_Process_tomlAndINI(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-files-config
+  namespace: my-operator-system
+data:
+  config.toml: |
+    # server config
+    title = "app"
+    [server]
+    port = 8080
+    debug = false
+    hosts = ["a", "b"]
+    motd = """
+    hello = world
+    """
+    [[plugins]]
+    name = "auth"
+  app.ini: |
+    ; global
+    mode = production
+    [database]
+    host = db.local
+    port = 5432`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorFilesConfig": map[string]interface{}{
+		"configToml": map[string]interface{}{
+			"title":  "app",
+			"server": map[string]interface{}{"port": int64(8080), "debug": false},
+		},
+		"appIni": map[string]interface{}{
+			"mode":     "production",
+			"database": map[string]interface{}{"host": "db.local", "port": "5432"},
+		},
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values := tpl.Values()["myOperatorFilesConfig"].(map[string]interface{})
+	values["configToml"].(map[string]interface{})["server"].(map[string]interface{})["port"] = int64(9090)
+	values["appIni"].(map[string]interface{})["database"].(map[string]interface{})["host"] = "db.prod"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ConfigMap{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, `# server config
+title = "app"
+[server]
+port = 9090
+debug = false
+hosts = ["a", "b"]
+motd = """
+hello = world
+"""
+[[plugins]]
+name = "auth"`, strings.TrimSpace(res.Data["config.toml"]))
+	assert.Equal(t, `; global
+mode = production
+[database]
+host = db.prod
+port = 5432`, res.Data["app.ini"])
+}
+
+func Test_configMap_Process_env(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-env-config
+  namespace: my-operator-system
+data:
+  app.env: |
+    # database
+    DB_HOST=db.local
+    export DB_PORT=5432 # default port
+    GREETING="hello world"
+    NAME='app'
+    MULTILINE="first
+    second"`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", ConfigMapTypes: true})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorEnvConfig": map[string]interface{}{
+		"appEnv": map[string]interface{}{
+			"dbHost":   "db.local",
+			"dbPort":   int64(5432),
+			"greeting": "hello world",
+			"name":     "app",
+		},
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values := tpl.Values()["myOperatorEnvConfig"].(map[string]interface{})["appEnv"].(map[string]interface{})
+	values["dbHost"] = "db.prod"
+	values["greeting"] = "hi"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ConfigMap{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, `# database
+DB_HOST=db.prod
+export DB_PORT=5432 # default port
+GREETING="hi"
+NAME='app'
+MULTILINE="first
+second"`, res.Data["app.env"])
+}
+
+func Test_configMap_Process_yamlArrays(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-list-config
+  namespace: my-operator-system
+data:
+  config.yaml: |
+    upstream:
+      servers:
+      - host: a.local
+        port: 80
+      - host: b.local
+        port: 80
+    endpoints: []`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	configYaml := tpl.Values()["myOperatorListConfig"].(map[string]interface{})["configYaml"].(map[string]interface{})
+	assert.Equal(t, []interface{}{}, configYaml["endpoints"])
+	assert.Len(t, configYaml["upstream"].(map[string]interface{})["servers"], 2)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	configYaml["endpoints"] = []interface{}{"/metrics"}
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ConfigMap{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	parsed := map[string]interface{}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(res.Data["config.yaml"]), &parsed))
+	assert.Equal(t, map[string]interface{}{
+		"endpoints": []interface{}{"/metrics"},
+		"upstream": map[string]interface{}{"servers": []interface{}{
+			map[string]interface{}{"host": "a.local", "port": float64(80)},
+			map[string]interface{}{"host": "b.local", "port": float64(80)},
+		}},
+	}, parsed)
+}
+
+func Test_configMap_Process_raw(t *testing.T) {
+	var testInstance configMap
+	const content = "greeting: Hello {{ .Name }}!\nport: '8080'"
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-raw-config
+  namespace: my-operator-system
+data:
+  config.yaml: |
+    greeting: Hello {{ .Name }}!
+    port: '8080'`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", RawConfigMaps: []string{"my-operator-raw-config"}})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorRawConfig": map[string]interface{}{
+		"configYaml": "greeting: Hello {{\"{{\"}} .Name {{\"}}\"}}!\nport: '8080'",
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), "config.yaml: {{ tpl .Values.myOperatorRawConfig.configYaml . | quote }}")
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ConfigMap{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, content, res.Data["config.yaml"])
+
+	tpl.Values()["myOperatorRawConfig"].(map[string]interface{})["configYaml"] = "release: {{ .Release.Name }}"
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, "release: release", res.Data["config.yaml"])
+}
+
+func Test_configMap_Process_filesGetSize(t *testing.T) {
+	var testInstance configMap
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-operator-nginx
+data:
+  nginx.conf: |
+    server {
+      listen 80;
+    }
+  mode: prod`)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name", FilesGetSize: 10})
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `nginx.conf: {{ .Files.Get "files/my-operator-nginx/nginx.conf" | quote }}`)
+	assert.Contains(t, buf.String(), "mode: {{ .Values.myOperatorNginx.mode | quote }}")
+	assert.Equal(t, map[string][]byte{
+		"files/my-operator-nginx/nginx.conf": []byte("server {\n  listen 80;\n}\n"),
+	}, tpl.(helmify.FilesTemplate).Files())
+}
@@ -0,0 +1,79 @@
+package configmap
+
+import (
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+)
+
+// parseEnv - moves 'KEY=VALUE' variables of dotenv file to values. Optional 'export' prefix and value quotes are kept.
+// Set inferTypes to store numeric and boolean looking unquoted values typed. Comments, multi-line values and lines
+// which can not be parsed are kept unchanged.
+func parseEnv(env string, path []string, values helmify.Values, inferTypes bool) string {
+	var res strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(env, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		eq := strings.Index(line, "=")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || eq < 0 {
+			res.WriteString(line + "\n")
+			continue
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[:eq]), "export "))
+		value, quote, comment, ok := envValue(line[eq+1:])
+		if key == "" || strings.ContainsAny(key, " \t") || !ok {
+			res.WriteString(line + "\n")
+			continue
+		}
+		var typed interface{} = value
+		if inferTypes && quote == "" {
+			typed = inferType(value)
+		}
+		valuePath := append(append([]string{}, path...), key)
+		templated, err := values.Add(typed, valuePath...)
+		if err != nil {
+			logrus.Warnf("Can't templatize %v at line %s ignore..", valuePath, line)
+			res.WriteString(line + "\n")
+			continue
+		}
+		// quotes of original value are kept to render the same dotenv syntax
+		templated = strings.TrimSuffix(templated, " | quote }}")
+		templated = strings.TrimSuffix(templated, " }}")
+		switch quote {
+		case `"`:
+			templated += " | quote"
+		case "'":
+			templated += " | squote"
+		}
+		res.WriteString(line[:eq+1] + templated + " }}" + comment + "\n")
+	}
+	if !strings.HasSuffix(env, "\n") {
+		return strings.TrimSuffix(res.String(), "\n")
+	}
+	return res.String()
+}
+
+// envValue - parses dotenv variable value. Returns value without quotes, quote character and trailing comment.
+// Returns false for quoted values with escapes or without closing quote, e.g. multi-line values.
+func envValue(raw string) (value, quote, comment string, ok bool) {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, `"`) || strings.HasPrefix(trimmed, "'") {
+		quote = trimmed[:1]
+		end := strings.Index(trimmed[1:], quote)
+		if end < 0 || strings.Contains(trimmed[1:end+1], `\`) {
+			return "", "", "", false
+		}
+		rest := trimmed[end+2:]
+		if strings.TrimSpace(rest) != "" && !strings.HasPrefix(strings.TrimSpace(rest), "#") {
+			return "", "", "", false
+		}
+		return trimmed[1 : end+1], quote, rest, true
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw, comment = raw[:i], raw[i:]
+	}
+	if strings.ContainsAny(raw, `"'`) {
+		return "", "", "", false
+	}
+	return strings.TrimSpace(raw), "", comment, true
+}
@@ -0,0 +1,43 @@
+package configmap
+
+import (
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+)
+
+// parseINI - moves 'key = value' pairs of INI file to values under section path.
+// Values are rendered as is, comments and lines which can not be parsed are kept unchanged.
+func parseINI(ini string, path []string, values helmify.Values) string {
+	var res strings.Builder
+	var section []string
+	for _, line := range strings.Split(strings.TrimSuffix(ini, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.Split(strings.Trim(trimmed, "[] "), ".")
+			res.WriteString(line + "\n")
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") || eq < 0 {
+			res.WriteString(line + "\n")
+			continue
+		}
+		key, value := strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
+		valuePath := append(append(append([]string{}, path...), section...), key)
+		templated, err := values.Add(value, valuePath...)
+		if err != nil {
+			logrus.Warnf("Can't templatize %v at line %s ignore..", valuePath, line)
+			res.WriteString(line + "\n")
+			continue
+		}
+		// INI values are not quoted
+		templated = strings.TrimSuffix(templated, " | quote }}") + " }}"
+		res.WriteString(line[:eq+1] + " " + templated + "\n")
+	}
+	if !strings.HasSuffix(ini, "\n") {
+		return strings.TrimSuffix(res.String(), "\n")
+	}
+	return res.String()
+}
@@ -0,0 +1,105 @@
+package configmap
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/sirupsen/logrus"
+)
+
+// parseTOML - moves scalar 'key = value' pairs of TOML file to values under table path.
+// Strings, integers, floats and booleans are supported. Arrays, inline tables, dates, multi-line strings,
+// arrays of tables and comments are kept unchanged.
+func parseTOML(toml string, path []string, values helmify.Values) string {
+	var res strings.Builder
+	var table []string
+	// skip - true inside array of tables which can not be mapped to values
+	skip := false
+	lines := strings.Split(strings.TrimSuffix(toml, "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "[["):
+			skip = true
+		case strings.HasPrefix(trimmed, "[") && strings.Contains(trimmed, "]"):
+			skip = false
+			table = tomlKey(trimmed[1:strings.Index(trimmed, "]")])
+		}
+		eq := strings.Index(line, "=")
+		if skip || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "[") || eq < 0 {
+			res.WriteString(line + "\n")
+			continue
+		}
+		rawValue := strings.TrimSpace(line[eq+1:])
+		if delim := tomlMultilineDelim(rawValue); delim != "" {
+			// keep multi-line string as is
+			res.WriteString(line + "\n")
+			for i+1 < len(lines) {
+				i++
+				res.WriteString(lines[i] + "\n")
+				if strings.Contains(lines[i], delim) {
+					break
+				}
+			}
+			continue
+		}
+		value, ok := tomlValue(rawValue)
+		if !ok {
+			res.WriteString(line + "\n")
+			continue
+		}
+		valuePath := append(append(append([]string{}, path...), table...), tomlKey(line[:eq])...)
+		templated, err := values.Add(value, valuePath...)
+		if err != nil {
+			logrus.Warnf("Can't templatize %v at line %s ignore..", valuePath, line)
+			res.WriteString(line + "\n")
+			continue
+		}
+		res.WriteString(line[:eq+1] + " " + templated + "\n")
+	}
+	if !strings.HasSuffix(toml, "\n") {
+		return strings.TrimSuffix(res.String(), "\n")
+	}
+	return res.String()
+}
+
+// tomlMultilineDelim - returns delimiter of multi-line string value not closed on the same line.
+func tomlMultilineDelim(value string) string {
+	for _, delim := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, delim) && !strings.Contains(value[len(delim):], delim) {
+			return delim
+		}
+	}
+	return ""
+}
+
+// tomlKey - splits dotted TOML key into parts without quotes.
+func tomlKey(key string) []string {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return parts
+}
+
+// tomlValue - parses scalar TOML value. Returns false for unsupported values.
+func tomlValue(value string) (interface{}, bool) {
+	switch {
+	case value == "true" || value == "false":
+		return value == "true", true
+	case strings.HasPrefix(value, `"`):
+		s, err := strconv.Unquote(value)
+		return s, err == nil
+	case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) > 1 && !strings.Contains(value[1:len(value)-1], "'"):
+		return value[1 : len(value)-1], true
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "xXoObB") {
+		return f, true
+	}
+	return nil, false
+}
@@ -2,6 +2,7 @@ package crd
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
@@ -15,10 +16,15 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
 )
 
-const crdTeml = `apiVersion: apiextensions.k8s.io/v1
+// caBundlePlaceholder - conversion webhook caBundle replaced with template after marshaling.
+const caBundlePlaceholder = "helmify-ca-bundle"
+
+const crdTeml = `{{- if .Values.crd.install }}
+apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
 metadata:
   name: %[1]s
@@ -33,7 +39,8 @@ status:
     kind: ""
     plural: ""
   conditions: []
-  storedVersions: []`
+  storedVersions: []
+{{- end }}`
 
 var crdGVC = schema.GroupVersionKind{
 	Group:   "apiextensions.k8s.io",
@@ -65,19 +72,28 @@ func (c crd) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 			return true, nil, errors.Wrap(err, "unable to create crd template")
 		}
 		return true, &result{
-			name: name + "-crd.yaml",
-			data: res,
+			name:   name + "-crd.yaml",
+			data:   res,
+			values: helmify.Values{},
 		}, nil
 	}
 
+	var caBundle string
+	if appMeta.Config().GenWebhookCerts {
+		// CA is generated by Helm instead of being injected by cert-manager
+		caBundle = processor.CABundle(appMeta, obj)
+		a := obj.GetAnnotations()
+		delete(a, processor.InjectCAAnnotation)
+		obj.SetAnnotations(a)
+	}
 	var labels, annotations string
 	if len(obj.GetAnnotations()) != 0 {
 		a := obj.GetAnnotations()
-		certName := a["cert-manager.io/inject-ca-from"]
+		certName := a[processor.InjectCAAnnotation]
 		if certName != "" {
 			certName = strings.TrimPrefix(certName, appMeta.Namespace()+"/")
 			certName = appMeta.TrimName(certName)
-			a["cert-manager.io/inject-ca-from"] = fmt.Sprintf(`{{ .Release.Namespace }}/{{ include "%[1]s.fullname" . }}-%[2]s`, appMeta.ChartName(), certName)
+			a["cert-manager.io/inject-ca-from"] = fmt.Sprintf(`%[3]s/{{ include "%[1]s.fullname" . }}-%[2]s`, appMeta.ChartName(), certName, appMeta.TemplatedNamespace(appMeta.Namespace()))
 		}
 		annotations, err = yamlformat.Marshal(map[string]interface{}{"annotations": a}, 2)
 		if err != nil {
@@ -116,14 +132,23 @@ func (c crd) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 		conv := spec.Conversion
 		if conv.Strategy == v1.WebhookConverter {
 			wh := conv.Webhook
-			if wh != nil {
-				wh.ClientConfig.Service.Name = appMeta.TemplatedName(wh.ClientConfig.Service.Name)
-				wh.ClientConfig.Service.Namespace = strings.ReplaceAll(wh.ClientConfig.Service.Namespace, appMeta.Namespace(), `{{ .Release.Namespace }}`)
+			if wh != nil && wh.ClientConfig != nil && wh.ClientConfig.Service != nil {
+				svc := wh.ClientConfig.Service
+				if templated := appMeta.TemplatedName(svc.Name); templated != svc.Name {
+					svc.Name, svc.Namespace = templated, appMeta.TemplatedNamespace(svc.Namespace)
+				}
+				if caBundle != "" {
+					// replaced with caBundle template after marshaling
+					wh.ClientConfig.CABundle = []byte(caBundlePlaceholder)
+				}
 			}
 		}
 	}
 
 	specYaml, _ := yaml.Marshal(spec)
+	if caBundle != "" {
+		specYaml = bytes.ReplaceAll(specYaml, []byte(base64.StdEncoding.EncodeToString([]byte(caBundlePlaceholder))), []byte(caBundle))
+	}
 	specYaml = yamlformat.Indent(specYaml, 2)
 	specYaml = bytes.TrimRight(specYaml, "\n ")
 
@@ -133,12 +158,15 @@ func (c crd) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 	return true, &result{
 		name: name + "-crd.yaml",
 		data: []byte(res),
+		// CRDs in templates dir are installed only if crd.install value is set
+		values: helmify.Values{"crd": map[string]interface{}{"install": true}},
 	}, nil
 }
 
 type result struct {
-	name string
-	data []byte
+	name   string
+	data   []byte
+	values helmify.Values
 }
 
 func (r *result) Filename() string {
@@ -146,7 +174,7 @@ func (r *result) Filename() string {
 }
 
 func (r *result) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *result) Write(writer io.Writer) error {
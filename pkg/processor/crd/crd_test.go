@@ -1,9 +1,15 @@
 package crd
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -46,3 +52,107 @@ func Test_crd_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+const strConversionCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: cephvolumes.test.example.com
+spec:
+  group: test.example.com
+  names:
+    kind: CephVolume
+    listKind: CephVolumeList
+    plural: cephvolumes
+    singular: cephvolume
+  scope: Namespaced
+  conversion:
+    strategy: Webhook
+    webhook:
+      conversionReviewVersions:
+      - v1
+      clientConfig:
+        service:
+          name: my-operator-webhook-service
+          namespace: my-operator-system
+          path: /convert`
+
+func Test_crd_Process_install(t *testing.T) {
+	var testInstance crd
+	obj := internal.GenerateObj(strConversionCRD)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-webhook-service
+  namespace: my-operator-system`))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-metrics-service
+  namespace: my-operator-system`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"crd": map[string]interface{}{"install": true}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := apiextensionsv1.CustomResourceDefinition{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	svc := res.Spec.Conversion.Webhook.ClientConfig.Service
+	assert.Equal(t, "release-webhook-service", svc.Name)
+	assert.Equal(t, "ns", svc.Namespace)
+
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), helmify.Values{"crd": map[string]interface{}{"install": false}})
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_crd_Process_genWebhookCerts(t *testing.T) {
+	var testInstance crd
+	obj := internal.GenerateObj(strings.Replace(strConversionCRD, `metadata:
+`, `metadata:
+  annotations:
+    cert-manager.io/inject-ca-from: my-operator-system/my-operator-serving-cert
+`, 1))
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", GenWebhookCerts: true})
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-webhook-service
+  namespace: my-operator-system`))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-metrics-service
+  namespace: my-operator-system`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+
+	// stub for the helper generated in place of the Certificate
+	helper := `{{- define "chart-name.serving-cert.certs" }}ca.crt: Y2E={{ end }}
+`
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", helper+buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := apiextensionsv1.CustomResourceDefinition{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Empty(t, res.Annotations)
+	assert.Equal(t, []byte("ca"), res.Spec.Conversion.Webhook.ClientConfig.CABundle)
+}
+
+func Test_crd_Process_crdDir(t *testing.T) {
+	var testInstance crd
+	obj := internal.GenerateObj(strConversionCRD)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", Crd: true})
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Empty(t, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.NotContains(t, buf.String(), "{{")
+	assert.Contains(t, buf.String(), "name: my-operator-webhook-service")
+}
@@ -1,8 +1,10 @@
 package daemonset
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/metadata"
 
 	"github.com/arttor/helmify/internal"
@@ -74,3 +76,47 @@ func Test_daemonset_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_daemonset_Process_capabilities(t *testing.T) {
+	var testInstance daemonset
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: proxy
+spec:
+  selector:
+    matchLabels:
+      app: proxy
+  template:
+    metadata:
+      labels:
+        app: proxy
+    spec:
+      containers:
+      - name: proxy
+        image: envoyproxy/envoy:v1.22.0
+        securityContext:
+          capabilities:
+            drop:
+            - ALL
+            add:
+            - NET_BIND_SERVICE
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `
+        securityContext: {{- toYaml .Values.proxy.proxy.securityContext | nindent 10 }}`)
+	tpl.Values()["kubernetesClusterDomain"] = "cluster.local"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Contains(t, rendered, `
+        securityContext:
+          capabilities:
+            add:
+            - NET_BIND_SERVICE
+            drop:
+            - ALL`)
+}
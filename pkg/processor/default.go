@@ -1,10 +1,14 @@
 package processor
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,6 +20,12 @@ var nsGVK = schema.GroupVersionKind{
 	Kind:    "Namespace",
 }
 
+// passthroughGKs - resources without dedicated processor which are expected in manifests.
+// Default processor templates only their metadata without a warning.
+var passthroughGKs = map[schema.GroupKind]bool{
+	{Group: "coordination.k8s.io", Kind: "Lease"}: true,
+}
+
 // Default default processor for unknown resources.
 func Default() helmify.Processor {
 	return &dft{}
@@ -29,34 +39,128 @@ func (d dft) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 		// Skip namespaces from processing because namespace will be handled by Helm.
 		return true, nil, nil
 	}
-	logrus.WithFields(logrus.Fields{
+	log := logrus.WithFields(logrus.Fields{
 		"ApiVersion": obj.GetAPIVersion(),
 		"Kind":       obj.GetKind(),
 		"Name":       obj.GetName(),
-	}).Warn("Unsupported resource: using default processor.")
-	name := appMeta.TrimName(obj.GetName())
+	})
+	if passthroughGKs[obj.GroupVersionKind().GroupKind()] {
+		log.Debug("Passthrough resource: using default processor.")
+	} else {
+		log.Warn("Unsupported resource: using default processor.")
+	}
+	name := appMeta.TrimName(metadata.ObjectName(obj))
 
 	meta, err := ProcessObjMeta(appMeta, obj)
 	if err != nil {
 		return true, nil, err
 	}
+	gvk := obj.GroupVersionKind()
 	delete(obj.Object, "apiVersion")
 	delete(obj.Object, "kind")
 	delete(obj.Object, "metadata")
 
+	values := helmify.Values{}
+	var templated []string
+	if isCustomResource(gvk) {
+		templated, err = processImageFields(strcase.ToLowerCamel(name), obj, appMeta.Config().CRImageFields, &values)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	placeholders := map[string]string{}
+	if appMeta.Config().CRValues && isCustomResource(gvk) {
+		if spec, ok := obj.Object["spec"].(map[string]interface{}); ok {
+			err = liftScalars(spec, []string{strcase.ToLowerCamel(name), "spec"}, &values, placeholders)
+			if err != nil {
+				return true, nil, err
+			}
+		}
+	}
 	body, err := yamlformat.Marshal(obj.Object, 0)
 	if err != nil {
 		return true, nil, err
 	}
+	for _, t := range templated {
+		body = strings.ReplaceAll(body, "'"+t+"'", t)
+	}
+	for placeholder, t := range placeholders {
+		body = strings.ReplaceAll(body, placeholder, t)
+	}
 	return true, &defaultResult{
-		data: []byte(meta + "\n" + body),
-		name: name,
+		data:   []byte(meta + "\n" + body),
+		name:   name,
+		values: values,
 	}, nil
 }
 
+// processImageFields - moves string fields with given dot-separated paths (e.g. 'spec.image') to values.
+// Returns templates replacing the fields.
+func processImageFields(name string, obj *unstructured.Unstructured, paths []string, values *helmify.Values) ([]string, error) {
+	var templated []string
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		image, ok, _ := unstructured.NestedString(obj.Object, fields...)
+		if !ok {
+			continue
+		}
+		imageTpl, err := values.Add(image, append([]string{name}, fields...)...)
+		if err != nil {
+			return nil, err
+		}
+		err = unstructured.SetNestedField(obj.Object, imageTpl, fields...)
+		if err != nil {
+			return nil, err
+		}
+		templated = append(templated, imageTpl)
+	}
+	return templated, nil
+}
+
+// builtinGroups - API groups of k8s built-in resources which do not end with '.k8s.io'.
+var builtinGroups = map[string]bool{"": true, "apps": true, "batch": true, "policy": true, "autoscaling": true, "extensions": true}
+
+// isCustomResource - returns true for resources not built into k8s.
+func isCustomResource(gvk schema.GroupVersionKind) bool {
+	return !builtinGroups[gvk.Group] && !strings.HasSuffix(gvk.Group, ".k8s.io")
+}
+
+// liftScalars - moves scalar fields of nested maps to values under given path. Lists and already templated fields are kept as is.
+// Fields are replaced with short placeholders to prevent yaml line wrapping of templates. Returns templates by placeholder.
+func liftScalars(obj map[string]interface{}, path []string, values *helmify.Values, placeholders map[string]string) error {
+	for k, v := range obj {
+		if strings.Contains(k, "/") {
+			// e.g. label keys which can not be a part of values path
+			continue
+		}
+		fieldPath := append(append([]string{}, path...), k)
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			err := liftScalars(typed, fieldPath, values, placeholders)
+			if err != nil {
+				return err
+			}
+		case string, bool, int64, float64:
+			if str, ok := typed.(string); ok && strings.HasPrefix(str, "{{") {
+				continue
+			}
+			tpl, err := values.Add(typed, fieldPath...)
+			if err != nil {
+				return err
+			}
+			// trailing dash keeps placeholders from being prefixes of each other
+			placeholder := fmt.Sprintf("helmify-cr-value-%d-", len(placeholders))
+			placeholders[placeholder] = tpl
+			obj[k] = placeholder
+		}
+	}
+	return nil
+}
+
 type defaultResult struct {
-	data []byte
-	name string
+	data   []byte
+	name   string
+	values helmify.Values
 }
 
 func (r *defaultResult) Filename() string {
@@ -64,7 +168,7 @@ func (r *defaultResult) Filename() string {
 }
 
 func (r *defaultResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *defaultResult) Write(writer io.Writer) error {
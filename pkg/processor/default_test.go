@@ -1,12 +1,16 @@
 package processor
 
 import (
-	"github.com/arttor/helmify/pkg/config"
+	"bytes"
 	"testing"
 
 	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
 )
 
 const pvcYaml = `apiVersion: v1
@@ -21,6 +25,31 @@ spec:
       storage: 2Gi
   storageClassName: cust1-mypool-lim`
 
+const hpaYaml = `apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: my-operator-hpa
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: my-operator-controller-manager
+  minReplicas: 1
+  maxReplicas: 3
+  metrics:
+  - type: Resource
+    resource:
+      name: memory
+      target:
+        type: AverageValue
+        averageValue: 500Mi
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        type: Utilization
+        averageUtilization: 80`
+
 func Test_dft_Process(t *testing.T) {
 
 	t.Run("skip namespace", func(t *testing.T) {
@@ -43,3 +72,150 @@ func Test_dft_Process(t *testing.T) {
 		assert.NotNil(t, templ)
 	})
 }
+
+func Test_dft_Process_hpaTargets(t *testing.T) {
+	obj := internal.GenerateObj(hpaYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	processed, templ, err := Default().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, templ.Write(&buf))
+	assert.Contains(t, buf.String(), "averageValue: 500Mi")
+	assert.Contains(t, buf.String(), "averageUtilization: 80")
+}
+
+func Test_dft_Process_crImageFields(t *testing.T) {
+	obj := internal.GenerateObj(`apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: my-operator-db
+spec:
+  image: postgres:14
+  storage: 10Gi
+  args:
+  - '--max-connections=100'`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", CRImageFields: []string{"spec.image", "spec.missing"}})
+	testMeta.Load(obj)
+	processed, templ, err := Default().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	image, ok, _ := unstructured.NestedString(templ.Values(), "myOperatorDb", "spec", "image")
+	assert.True(t, ok)
+	assert.Equal(t, "postgres:14", image)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, templ.Write(&buf))
+	assert.Contains(t, buf.String(), `name: {{ include "chart-name.fullname" . }}-my-operator-db`)
+	assert.Contains(t, buf.String(), "image: {{ .Values.myOperatorDb.spec.image | quote }}")
+	assert.Contains(t, buf.String(), "storage: 10Gi")
+	assert.Contains(t, buf.String(), "- --max-connections=100")
+}
+
+func Test_dft_Process_crImageFields_builtin(t *testing.T) {
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: PodTemplate
+metadata:
+  name: my-operator-tpl
+spec:
+  image: nginx:1.21`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", CRImageFields: []string{"spec.image"}})
+	testMeta.Load(obj)
+	processed, templ, err := Default().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Empty(t, templ.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, templ.Write(&buf))
+	assert.Contains(t, buf.String(), "image: nginx:1.21")
+}
+
+func Test_dft_Process_lease(t *testing.T) {
+	obj := internal.GenerateObj(`apiVersion: coordination.k8s.io/v1
+kind: Lease
+metadata:
+  name: my-operator-leader-election
+  namespace: my-operator-system
+spec:
+  holderIdentity: my-operator-controller-manager-6d4f8c7b9-x2x7v
+  leaseDurationSeconds: 15
+  acquireTime: "2022-01-01T10:00:00.000000Z"
+  renewTime: "2022-01-01T10:05:00.000000Z"
+  leaseTransitions: 2`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	processed, templ, err := Default().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Empty(t, templ.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, templ.Write(&buf))
+	assert.Equal(t, `apiVersion: coordination.k8s.io/v1
+kind: Lease
+metadata:
+  name: {{ include "chart-name.fullname" . }}-my-operator-leader-election
+  labels:
+  {{- include "chart-name.labels" . | nindent 4 }}
+spec:
+  acquireTime: "2022-01-01T10:00:00.000000Z"
+  holderIdentity: my-operator-controller-manager-6d4f8c7b9-x2x7v
+  leaseDurationSeconds: 15
+  leaseTransitions: 2
+  renewTime: "2022-01-01T10:05:00.000000Z"`, buf.String())
+}
+
+func Test_dft_Process_crValues(t *testing.T) {
+	obj := internal.GenerateObj(`apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: my-operator-db
+spec:
+  replicas: 3
+  version: "14.2"
+  backup:
+    enabled: true
+    schedule: 0 1 * * *
+    podLabels:
+      app.kubernetes.io/component: backup
+  args:
+  - '--max-connections=100'`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", CRValues: true})
+	testMeta.Load(obj)
+	processed, templ, err := Default().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, helmify.Values{"myOperatorDb": map[string]interface{}{"spec": map[string]interface{}{
+		"replicas": int64(3),
+		"version":  "14.2",
+		"backup":   map[string]interface{}{"enabled": true, "schedule": "0 1 * * *"},
+	}}}, templ.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, templ.Write(&buf))
+	assert.Contains(t, buf.String(), "replicas: {{ .Values.myOperatorDb.spec.replicas }}")
+	assert.Contains(t, buf.String(), "app.kubernetes.io/component: backup")
+	assert.Contains(t, buf.String(), "- --max-connections=100")
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), templ.Values())
+	assert.NoError(t, err)
+	res := map[string]interface{}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, map[string]interface{}{
+		"replicas": float64(3),
+		"version":  "14.2",
+		"backup":   map[string]interface{}{"enabled": true, "schedule": "0 1 * * *", "podLabels": map[string]interface{}{"app.kubernetes.io/component": "backup"}},
+		"args":     []interface{}{"--max-connections=100"},
+	}, res["spec"])
+}
+
+func Test_dft_Process_crValuesBuiltin(t *testing.T) {
+	obj := internal.GenerateObj(pvcYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", CRValues: true})
+	testMeta.Load(obj)
+	_, templ, err := Default().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Empty(t, templ.Values())
+}
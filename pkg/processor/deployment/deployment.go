@@ -6,15 +6,13 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/arttor/helmify/pkg/cluster"
 	"github.com/arttor/helmify/pkg/processor"
+	"github.com/arttor/helmify/pkg/processor/pod"
 
 	"github.com/arttor/helmify/pkg/helmify"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
-	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,6 +29,9 @@ var deploymentTempl, _ = template.New("deployment").Parse(
 spec:
 {{- if .Replicas }}
 {{ .Replicas }}
+{{- end }}
+{{- if .ProgressDeadlineSeconds }}
+{{ .ProgressDeadlineSeconds }}
 {{- end }}
   selector:
 {{ .Selector }}
@@ -46,6 +47,9 @@ const selectorTempl = `%[1]s
 {{- include "%[2]s.selectorLabels" . | nindent 6 }}
 %[3]s`
 
+// defaultProgressDeadlineSeconds - k8s default for Deployment progressDeadlineSeconds.
+const defaultProgressDeadlineSeconds = 600
+
 // New creates processor for k8s Deployment resource.
 func New() helmify.Processor {
 	return &deployment{}
@@ -71,7 +75,16 @@ func (d deployment) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstr
 	values := helmify.Values{}
 
 	name := appMeta.TrimName(obj.GetName())
-	replicas, err := processReplicas(name, &depl, &values)
+	replicas, err := processReplicas(name, &depl, appMeta.Config().ReplicaCount, &values)
+	if err != nil {
+		return true, nil, err
+	}
+	replicas, err = processor.GuardAutoscaledReplicas(appMeta, obj, replicas, &values)
+	if err != nil {
+		return true, nil, err
+	}
+
+	progressDeadlineSeconds, err := processProgressDeadlineSeconds(name, &depl, &values)
 	if err != nil {
 		return true, nil, err
 	}
@@ -91,100 +104,44 @@ func (d deployment) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstr
 	selector = strings.Trim(selector, " \n")
 	selector = string(yamlformat.Indent([]byte(selector), 4))
 
-	podLabels, err := yamlformat.Marshal(depl.Spec.Template.ObjectMeta.Labels, 8)
-	if err != nil {
-		return true, nil, err
-	}
-	podLabels += fmt.Sprintf("\n      {{- include \"%s.selectorLabels\" . | nindent 8 }}", appMeta.ChartName())
-
-	podAnnotations := ""
-	if len(depl.Spec.Template.ObjectMeta.Annotations) != 0 {
-		podAnnotations, err = yamlformat.Marshal(map[string]interface{}{"annotations": depl.Spec.Template.ObjectMeta.Annotations}, 6)
-		if err != nil {
-			return true, nil, err
-		}
-
-		podAnnotations = "\n" + podAnnotations
-	}
-
-	nameCamel := strcase.ToLowerCamel(name)
-	podValues, err := processPodSpec(nameCamel, appMeta, &depl.Spec.Template.Spec)
+	origPodSpec, _, _ := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+	podTpl, err := pod.ProcessTemplate(name, appMeta, &depl.Spec.Template, origPodSpec, 6, pod.Options{RestartAnnotation: appMeta.Config().RestartAnnotation}, &values)
 	if err != nil {
 		return true, nil, err
 	}
-	err = values.Merge(podValues)
-	if err != nil {
-		return true, nil, err
-	}
-
-	// replace PVC to templated name
-	for i := 0; i < len(depl.Spec.Template.Spec.Volumes); i++ {
-		vol := depl.Spec.Template.Spec.Volumes[i]
-		if vol.PersistentVolumeClaim == nil {
-			continue
-		}
-		tempPVCName := appMeta.TemplatedName(vol.PersistentVolumeClaim.ClaimName)
-		depl.Spec.Template.Spec.Volumes[i].PersistentVolumeClaim.ClaimName = tempPVCName
-	}
-
-	// replace container resources with template to values.
-	specMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&depl.Spec.Template.Spec)
-	if err != nil {
-		return true, nil, err
-	}
-	containers, _, err := unstructured.NestedSlice(specMap, "containers")
-	if err != nil {
-		return true, nil, err
-	}
-	for i := range containers {
-		containerName := strcase.ToLowerCamel((containers[i].(map[string]interface{})["name"]).(string))
-		res, exists, err := unstructured.NestedMap(values, nameCamel, containerName, "resources")
-		if err != nil {
-			return true, nil, err
-		}
-		if !exists || len(res) == 0 {
-			continue
-		}
-		err = unstructured.SetNestedField(containers[i].(map[string]interface{}), fmt.Sprintf(`{{- toYaml .Values.%s.%s.resources | nindent 10 }}`, nameCamel, containerName), "resources")
-		if err != nil {
-			return true, nil, err
-		}
-	}
-	err = unstructured.SetNestedSlice(specMap, containers, "containers")
-	if err != nil {
-		return true, nil, err
-	}
-	spec, err := yamlformat.Marshal(specMap, 6)
-	if err != nil {
-		return true, nil, err
-	}
-	spec = strings.ReplaceAll(spec, "'", "")
 
 	return true, &result{
 		values: values,
 		data: struct {
-			Meta           string
-			Replicas       string
-			Selector       string
-			PodLabels      string
-			PodAnnotations string
-			Spec           string
+			Meta                    string
+			Replicas                string
+			ProgressDeadlineSeconds string
+			Selector                string
+			PodLabels               string
+			PodAnnotations          string
+			Spec                    string
 		}{
-			Meta:           meta,
-			Replicas:       replicas,
-			Selector:       selector,
-			PodLabels:      podLabels,
-			PodAnnotations: podAnnotations,
-			Spec:           spec,
+			Meta:                    meta,
+			Replicas:                replicas,
+			ProgressDeadlineSeconds: progressDeadlineSeconds,
+			Selector:                selector,
+			PodLabels:               podTpl.Labels,
+			PodAnnotations:          podTpl.Annotations,
+			Spec:                    podTpl.Spec,
 		},
 	}, nil
 }
 
-func processReplicas(name string, deployment *appsv1.Deployment, values *helmify.Values) (string, error) {
+// processReplicas - adds replicas to <name>.replicas value or to top-level replicaCount value if replicaCount is set.
+func processReplicas(name string, deployment *appsv1.Deployment, replicaCount bool, values *helmify.Values) (string, error) {
 	if deployment.Spec.Replicas == nil {
 		return "", nil
 	}
-	replicasTpl, err := values.Add(int64(*deployment.Spec.Replicas), name, "replicas")
+	valueName := []string{name, "replicas"}
+	if replicaCount {
+		valueName = []string{"replicaCount"}
+	}
+	replicasTpl, err := values.Add(int64(*deployment.Spec.Replicas), valueName...)
 	if err != nil {
 		return "", err
 	}
@@ -192,96 +149,35 @@ func processReplicas(name string, deployment *appsv1.Deployment, values *helmify
 	if err != nil {
 		return "", err
 	}
-	replicas = strings.ReplaceAll(replicas, "'", "")
+	replicas = processor.UnquoteTemplates(replicas)
 	return replicas, nil
 }
 
-func processPodSpec(name string, appMeta helmify.AppMetadata, pod *corev1.PodSpec) (helmify.Values, error) {
-	values := helmify.Values{}
-	for i, c := range pod.Containers {
-		processed, err := processPodContainer(name, appMeta, c, &values)
-		if err != nil {
-			return nil, err
-		}
-		pod.Containers[i] = processed
-	}
-	for _, v := range pod.Volumes {
-		if v.ConfigMap != nil {
-			v.ConfigMap.Name = appMeta.TemplatedName(v.ConfigMap.Name)
-		}
-		if v.Secret != nil {
-			v.Secret.SecretName = appMeta.TemplatedName(v.Secret.SecretName)
-		}
-	}
-	pod.ServiceAccountName = appMeta.TemplatedName(pod.ServiceAccountName)
-
-	for i, s := range pod.ImagePullSecrets {
-		pod.ImagePullSecrets[i].Name = appMeta.TemplatedName(s.Name)
-	}
-
-	return values, nil
-}
-
-func processPodContainer(name string, appMeta helmify.AppMetadata, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
-	index := strings.LastIndex(c.Image, ":")
-	if index < 0 {
-		return c, errors.New("wrong image format: " + c.Image)
+// processProgressDeadlineSeconds - adds progressDeadlineSeconds to values if it differs from k8s default.
+func processProgressDeadlineSeconds(name string, deployment *appsv1.Deployment, values *helmify.Values) (string, error) {
+	if deployment.Spec.ProgressDeadlineSeconds == nil || *deployment.Spec.ProgressDeadlineSeconds == defaultProgressDeadlineSeconds {
+		return "", nil
 	}
-	repo, tag := c.Image[:index], c.Image[index+1:]
-	containerName := strcase.ToLowerCamel(c.Name)
-	c.Image = fmt.Sprintf("{{ .Values.%[1]s.%[2]s.image.repository }}:{{ .Values.%[1]s.%[2]s.image.tag | default .Chart.AppVersion }}", name, containerName)
-
-	err := unstructured.SetNestedField(*values, repo, name, containerName, "image", "repository")
+	deadlineTpl, err := values.Add(int64(*deployment.Spec.ProgressDeadlineSeconds), name, "progressDeadlineSeconds")
 	if err != nil {
-		return c, errors.Wrap(err, "unable to set deployment value field")
+		return "", err
 	}
-	err = unstructured.SetNestedField(*values, tag, name, containerName, "image", "tag")
+	deadline, err := yamlformat.Marshal(map[string]interface{}{"progressDeadlineSeconds": deadlineTpl}, 2)
 	if err != nil {
-		return c, errors.Wrap(err, "unable to set deployment value field")
-	}
-	for _, e := range c.Env {
-		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
-			e.ValueFrom.SecretKeyRef.Name = appMeta.TemplatedName(e.ValueFrom.SecretKeyRef.Name)
-		}
-		if e.ValueFrom != nil && e.ValueFrom.ConfigMapKeyRef != nil {
-			e.ValueFrom.ConfigMapKeyRef.Name = appMeta.TemplatedName(e.ValueFrom.ConfigMapKeyRef.Name)
-		}
-	}
-	for _, e := range c.EnvFrom {
-		if e.SecretRef != nil {
-			e.SecretRef.Name = appMeta.TemplatedName(e.SecretRef.Name)
-		}
-		if e.ConfigMapRef != nil {
-			e.ConfigMapRef.Name = appMeta.TemplatedName(e.ConfigMapRef.Name)
-		}
-	}
-	c.Env = append(c.Env, corev1.EnvVar{
-		Name:  cluster.DomainEnv,
-		Value: fmt.Sprintf("{{ .Values.%s }}", cluster.DomainKey),
-	})
-	for k, v := range c.Resources.Requests {
-		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "requests", k.String())
-		if err != nil {
-			return c, errors.Wrap(err, "unable to set container resources value")
-		}
-	}
-	for k, v := range c.Resources.Limits {
-		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "limits", k.String())
-		if err != nil {
-			return c, errors.Wrap(err, "unable to set container resources value")
-		}
+		return "", err
 	}
-	return c, nil
+	return processor.UnquoteTemplates(deadline), nil
 }
 
 type result struct {
 	data struct {
-		Meta           string
-		Replicas       string
-		Selector       string
-		PodLabels      string
-		PodAnnotations string
-		Spec           string
+		Meta                    string
+		Replicas                string
+		ProgressDeadlineSeconds string
+		Selector                string
+		PodLabels               string
+		PodAnnotations          string
+		Spec                    string
 	}
 	values helmify.Values
 }
@@ -1,12 +1,19 @@
 package deployment
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/processor/configmap"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -95,6 +102,35 @@ spec:
       - name: sample-pv-storage
         persistentVolumeClaim:
           claimName: my-sample-pv-claim
+`
+	strDeplEnvFrom = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+  namespace: my-app-ns
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+        envFrom:
+        - secretRef:
+            name: my-app-secret-vars
+`
+	strSecretVars = `apiVersion: v1
+kind: Secret
+metadata:
+  name: my-app-secret-vars
+  namespace: my-app-ns
+data:
+  VAR1: dmFsMQ==
 `
 )
 
@@ -114,3 +150,632 @@ func Test_deployment_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_deployment_Process_envFromSecret(t *testing.T) {
+	var testInstance deployment
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(internal.GenerateObj(strSecretVars))
+	obj := internal.GenerateObj(strDeplEnvFrom)
+	appMeta.Load(obj)
+
+	processed, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, true, processed)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `name: {{ include "chart-name.fullname" . }}-secret-vars`)
+	assert.NotContains(t, buf.String(), "VAR1")
+	assert.NotContains(t, tpl.Values(), "secretVars")
+}
+
+func Test_deployment_Process_subPathExpr(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+        env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+        volumeMounts:
+        - name: logs
+          mountPath: /var/log/nginx
+          subPathExpr: $(POD_NAME)
+      volumes:
+      - name: logs
+        emptyDir: {}
+`)
+	processed, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, true, processed)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), "subPathExpr: $(POD_NAME)")
+}
+
+func Test_deployment_Process_shareProcessNamespace(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      shareProcessNamespace: true
+      containers:
+      - name: web
+        image: nginx:1.21
+      - name: sidecar
+        image: busybox:1.35
+`)
+	processed, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, true, processed)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), "\n      shareProcessNamespace: true")
+}
+
+func Test_deployment_Process_resources(t *testing.T) {
+	var testInstance deployment
+	source := appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal([]byte(strDepl), &source))
+
+	obj := internal.GenerateObj(strDepl)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(obj)
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	_, hasResources, _ := unstructured.NestedMap(tpl.Values(), "myOperatorControllerManager", "manager", "resources")
+	assert.True(t, hasResources)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+
+	assert.Len(t, res.Spec.Template.Spec.Containers, len(source.Spec.Template.Spec.Containers))
+	for i, c := range source.Spec.Template.Spec.Containers {
+		assert.Equal(t, c.Resources, res.Spec.Template.Spec.Containers[i].Resources, c.Name)
+	}
+}
+
+func Test_deployment_Process_grpcProbe(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-api
+spec:
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: api
+        image: my-api:1.0.0
+        startupProbe:
+          grpc:
+            port: 9090
+            service: health
+          failureThreshold: 30
+          periodSeconds: 10
+`)
+	processed, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, true, processed)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	podSpec, ok, err := unstructured.NestedMap(internal.GenerateObj(rendered).Object, "spec", "template", "spec")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	containers := podSpec["containers"].([]interface{})
+	startupProbe := containers[0].(map[string]interface{})["startupProbe"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"port": int64(9090), "service": "health"}, startupProbe["grpc"])
+	assert.Equal(t, int64(30), startupProbe["failureThreshold"])
+}
+
+func Test_deployment_Process_configMapKeyRef(t *testing.T) {
+	var testInstance deployment
+	cm := internal.GenerateObj(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-env
+data:
+  LOG_LEVEL: debug
+`)
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+        env:
+        - name: LOG_LEVEL
+          valueFrom:
+            configMapKeyRef:
+              name: my-app-env
+              key: LOG_LEVEL
+`)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(cm)
+	appMeta.Load(obj)
+
+	_, cmTpl, err := configmap.New().Process(appMeta, cm)
+	assert.NoError(t, err)
+	cmBuf := bytes.Buffer{}
+	assert.NoError(t, cmTpl.Write(&cmBuf))
+	assert.Contains(t, cmBuf.String(), `name: {{ include "chart-name.fullname" . }}-env`)
+	assert.Contains(t, cmBuf.String(), "LOG_LEVEL: {{ .Values.env.logLevel | quote }}")
+
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `
+            configMapKeyRef:
+              key: LOG_LEVEL
+              name: {{ include "chart-name.fullname" . }}-env`)
+	assert.NotContains(t, buf.String(), "debug")
+	assert.NotContains(t, buf.String(), ".Values.env")
+}
+
+func Test_deployment_Process_progressDeadlineSeconds(t *testing.T) {
+	var testInstance deployment
+	const strDeplDeadline = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  progressDeadlineSeconds: %d
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+`
+	t.Run("custom", func(t *testing.T) {
+		obj := internal.GenerateObj(fmt.Sprintf(strDeplDeadline, 120))
+		_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+		assert.NoError(t, err)
+		deadline, ok, err := unstructured.NestedInt64(tpl.Values(), "web", "progressDeadlineSeconds")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(120), deadline)
+
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		assert.Contains(t, buf.String(), "\n  progressDeadlineSeconds: {{ .Values.web.progressDeadlineSeconds }}\n")
+	})
+	t.Run("default omitted", func(t *testing.T) {
+		obj := internal.GenerateObj(fmt.Sprintf(strDeplDeadline, 600))
+		_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+		assert.NoError(t, err)
+		_, ok, _ := unstructured.NestedFieldNoCopy(tpl.Values(), "web", "progressDeadlineSeconds")
+		assert.False(t, ok)
+
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		assert.NotContains(t, buf.String(), "progressDeadlineSeconds")
+	})
+}
+
+func Test_deployment_Process_topologySpreadConstraints(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: topology.kubernetes.io/zone
+        whenUnsatisfiable: DoNotSchedule
+        labelSelector:
+          matchLabels:
+            app: web
+      containers:
+      - name: web
+        image: nginx:1.21
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+
+	podLabels := res.Spec.Template.ObjectMeta.Labels
+	constraints := res.Spec.Template.Spec.TopologySpreadConstraints
+	assert.Len(t, constraints, 1)
+	assert.Equal(t, podLabels, constraints[0].LabelSelector.MatchLabels)
+	assert.Contains(t, podLabels, "app.kubernetes.io/instance")
+}
+
+func Test_deployment_Process_schedulingValues(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      runtimeClassName: kata
+      nodeSelector:
+        pool: frontend
+      tolerations:
+      - key: dedicated
+        operator: Equal
+        value: frontend
+        effect: NoSchedule
+      containers:
+      - name: web
+        image: nginx:1.21
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", SchedulingValues: true}), obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	assert.Equal(t, map[string]interface{}{"pool": "frontend"}, values["web"].(map[string]interface{})["nodeSelector"])
+	assert.Equal(t, map[string]interface{}{}, values["web"].(map[string]interface{})["affinity"])
+	assert.Equal(t, "kata", values["web"].(map[string]interface{})["runtimeClassName"])
+	assert.Equal(t, "", values["web"].(map[string]interface{})["schedulerName"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, map[string]string{"pool": "frontend"}, res.Spec.Template.Spec.NodeSelector)
+	assert.Len(t, res.Spec.Template.Spec.Tolerations, 1)
+	assert.Equal(t, "dedicated", res.Spec.Template.Spec.Tolerations[0].Key)
+	assert.Nil(t, res.Spec.Template.Spec.Affinity)
+	assert.Equal(t, "kata", *res.Spec.Template.Spec.RuntimeClassName)
+	assert.Empty(t, res.Spec.Template.Spec.SchedulerName)
+
+	err = unstructured.SetNestedStringMap(values, map[string]string{"pool": "backend"}, "web", "nodeSelector")
+	assert.NoError(t, err)
+	err = unstructured.SetNestedField(values, "gpu-scheduler", "web", "schedulerName")
+	assert.NoError(t, err)
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, map[string]string{"pool": "backend"}, res.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, "gpu-scheduler", res.Spec.Template.Spec.SchedulerName)
+}
+
+func Test_deployment_Process_envValues(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+        env:
+        - name: LOG_LEVEL
+          value: debug
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", EnvValues: true}), obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	container := values["web"].(map[string]interface{})["web"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"logLevel": "debug"}, container["env"])
+	assert.Equal(t, []interface{}{}, container["extraEnv"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values["kubernetesClusterDomain"] = "cluster.local"
+	container["extraEnv"] = []interface{}{map[string]interface{}{"name": "EXTRA", "value": "1"}}
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	env := res.Spec.Template.Spec.Containers[0].Env
+	assert.Len(t, env, 4)
+	assert.Equal(t, "LOG_LEVEL", env[0].Name)
+	assert.Equal(t, "debug", env[0].Value)
+	assert.Equal(t, "metadata.name", env[1].ValueFrom.FieldRef.FieldPath)
+	assert.Equal(t, "cluster.local", env[2].Value)
+	assert.Equal(t, "EXTRA", env[3].Name)
+}
+
+func Test_deployment_Process_initContainers(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate/migrate:v4.15.2
+        resources:
+          limits:
+            memory: 64Mi
+      containers:
+      - name: web
+        image: nginx:1.21
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	initContainer := values["web"].(map[string]interface{})["migrate"].(map[string]interface{})
+	assert.Equal(t, "migrate/migrate", initContainer["image"].(map[string]interface{})["repository"])
+	assert.Equal(t, "v4.15.2", initContainer["image"].(map[string]interface{})["tag"])
+	assert.Equal(t, map[string]interface{}{"limits": map[string]interface{}{"memory": "64Mi"}}, initContainer["resources"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	initContainer["image"].(map[string]interface{})["tag"] = "v4.16.0"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Len(t, res.Spec.Template.Spec.InitContainers, 1)
+	assert.Equal(t, "migrate/migrate:v4.16.0", res.Spec.Template.Spec.InitContainers[0].Image)
+	assert.Equal(t, "64Mi", res.Spec.Template.Spec.InitContainers[0].Resources.Limits.Memory().String())
+	assert.Equal(t, "nginx:1.21", res.Spec.Template.Spec.Containers[0].Image)
+}
+
+func Test_deployment_Process_autoscaled(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(strDepl)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(obj)
+	appMeta.Load(internal.GenerateObj(`apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: my-operator-hpa
+  namespace: my-operator-system
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: my-operator-controller-manager
+  maxReplicas: 3`))
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	assert.Equal(t, map[string]interface{}{"enabled": true}, values["autoscaling"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values["kubernetesClusterDomain"] = "cluster.local"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Nil(t, res.Spec.Replicas)
+
+	values["autoscaling"] = map[string]interface{}{"enabled": false}
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res = appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, int32(1), *res.Spec.Replicas)
+}
+
+func Test_deployment_Process_existingSecret(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+        envFrom:
+        - secretRef:
+            name: my-app-secret
+`)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name", ExistingSecrets: true})
+	appMeta.Load(obj)
+	appMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Secret
+metadata:
+  name: my-app-secret`))
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values := tpl.Values()
+	values["kubernetesClusterDomain"] = "cluster.local"
+	// added by Secret processor
+	values["secret"] = map[string]interface{}{"existingSecret": ""}
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, "release-secret", res.Spec.Template.Spec.Containers[0].EnvFrom[0].SecretRef.Name)
+
+	values["secret"] = map[string]interface{}{"existingSecret": "prod-secret"}
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, "prod-secret", res.Spec.Template.Spec.Containers[0].EnvFrom[0].SecretRef.Name)
+}
+
+func Test_deployment_Process_restartAnnotation(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", RestartAnnotation: true}), obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	restartedAt, ok, err := unstructured.NestedString(values, "web", "restartedAt")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "", restartedAt)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values["kubernetesClusterDomain"] = "cluster.local"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.NotContains(t, res.Spec.Template.Annotations, "kubectl.kubernetes.io/restartedAt")
+
+	assert.NoError(t, unstructured.SetNestedField(values, "2026-10-18T10:00:00Z", "web", "restartedAt"))
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res = appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "2026-10-18T10:00:00Z", res.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+}
+
+func Test_deployment_Process_quotedValues(t *testing.T) {
+	var testInstance deployment
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.21
+        args: ["sh", "-c", "echo 'hello world'"]
+        env:
+        - name: PATTERN
+          value: "*"
+        - name: MESSAGE
+          value: "it's"
+`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	values["kubernetesClusterDomain"] = "cluster.local"
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := appsv1.Deployment{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	container := res.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"sh", "-c", "echo 'hello world'"}, container.Args)
+	assert.Equal(t, "*", container.Env[0].Value)
+	assert.Equal(t, "it's", container.Env[1].Value)
+}
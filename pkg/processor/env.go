@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"github.com/arttor/helmify/pkg/cluster"
+	"github.com/arttor/helmify/pkg/helmify"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TemplateEnv - moves container env vars with plain values to <name>.<container name>.env values if enabled by config.
+// Env vars referencing other resources are kept as is. Adds a mark for extraEnv values hook at the end of env list
+// which has to be replaced with ReplaceExtraMarks after marshaling.
+func TemplateEnv(appMeta helmify.AppMetadata, name, containerName string, c *corev1.Container, values *helmify.Values) error {
+	if !appMeta.Config().EnvValues {
+		return nil
+	}
+	for i, e := range c.Env {
+		if e.ValueFrom != nil || e.Name == cluster.DomainEnv {
+			continue
+		}
+		valueTpl, err := values.Add(e.Value, name, containerName, "env", e.Name)
+		if err != nil {
+			return err
+		}
+		c.Env[i].Value = valueTpl
+	}
+	_, err := values.Add([]interface{}{}, name, containerName, "extraEnv")
+	if err != nil {
+		return err
+	}
+	c.Env = append(c.Env, corev1.EnvVar{Name: extraMarkName(name, containerName, "extraEnv")})
+	return nil
+}
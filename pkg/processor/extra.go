@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// extraMark - name prefix of temporary list item replaced with values hook appending items from values.
+const extraMark = "helmify-extra-"
+
+// extraMarkRegexp - matches marshaled list item with extraMark name. Captures indent and values path.
+var extraMarkRegexp = regexp.MustCompile(`(?m)^( *)- name: ` + extraMark + `(\S+)$`)
+
+// extraTempl - values hook appending list items. %[1]s - indent, %[2]s - values path, %[3]d - items indent.
+const extraTempl = `%[1]s{{- with .Values.%[2]s }}
+%[1]s{{- toYaml . | nindent %[3]d }}
+%[1]s{{- end }}`
+
+// extraMarkName - returns name of a list item marking the place of values hook appending items from value with given path.
+// Marks have to be replaced with ReplaceExtraMarks after marshaling.
+func extraMarkName(path ...string) string {
+	return extraMark + strings.Join(path, ".")
+}
+
+func extraListItem(path ...string) map[string]interface{} {
+	return map[string]interface{}{"name": extraMarkName(path...)}
+}
+
+// MarkExtraHooks - adds empty <name>.extraVolumes, <name>.extraContainers and <name>.<container name>.extraVolumeMounts
+// values and marks for hooks appending them to pod spec. Marks have to be replaced with ReplaceExtraMarks after marshaling.
+func MarkExtraHooks(name string, podSpec map[string]interface{}, values *helmify.Values) error {
+	containers, _, err := unstructured.NestedSlice(podSpec, "containers")
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		containerName := strcase.ToLowerCamel(container["name"].(string))
+		err = appendExtraHook(container, "volumeMounts", values, name, containerName, "extraVolumeMounts")
+		if err != nil {
+			return err
+		}
+	}
+	containers = append(containers, extraListItem(name, "extraContainers"))
+	_, err = values.Add([]interface{}{}, name, "extraContainers")
+	if err != nil {
+		return err
+	}
+	err = unstructured.SetNestedSlice(podSpec, containers, "containers")
+	if err != nil {
+		return err
+	}
+	return appendExtraHook(podSpec, "volumes", values, name, "extraVolumes")
+}
+
+// appendExtraHook - adds empty list value with given path and a mark for hook appending it to the list field.
+func appendExtraHook(obj map[string]interface{}, field string, values *helmify.Values, path ...string) error {
+	list, _, err := unstructured.NestedSlice(obj, field)
+	if err != nil {
+		return err
+	}
+	_, err = values.Add([]interface{}{}, path...)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedSlice(obj, append(list, extraListItem(path...)), field)
+}
+
+// ReplaceExtraMarks - replaces list item marks with values hooks appending items from values.
+func ReplaceExtraMarks(podSpec string) string {
+	return extraMarkRegexp.ReplaceAllStringFunc(podSpec, func(mark string) string {
+		match := extraMarkRegexp.FindStringSubmatch(mark)
+		return fmt.Sprintf(extraTempl, match[1], match[2], len(match[1]))
+	})
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestMarkExtraHooks(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":  "web-app",
+				"image": "nginx",
+				"volumeMounts": []interface{}{
+					map[string]interface{}{"name": "config", "mountPath": "/etc/nginx"},
+				},
+			},
+		},
+		"volumes": []interface{}{
+			map[string]interface{}{"name": "config", "emptyDir": map[string]interface{}{}},
+		},
+	}
+	values := helmify.Values{}
+	err := MarkExtraHooks("web", podSpec, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"web": map[string]interface{}{
+		"extraContainers": []interface{}{},
+		"extraVolumes":    []interface{}{},
+		"webApp":          map[string]interface{}{"extraVolumeMounts": []interface{}{}},
+	}}, values)
+
+	spec, err := yaml.Marshal(podSpec, 6)
+	assert.NoError(t, err)
+	spec = ReplaceExtraMarks(spec)
+	assert.NotContains(t, spec, extraMark)
+
+	rendered, err := internal.RenderTemplate("chart-name", spec, values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Len(t, res.Containers, 1)
+	assert.Len(t, res.Containers[0].VolumeMounts, 1)
+	assert.Len(t, res.Volumes, 1)
+
+	web := values["web"].(map[string]interface{})
+	web["extraContainers"] = []interface{}{map[string]interface{}{"name": "sidecar", "image": "busybox"}}
+	web["extraVolumes"] = []interface{}{map[string]interface{}{"name": "cache", "emptyDir": map[string]interface{}{}}}
+	web["webApp"].(map[string]interface{})["extraVolumeMounts"] = []interface{}{
+		map[string]interface{}{"name": "cache", "mountPath": "/cache"},
+	}
+	rendered, err = internal.RenderTemplate("chart-name", spec, values)
+	assert.NoError(t, err)
+	res = corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Len(t, res.Containers, 2)
+	assert.Equal(t, "sidecar", res.Containers[1].Name)
+	assert.Equal(t, "/cache", res.Containers[0].VolumeMounts[1].MountPath)
+	assert.Equal(t, "cache", res.Volumes[1].Name)
+}
@@ -0,0 +1,185 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const group = "gateway.networking.k8s.io"
+
+var gatewayGK = schema.GroupKind{Group: group, Kind: "Gateway"}
+
+// New creates processor for Gateway API Gateway resource.
+func New() helmify.Processor {
+	return &gateway{}
+}
+
+type gateway struct{}
+
+// Process Gateway object into template. Returns false if not capable of processing given resource type.
+// gatewayClassName and listeners hostnames are moved to gateway.<name>.* values.
+func (g gateway) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind().GroupKind() != gatewayGK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get Gateway spec")
+	}
+	values := helmify.Values{}
+	if className, ok := spec["gatewayClassName"].(string); ok {
+		spec["gatewayClassName"], err = values.Add(className, "gateway", nameCamel, "gatewayClassName")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	listeners, _ := spec["listeners"].([]interface{})
+	for _, l := range listeners {
+		listener, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		listenerName, _ := listener["name"].(string)
+		hostname, ok := listener["hostname"].(string)
+		if !ok || listenerName == "" {
+			continue
+		}
+		listener["hostname"], err = values.Add(hostname, "gateway", nameCamel, "listeners", listenerName, "hostname")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	specYaml, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &result{
+		name:   name,
+		data:   []byte(meta + "\n" + processor.UnquoteTemplates(specYaml)),
+		values: values,
+	}, nil
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
+
+// templateRefs - templates names of chart objects and release namespace in given parentRefs or backendRefs.
+func templateRefs(appMeta helmify.AppMetadata, refs []interface{}) {
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := ref["name"].(string)
+		if !ok {
+			continue
+		}
+		templatedName := appMeta.TemplatedName(name)
+		if templatedName == name {
+			continue
+		}
+		ref["name"] = templatedName
+		if ns, ok := ref["namespace"].(string); ok && ns != "" {
+			ref["namespace"] = appMeta.TemplatedNamespace(ns)
+		}
+	}
+}
+
+// routeHostnamesTempl - %[1]s - values name.
+const routeHostnamesTempl = `
+spec:
+  {{- with .Values.gateway.%[1]s.hostnames }}
+  hostnames:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}`
+
+// NewRoute creates processor for Gateway API HTTPRoute and GRPCRoute resources.
+func NewRoute() helmify.Processor {
+	return &route{}
+}
+
+type route struct{}
+
+// Process HTTPRoute or GRPCRoute object into template. Returns false if not capable of processing given resource type.
+// Hostnames are moved to gateway.<name>.hostnames value, chart gateways and services references are templated.
+func (r route) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	gk := obj.GroupVersionKind().GroupKind()
+	if gk.Group != group || (gk.Kind != "HTTPRoute" && gk.Kind != "GRPCRoute") {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to get %s spec", gk.Kind)
+	}
+	values := helmify.Values{}
+	hostnames, _ := spec["hostnames"].([]interface{})
+	if hostnames == nil {
+		hostnames = []interface{}{}
+	}
+	err = unstructured.SetNestedSlice(values, hostnames, "gateway", nameCamel, "hostnames")
+	if err != nil {
+		return true, nil, err
+	}
+	delete(spec, "hostnames")
+
+	parentRefs, _ := spec["parentRefs"].([]interface{})
+	templateRefs(appMeta, parentRefs)
+	rules, _ := spec["rules"].([]interface{})
+	for _, rl := range rules {
+		rule, ok := rl.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendRefs, _ := rule["backendRefs"].([]interface{})
+		templateRefs(appMeta, backendRefs)
+	}
+	specYaml := ""
+	if len(spec) != 0 {
+		specYaml, err = yamlformat.Marshal(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		specYaml = "\n" + processor.UnquoteTemplates(specYaml)
+	}
+	return true, &result{
+		name:   name,
+		data:   []byte(meta + fmt.Sprintf(routeHostnamesTempl, nameCamel) + specYaml),
+		values: values,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	gatewayYaml = `apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: my-app-gateway
+  namespace: my-app
+spec:
+  gatewayClassName: istio
+  listeners:
+  - name: https
+    hostname: app.example.com
+    port: 443
+    protocol: HTTPS`
+	httpRouteYaml = `apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: my-app-route
+  namespace: my-app
+spec:
+  parentRefs:
+  - name: my-app-gateway
+    namespace: my-app
+  hostnames:
+  - app.example.com
+  rules:
+  - matches:
+    - path:
+        type: PathPrefix
+        value: /api
+    backendRefs:
+    - name: my-app-api
+      port: 8080
+    - name: external-api
+      port: 80`
+)
+
+func Test_gateway_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := New().Process(&metadata.Service{}, internal.GenerateObj(gatewayYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+		processed, _, err = NewRoute().Process(&metadata.Service{}, internal.GenerateObj(httpRouteYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := New().Process(&metadata.Service{}, internal.GenerateObj(httpRouteYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+		processed, _, err = NewRoute().Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func loadMeta() *metadata.Service {
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(internal.GenerateObj(gatewayYaml))
+	testMeta.Load(internal.GenerateObj(httpRouteYaml))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-api
+  namespace: my-app`))
+	return testMeta
+}
+
+func Test_gateway_Process_values(t *testing.T) {
+	_, tpl, err := New().Process(loadMeta(), internal.GenerateObj(gatewayYaml))
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"gateway": map[string]interface{}{"gateway": map[string]interface{}{
+		"gatewayClassName": "istio",
+		"listeners":        map[string]interface{}{"https": map[string]interface{}{"hostname": "app.example.com"}},
+	}}}, tpl.Values())
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			GatewayClassName string `json:"gatewayClassName"`
+			Listeners        []struct {
+				Hostname string
+				Port     int
+			}
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "istio", res.Spec.GatewayClassName)
+	assert.Equal(t, "app.example.com", res.Spec.Listeners[0].Hostname)
+	assert.Equal(t, 443, res.Spec.Listeners[0].Port)
+}
+
+func Test_route_Process_values(t *testing.T) {
+	_, tpl, err := NewRoute().Process(loadMeta(), internal.GenerateObj(httpRouteYaml))
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"gateway": map[string]interface{}{"route": map[string]interface{}{
+		"hostnames": []interface{}{"app.example.com"},
+	}}}, tpl.Values())
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	type ref struct {
+		Name      string
+		Namespace string
+		Port      int
+	}
+	var res struct {
+		Spec struct {
+			ParentRefs []ref `json:"parentRefs"`
+			Hostnames  []string
+			Rules      []struct {
+				BackendRefs []ref `json:"backendRefs"`
+			}
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, []ref{{Name: "release-gateway", Namespace: "ns"}}, res.Spec.ParentRefs)
+	assert.Equal(t, []string{"app.example.com"}, res.Spec.Hostnames)
+	assert.Equal(t, []ref{{Name: "release-api", Port: 8080}, {Name: "external-api", Port: 80}}, res.Spec.Rules[0].BackendRefs)
+}
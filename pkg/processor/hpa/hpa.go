@@ -0,0 +1,141 @@
+package hpa
+
+import (
+	"io"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// metricTargetFields - metric target fields moved to values.
+var metricTargetFields = []string{"averageUtilization", "averageValue", "value"}
+
+// New creates processor for k8s HorizontalPodAutoscaler resource.
+func New() helmify.Processor {
+	return &hpa{}
+}
+
+type hpa struct{}
+
+// Process k8s HorizontalPodAutoscaler object into template. Returns false if not capable of processing given resource type.
+// autoscaling/v2beta2 is also supported because it has the same schema as autoscaling/v2.
+func (p hpa) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != "autoscaling" || gvk.Kind != "HorizontalPodAutoscaler" || (gvk.Version != "v2" && gvk.Version != "v2beta2") {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	if target, ok, _ := unstructured.NestedString(spec, "scaleTargetRef", "name"); ok {
+		err = unstructured.SetNestedField(spec, appMeta.TemplatedName(target), "scaleTargetRef", "name")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	for _, field := range []string{"minReplicas", "maxReplicas"} {
+		value, ok := spec[field]
+		if !ok {
+			continue
+		}
+		spec[field], err = values.Add(value, nameCamel, field)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	metrics, _, err := unstructured.NestedSlice(spec, "metrics")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get hpa metrics")
+	}
+	for _, m := range metrics {
+		metric, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		err = processMetric(nameCamel, metric, &values)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if len(metrics) != 0 {
+		err = unstructured.SetNestedSlice(spec, metrics, "metrics")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	specStr, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	specStr = processor.UnquoteTemplates(specStr)
+	data, err := processor.TemplateAutoscaling(meta+"\n"+specStr, &values)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &result{
+		name:   name,
+		data:   data,
+		values: values,
+	}, nil
+}
+
+// processMetric - moves metric target to <name>.metrics.<metric name> values.
+func processMetric(name string, metric map[string]interface{}, values *helmify.Values) error {
+	metricType, _, _ := unstructured.NestedString(metric, "type")
+	source := strcase.ToLowerCamel(metricType)
+	metricName, ok, _ := unstructured.NestedString(metric, source, "name")
+	if !ok {
+		// pods, object and external metrics
+		metricName, ok, _ = unstructured.NestedString(metric, source, "metric", "name")
+	}
+	if !ok {
+		return nil
+	}
+	// metric names may contain '/', e.g. for external metrics
+	metricName = strings.ReplaceAll(metricName, "/", "-")
+	for _, field := range metricTargetFields {
+		value, ok, _ := unstructured.NestedFieldNoCopy(metric, source, "target", field)
+		if !ok {
+			continue
+		}
+		templated, err := values.Add(value, name, "metrics", metricName, field)
+		if err != nil {
+			return errors.Wrapf(err, "unable to process %s metric", metricName)
+		}
+		err = unstructured.SetNestedField(metric, templated, source, "target", field)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type result struct {
+	name   string
+	data   string
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write([]byte(r.data))
+	return err
+}
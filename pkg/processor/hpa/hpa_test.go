@@ -0,0 +1,108 @@
+package hpa
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	strHpa = `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: my-operator-hpa
+  namespace: my-operator-system
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: my-operator-controller-manager
+  minReplicas: 1
+  maxReplicas: 3
+  metrics:
+  - type: Resource
+    resource:
+      name: memory
+      target:
+        type: AverageValue
+        averageValue: 500Mi
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        type: Utilization
+        averageUtilization: 80
+  - type: Pods
+    pods:
+      metric:
+        name: requests-per-second
+      target:
+        type: AverageValue
+        averageValue: "10"`
+	strDepl = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-operator-controller-manager
+  namespace: my-operator-system`
+)
+
+func Test_hpa_Process(t *testing.T) {
+	var testInstance hpa
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(strHpa)
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_hpa_Process_values(t *testing.T) {
+	var testInstance hpa
+	obj := internal.GenerateObj(strHpa)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(strDepl))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	values := tpl.Values()["hpa"].(map[string]interface{})
+	assert.Equal(t, int64(1), values["minReplicas"])
+	assert.Equal(t, int64(3), values["maxReplicas"])
+	assert.Equal(t, map[string]interface{}{
+		"memory":            map[string]interface{}{"averageValue": "500Mi"},
+		"cpu":               map[string]interface{}{"averageUtilization": int64(80)},
+		"requestsPerSecond": map[string]interface{}{"averageValue": "10"},
+	}, values["metrics"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `name: {{ include "chart-name.fullname" . }}-controller-manager`)
+	tpl.Values()["hpa"].(map[string]interface{})["maxReplicas"] = int64(5)
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := map[string]interface{}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	maxReplicas, _, _ := unstructured.NestedFloat64(res, "spec", "maxReplicas")
+	assert.Equal(t, float64(5), maxReplicas)
+	metrics, _, _ := unstructured.NestedSlice(res, "spec", "metrics")
+	assert.Equal(t, float64(80), metrics[1].(map[string]interface{})["resource"].(map[string]interface{})["target"].(map[string]interface{})["averageUtilization"])
+	assert.Equal(t, "10", metrics[2].(map[string]interface{})["pods"].(map[string]interface{})["target"].(map[string]interface{})["averageValue"])
+
+	assert.Equal(t, map[string]interface{}{"enabled": true}, tpl.Values()["autoscaling"])
+	tpl.Values()["autoscaling"] = map[string]interface{}{"enabled": false}
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Empty(t, rendered)
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// imageTempl - container image template. Chart-wide global.imageRegistry value overrides image registry.
+// Image is pinned by digest if digest value is set. %[1]s - image values path.
+const imageTempl = `{{ with .Values.global.imageRegistry | default .Values.%[1]s.registry }}{{ . }}/{{ end }}{{ .Values.%[1]s.repository }}{{ with .Values.%[1]s.digest }}@{{ . }}{{ else }}:{{ .Values.%[1]s.tag | default .Chart.AppVersion }}{{ end }}`
+
+// libraryImageTempl - container image template using library chart 'image' helper.
+// %[1]s - library chart name, %[2]s - image values path.
+const libraryImageTempl = `{{ include "%[1]s.image" (dict "image" .Values.%[2]s "context" $) }}`
+
+// image - container image reference parts.
+type image struct {
+	registry, repository, tag, digest string
+}
+
+// TemplateImage - moves container image registry, repository, tag and digest to <name>.<container name>.image values.
+// Returns image template. Library chart 'image' helper is used if library chart is enabled.
+func TemplateImage(appMeta helmify.AppMetadata, name, containerName, img string, values *helmify.Values) (string, error) {
+	ref, err := parseImage(img)
+	if err != nil {
+		return "", err
+	}
+	for field, value := range map[string]string{"registry": ref.registry, "repository": ref.repository, "tag": ref.tag, "digest": ref.digest} {
+		_, err := values.Add(value, name, containerName, "image", field)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to set image value")
+		}
+	}
+	// registry override is shared by all images of the chart
+	_, err = values.Add("", "global", "imageRegistry")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to set image value")
+	}
+	path := strings.Join([]string{name, containerName, "image"}, ".")
+	if library := appMeta.Config().LibraryChart; library != "" {
+		return fmt.Sprintf(libraryImageTempl, library, path), nil
+	}
+	return fmt.Sprintf(imageTempl, path), nil
+}
+
+// parseImage - splits image reference to registry, repository, tag and digest.
+// Registry is empty for images from the default registry.
+func parseImage(img string) (image, error) {
+	named, err := reference.ParseNormalizedNamed(img)
+	if err != nil {
+		// ':' before the last '/' separates registry port, not tag
+		index := strings.LastIndex(img, ":")
+		if index <= strings.LastIndex(img, "/") {
+			return image{}, errors.Wrapf(err, "wrong image format: %q", img)
+		}
+		logrus.WithError(err).Warnf("unable to parse image %s: image is split on the last ':'", img)
+		return image{repository: img[:index], tag: img[index+1:]}, nil
+	}
+	res := image{repository: reference.Path(named)}
+	if domain := reference.Domain(named); strings.HasPrefix(img, domain+"/") {
+		res.registry = domain
+	} else {
+		// short name from the default registry, e.g. 'nginx' instead of 'docker.io/library/nginx'
+		res.repository = reference.FamiliarName(named)
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		res.tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		res.digest = digested.Digest().String()
+	}
+	if res.tag == "" && res.digest == "" {
+		logrus.Warnf("image %s has no tag: chart appVersion is used as image tag", img)
+	}
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_parseImage(t *testing.T) {
+	const digest = "sha256:45b23dee08af5e43a7fea6c4cf9c25ccf269ee113168c19722f87876677c5cb2"
+	tests := []struct {
+		img  string
+		want image
+	}{
+		{img: "nginx", want: image{repository: "nginx"}},
+		{img: "nginx:1.21", want: image{repository: "nginx", tag: "1.21"}},
+		{img: "bitnami/redis:7.0", want: image{repository: "bitnami/redis", tag: "7.0"}},
+		{img: "gcr.io/kubebuilder/kube-rbac-proxy:v0.8.0", want: image{registry: "gcr.io", repository: "kubebuilder/kube-rbac-proxy", tag: "v0.8.0"}},
+		{img: "localhost/app:dev", want: image{registry: "localhost", repository: "app", tag: "dev"}},
+		{img: "registry:5000/app", want: image{registry: "registry:5000", repository: "app"}},
+		{img: "registry:5000/team/app:1.0", want: image{registry: "registry:5000", repository: "team/app", tag: "1.0"}},
+		{img: "app@" + digest, want: image{repository: "app", digest: digest}},
+		{img: "quay.io/app:1.0@" + digest, want: image{registry: "quay.io", repository: "app", tag: "1.0", digest: digest}},
+		{img: "docker.io/library/nginx:1.21", want: image{registry: "docker.io", repository: "library/nginx", tag: "1.21"}},
+		{img: "${IMAGE}:1.0", want: image{repository: "${IMAGE}", tag: "1.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.img, func(t *testing.T) {
+			got, err := parseImage(tt.img)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := parseImage("${REGISTRY}:5000/app")
+	assert.Error(t, err)
+}
+
+func TestTemplateImage(t *testing.T) {
+	values := helmify.Values{}
+	tpl, err := TemplateImage(metadata.New(config.Config{}), "web", "app", "gcr.io/project/app:1.0.0", &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{
+		"global": map[string]interface{}{"imageRegistry": ""},
+		"web": map[string]interface{}{"app": map[string]interface{}{"image": map[string]interface{}{
+			"registry": "gcr.io", "repository": "project/app", "tag": "1.0.0", "digest": "",
+		}}},
+	}, values)
+
+	rendered, err := internal.RenderTemplate("chart-name", tpl, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "gcr.io/project/app:1.0.0", rendered)
+
+	assert.NoError(t, unstructured.SetNestedField(values, "mirror.local:5000", "global", "imageRegistry"))
+	rendered, err = internal.RenderTemplate("chart-name", tpl, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "mirror.local:5000/project/app:1.0.0", rendered)
+
+	assert.NoError(t, unstructured.SetNestedField(values, "", "global", "imageRegistry"))
+	assert.NoError(t, unstructured.SetNestedField(values, "", "web", "app", "image", "registry"))
+	rendered, err = internal.RenderTemplate("chart-name", tpl, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "project/app:1.0.0", rendered)
+
+	assert.NoError(t, unstructured.SetNestedField(values, "sha256:45b23dee08af5e43a7fea6c4cf9c25ccf269ee113168c19722f87876677c5cb2", "web", "app", "image", "digest"))
+	rendered, err = internal.RenderTemplate("chart-name", tpl, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "project/app@sha256:45b23dee08af5e43a7fea6c4cf9c25ccf269ee113168c19722f87876677c5cb2", rendered)
+}
+
+func TestTemplateImage_library(t *testing.T) {
+	values := helmify.Values{}
+	tpl, err := TemplateImage(metadata.New(config.Config{LibraryChart: "common"}), "web", "app", "nginx:1.21", &values)
+	assert.NoError(t, err)
+	assert.Equal(t, `{{ include "common.image" (dict "image" .Values.web.app.image "context" $) }}`, tpl)
+	repository, _, _ := unstructured.NestedString(values, "web", "app", "image", "repository")
+	assert.Equal(t, "nginx", repository)
+}
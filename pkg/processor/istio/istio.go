@@ -0,0 +1,203 @@
+package istio
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const group = "networking.istio.io"
+
+// routeTypes - VirtualService route lists with weighted destinations.
+var routeTypes = []string{"http", "tcp", "tls"}
+
+// New creates processor for Istio VirtualService, DestinationRule and Gateway resources.
+func New() helmify.Processor {
+	return &istio{}
+}
+
+type istio struct{}
+
+// Process Istio object into template. Returns false if not capable of processing given resource type.
+// Hosts and gateways referring chart objects are templated with chart fullname and release namespace.
+// VirtualService route weights are moved to <name>.weights.<destination> values.
+func (i istio) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != group {
+		return false, nil, nil
+	}
+	switch gvk.Kind {
+	case "VirtualService", "DestinationRule", "Gateway":
+	default:
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to get %s spec", gvk.Kind)
+	}
+	values := helmify.Values{}
+	switch gvk.Kind {
+	case "VirtualService":
+		err = processVirtualService(appMeta, strcase.ToLowerCamel(name), spec, &values)
+		if err != nil {
+			return true, nil, err
+		}
+	case "DestinationRule":
+		if host, ok := spec["host"].(string); ok {
+			spec["host"] = templatedHost(appMeta, host)
+		}
+	}
+	specYaml, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &result{
+		// VirtualService and DestinationRule usually share the name
+		name:   name + "-" + strings.ToLower(gvk.Kind),
+		data:   []byte(meta + "\n" + processor.UnquoteTemplates(specYaml)),
+		values: values,
+	}, nil
+}
+
+func processVirtualService(appMeta helmify.AppMetadata, nameCamel string, spec map[string]interface{}, values *helmify.Values) error {
+	hosts, _ := spec["hosts"].([]interface{})
+	for i, h := range hosts {
+		if host, ok := h.(string); ok {
+			hosts[i] = templatedHost(appMeta, host)
+		}
+	}
+	gateways, _ := spec["gateways"].([]interface{})
+	for i, g := range gateways {
+		if gw, ok := g.(string); ok {
+			gateways[i] = templatedGateway(appMeta, gw)
+		}
+	}
+	weightNames := map[string]bool{}
+	for _, routeType := range routeTypes {
+		routes, _ := spec[routeType].([]interface{})
+		for _, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if matches, ok := route["match"].([]interface{}); ok {
+				for _, m := range matches {
+					match, ok := m.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					matchGateways, _ := match["gateways"].([]interface{})
+					for i, g := range matchGateways {
+						if gw, ok := g.(string); ok {
+							matchGateways[i] = templatedGateway(appMeta, gw)
+						}
+					}
+				}
+			}
+			destinations, _ := route["route"].([]interface{})
+			for _, d := range destinations {
+				destination, ok := d.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				host, _, _ := unstructured.NestedString(destination, "destination", "host")
+				if host != "" {
+					err := unstructured.SetNestedField(destination, templatedHost(appMeta, host), "destination", "host")
+					if err != nil {
+						return err
+					}
+				}
+				weight, ok := destination["weight"].(int64)
+				if !ok {
+					continue
+				}
+				weightName := appMeta.TrimName(strings.Split(host, ".")[0])
+				if subset, ok, _ := unstructured.NestedString(destination, "destination", "subset"); ok {
+					weightName += "-" + subset
+				}
+				weightName = uniqueName(strcase.ToLowerCamel(weightName), weightNames)
+				tpl, err := values.Add(weight, nameCamel, "weights", weightName)
+				if err != nil {
+					return errors.Wrapf(err, "unable to process %s route weight", host)
+				}
+				destination["weight"] = tpl
+			}
+		}
+	}
+	return nil
+}
+
+// templatedHost - templates service host in forms 'name', 'name.namespace' and 'name.namespace.svc.cluster.local'
+// if it refers to a chart service.
+func templatedHost(appMeta helmify.AppMetadata, host string) string {
+	parts := strings.SplitN(host, ".", 3)
+	templatedName := appMeta.TemplatedName(parts[0])
+	if templatedName == parts[0] {
+		return host
+	}
+	parts[0] = templatedName
+	if len(parts) > 1 {
+		if parts[1] != appMeta.Namespace() {
+			return host
+		}
+		parts[1] = appMeta.TemplatedNamespace(parts[1])
+	}
+	return strings.Join(parts, ".")
+}
+
+// templatedGateway - templates gateway reference in forms 'name' and 'namespace/name' if it refers to a chart gateway.
+func templatedGateway(appMeta helmify.AppMetadata, gateway string) string {
+	ns, name := "", gateway
+	if idx := strings.Index(gateway, "/"); idx != -1 {
+		ns, name = gateway[:idx], gateway[idx+1:]
+	}
+	templatedName := appMeta.TemplatedName(name)
+	if templatedName == name || (ns != "" && ns != appMeta.Namespace()) {
+		return gateway
+	}
+	if ns != "" {
+		return appMeta.TemplatedNamespace(ns) + "/" + templatedName
+	}
+	return templatedName
+}
+
+// uniqueName - returns given name or name with numeric suffix if the name is already used.
+func uniqueName(name string, used map[string]bool) string {
+	res := name
+	for i := 2; used[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+	used[res] = true
+	return res
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
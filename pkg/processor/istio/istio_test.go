@@ -0,0 +1,171 @@
+package istio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	virtualServiceYaml = `apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: my-app-reviews
+  namespace: my-app
+spec:
+  hosts:
+  - my-app-reviews
+  - reviews.example.com
+  gateways:
+  - my-app/my-app-gateway
+  - mesh
+  http:
+  - route:
+    - destination:
+        host: my-app-reviews.my-app.svc.cluster.local
+        subset: v1
+      weight: 90
+    - destination:
+        host: my-app-reviews.my-app.svc.cluster.local
+        subset: v2
+      weight: 10`
+	destinationRuleYaml = `apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  name: my-app-reviews
+  namespace: my-app
+spec:
+  host: my-app-reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1`
+	gatewayYaml = `apiVersion: networking.istio.io/v1beta1
+kind: Gateway
+metadata:
+  name: my-app-gateway
+  namespace: my-app
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - hosts:
+    - reviews.example.com
+    port:
+      name: http
+      number: 80
+      protocol: HTTP`
+)
+
+func Test_istio_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		for _, obj := range []string{virtualServiceYaml, destinationRuleYaml, gatewayYaml} {
+			processed, _, err := New().Process(&metadata.Service{}, internal.GenerateObj(obj))
+			assert.NoError(t, err)
+			assert.Equal(t, true, processed)
+		}
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := New().Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func loadMeta() *metadata.Service {
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	for _, obj := range []string{virtualServiceYaml, destinationRuleYaml, gatewayYaml} {
+		testMeta.Load(internal.GenerateObj(obj))
+	}
+	return testMeta
+}
+
+func Test_istio_Process_virtualService(t *testing.T) {
+	_, tpl, err := New().Process(loadMeta(), internal.GenerateObj(virtualServiceYaml))
+	assert.NoError(t, err)
+	assert.Equal(t, "reviews-virtualservice.yaml", tpl.Filename())
+	assert.Equal(t, helmify.Values{"reviews": map[string]interface{}{"weights": map[string]interface{}{
+		"reviewsV1": int64(90),
+		"reviewsV2": int64(10),
+	}}}, tpl.Values())
+
+	tpl.Values()["reviews"].(map[string]interface{})["weights"] = map[string]interface{}{"reviewsV1": 50, "reviewsV2": 50}
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Hosts    []string
+			Gateways []string
+			HTTP     []struct {
+				Route []struct {
+					Destination struct {
+						Host   string
+						Subset string
+					}
+					Weight int
+				}
+			} `json:"http"`
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, []string{"release-reviews", "reviews.example.com"}, res.Spec.Hosts)
+	assert.Equal(t, []string{"ns/release-gateway", "mesh"}, res.Spec.Gateways)
+	route := res.Spec.HTTP[0].Route
+	assert.Equal(t, "release-reviews.ns.svc.cluster.local", route[0].Destination.Host)
+	assert.Equal(t, 50, route[0].Weight)
+	assert.Equal(t, "v2", route[1].Destination.Subset)
+	assert.Equal(t, 50, route[1].Weight)
+}
+
+func Test_istio_Process_destinationRule(t *testing.T) {
+	_, tpl, err := New().Process(loadMeta(), internal.GenerateObj(destinationRuleYaml))
+	assert.NoError(t, err)
+	assert.Equal(t, "reviews-destinationrule.yaml", tpl.Filename())
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Host string
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "release-reviews", res.Spec.Host)
+}
+
+func Test_istio_Process_wildcardHosts(t *testing.T) {
+	for _, obj := range []string{virtualServiceYaml, gatewayYaml} {
+		obj = strings.Replace(obj, "- reviews.example.com", `- "*"`, 1)
+		_, tpl, err := New().Process(loadMeta(), internal.GenerateObj(obj))
+		assert.NoError(t, err)
+
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+		assert.NoError(t, err)
+		var res struct {
+			Spec struct {
+				Hosts   []string
+				Servers []struct {
+					Hosts []string
+				}
+			}
+		}
+		assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+		hosts := res.Spec.Hosts
+		if len(res.Spec.Servers) != 0 {
+			hosts = res.Spec.Servers[0].Hosts
+		}
+		assert.Contains(t, hosts, "*")
+	}
+}
@@ -0,0 +1,189 @@
+package job
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/processor"
+	"github.com/arttor/helmify/pkg/processor/pod"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var cronJobGVC = schema.GroupVersionKind{
+	Group:   "batch",
+	Version: "v1",
+	Kind:    "CronJob",
+}
+
+var cronJobTempl, _ = template.New("cronJob").Parse(
+	`{{- .Meta }}
+spec:
+{{ .Schedule }}
+{{- if .Spec }}
+{{ .Spec }}
+{{- end }}
+  jobTemplate:
+{{- if .JobMeta }}
+{{ .JobMeta }}
+{{- end }}
+    spec:
+{{- if .JobSpec }}
+{{ .JobSpec }}
+{{- end }}
+      template:
+        metadata:
+          labels:
+{{ .PodLabels }}
+{{- .PodAnnotations }}
+        spec:
+{{ .PodSpec }}`)
+
+// cronJobValues - CronJob spec fields moved to values with k8s defaults used for missing fields.
+var cronJobValues = []struct {
+	field string
+	dft   interface{}
+}{
+	{field: "schedule"},
+	{field: "suspend", dft: false},
+	{field: "concurrencyPolicy", dft: string(batchv1.AllowConcurrent)},
+	{field: "successfulJobsHistoryLimit", dft: int64(3)},
+	{field: "failedJobsHistoryLimit", dft: int64(1)},
+}
+
+// NewCronJob creates processor for k8s CronJob resource.
+func NewCronJob() helmify.Processor {
+	return &cronJob{}
+}
+
+type cronJob struct{}
+
+// Process k8s CronJob object into template. Returns false if not capable of processing given resource type.
+func (p cronJob) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != cronJobGVC {
+		return false, nil, nil
+	}
+	job := batchv1.CronJob{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &job)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to cast to cronjob")
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(metadata.ObjectName(obj))
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	schedule := map[string]interface{}{}
+	for _, v := range cronJobValues {
+		value, ok := spec[v.field]
+		if !ok {
+			value = v.dft
+		}
+		delete(spec, v.field)
+		if value == nil {
+			continue
+		}
+		schedule[v.field], err = values.Add(value, nameCamel, v.field)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	scheduleStr, err := yamlformat.Marshal(schedule, 2)
+	if err != nil {
+		return true, nil, err
+	}
+	scheduleStr = processor.UnquoteTemplates(scheduleStr)
+
+	jobMeta, _, _ := unstructured.NestedMap(spec, "jobTemplate", "metadata")
+	delete(jobMeta, "creationTimestamp")
+	jobTemplate, _, _ := unstructured.NestedMap(spec, "jobTemplate", "spec")
+	delete(spec, "jobTemplate")
+	specStr := ""
+	if len(spec) != 0 {
+		specStr, err = yamlformat.Marshal(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	jobMetaStr := ""
+	if len(jobMeta) != 0 {
+		// labels and annotations of Jobs created by CronJob
+		jobMetaStr, err = yamlformat.Marshal(map[string]interface{}{"metadata": jobMeta}, 4)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	origPodSpec, _, _ := unstructured.NestedMap(jobTemplate, "template", "spec")
+	delete(jobTemplate, "template")
+	jobSpec := ""
+	if len(jobTemplate) != 0 {
+		jobSpec, err = yamlformat.Marshal(jobTemplate, 6)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+
+	podTpl, err := pod.ProcessTemplate(name, appMeta, &job.Spec.JobTemplate.Spec.Template, origPodSpec, 10, pod.Options{}, &values)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &cronJobResult{
+		values: values,
+		data: struct {
+			Meta           string
+			Schedule       string
+			Spec           string
+			JobMeta        string
+			JobSpec        string
+			PodLabels      string
+			PodAnnotations string
+			PodSpec        string
+		}{
+			Meta:           meta,
+			Schedule:       scheduleStr,
+			Spec:           specStr,
+			JobMeta:        jobMetaStr,
+			JobSpec:        jobSpec,
+			PodLabels:      podTpl.Labels,
+			PodAnnotations: podTpl.Annotations,
+			PodSpec:        podTpl.Spec,
+		},
+	}, nil
+}
+
+type cronJobResult struct {
+	data struct {
+		Meta           string
+		Schedule       string
+		Spec           string
+		JobMeta        string
+		JobSpec        string
+		PodLabels      string
+		PodAnnotations string
+		PodSpec        string
+	}
+	values helmify.Values
+}
+
+func (r *cronJobResult) Filename() string {
+	return "cronjob.yaml"
+}
+
+func (r *cronJobResult) Values() helmify.Values {
+	return r.values
+}
+
+func (r *cronJobResult) Write(writer io.Writer) error {
+	return cronJobTempl.Execute(writer, r.data)
+}
@@ -0,0 +1,98 @@
+package job
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const strCronJob = `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: my-operator-cleanup
+  namespace: my-operator-system
+spec:
+  schedule: "*/5 * * * *"
+  concurrencyPolicy: Forbid
+  startingDeadlineSeconds: 100
+  jobTemplate:
+    metadata:
+      labels:
+        app: cleanup
+      annotations:
+        example.com/owner: ops
+    spec:
+      backoffLimit: 2
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+          - name: cleanup
+            image: busybox:1.35
+            args:
+            - /bin/sh
+            - -c
+            - date
+            resources:
+              limits:
+                memory: 64Mi`
+
+func Test_cronJob_Process(t *testing.T) {
+	var testInstance cronJob
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(strCronJob)
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_cronJob_Process_values(t *testing.T) {
+	var testInstance cronJob
+	obj := internal.GenerateObj(strCronJob)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	values := tpl.Values()["myOperatorCleanup"].(map[string]interface{})
+	assert.Equal(t, "*/5 * * * *", values["schedule"])
+	assert.Equal(t, false, values["suspend"])
+	assert.Equal(t, "Forbid", values["concurrencyPolicy"])
+	assert.Equal(t, int64(3), values["successfulJobsHistoryLimit"])
+	assert.Equal(t, int64(1), values["failedJobsHistoryLimit"])
+	assert.Equal(t, map[string]interface{}{"registry": "", "repository": "busybox", "tag": "1.35", "digest": ""}, values["cleanup"].(map[string]interface{})["image"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := batchv1.CronJob{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "release-my-operator-cleanup", res.Name)
+	assert.Equal(t, "*/5 * * * *", res.Spec.Schedule)
+	assert.Equal(t, batchv1.ForbidConcurrent, res.Spec.ConcurrencyPolicy)
+	assert.Equal(t, int64(100), *res.Spec.StartingDeadlineSeconds)
+	assert.Equal(t, int32(3), *res.Spec.SuccessfulJobsHistoryLimit)
+	assert.Equal(t, int32(2), *res.Spec.JobTemplate.Spec.BackoffLimit)
+	assert.Equal(t, map[string]string{"app": "cleanup"}, res.Spec.JobTemplate.Labels)
+	assert.Equal(t, map[string]string{"example.com/owner": "ops"}, res.Spec.JobTemplate.Annotations)
+	pod := res.Spec.JobTemplate.Spec.Template
+	assert.Equal(t, map[string]string{"app.kubernetes.io/instance": "release"}, pod.Labels)
+	assert.Equal(t, "busybox:1.35", pod.Spec.Containers[0].Image)
+	assert.Equal(t, []string{"/bin/sh", "-c", "date"}, pod.Spec.Containers[0].Args)
+	assert.Equal(t, resource.MustParse("64Mi"), pod.Spec.Containers[0].Resources.Limits["memory"])
+}
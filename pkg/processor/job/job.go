@@ -0,0 +1,156 @@
+package job
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/processor"
+	"github.com/arttor/helmify/pkg/processor/pod"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var jobGVC = schema.GroupVersionKind{
+	Group:   "batch",
+	Version: "v1",
+	Kind:    "Job",
+}
+
+var jobTempl, _ = template.New("job").Parse(
+	`{{- .Meta }}
+spec:
+{{ .Spec }}
+  template:
+    metadata:
+      labels:
+{{ .PodLabels }}
+{{- .PodAnnotations }}
+    spec:
+{{ .PodSpec }}`)
+
+// defaultBackoffLimit - k8s default for Job backoffLimit.
+const defaultBackoffLimit = 6
+
+// hookAnnotations - annotations making Job a Helm hook. Job spec is immutable, so previous hook is deleted before a new one.
+var hookAnnotations = map[string]string{
+	"helm.sh/hook":               "pre-install,pre-upgrade",
+	"helm.sh/hook-delete-policy": "before-hook-creation",
+}
+
+// generatedLabels - pod labels set by Job controller.
+var generatedLabels = []string{"controller-uid", "job-name", "batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name"}
+
+// NewJob creates processor for k8s Job resource.
+func NewJob() helmify.Processor {
+	return &job{}
+}
+
+type job struct{}
+
+// Process k8s Job object into template. Returns false if not capable of processing given resource type.
+func (p job) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != jobGVC {
+		return false, nil, nil
+	}
+	j := batchv1.Job{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &j)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to cast to job")
+	}
+	if appMeta.Config().JobHooks {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range hookAnnotations {
+			annotations[k] = v
+		}
+		obj.SetAnnotations(annotations)
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(metadata.ObjectName(obj))
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	origPodSpec, _, _ := unstructured.NestedMap(spec, "template", "spec")
+	delete(spec, "template")
+	// selector is generated by Job controller
+	delete(spec, "selector")
+	delete(spec, "manualSelector")
+	if _, ok := spec["backoffLimit"]; !ok {
+		spec["backoffLimit"] = int64(defaultBackoffLimit)
+	}
+	for _, field := range []string{"backoffLimit", "ttlSecondsAfterFinished"} {
+		value, ok := spec[field]
+		if !ok {
+			continue
+		}
+		spec[field], err = values.Add(value, nameCamel, field)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	specStr, err := yamlformat.Marshal(spec, 2)
+	if err != nil {
+		return true, nil, err
+	}
+	specStr = processor.UnquoteTemplates(specStr)
+
+	for _, l := range generatedLabels {
+		delete(j.Spec.Template.Labels, l)
+	}
+	podTpl, err := pod.ProcessTemplate(name, appMeta, &j.Spec.Template, origPodSpec, 6, pod.Options{}, &values)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &jobResult{
+		values: values,
+		data: struct {
+			Meta           string
+			Spec           string
+			PodLabels      string
+			PodAnnotations string
+			PodSpec        string
+		}{
+			Meta:           meta,
+			Spec:           specStr,
+			PodLabels:      podTpl.Labels,
+			PodAnnotations: podTpl.Annotations,
+			PodSpec:        podTpl.Spec,
+		},
+	}, nil
+}
+
+type jobResult struct {
+	data struct {
+		Meta           string
+		Spec           string
+		PodLabels      string
+		PodAnnotations string
+		PodSpec        string
+	}
+	values helmify.Values
+}
+
+func (r *jobResult) Filename() string {
+	return "job.yaml"
+}
+
+func (r *jobResult) Values() helmify.Values {
+	return r.values
+}
+
+func (r *jobResult) Write(writer io.Writer) error {
+	return jobTempl.Execute(writer, r.data)
+}
@@ -0,0 +1,130 @@
+package job
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const strJob = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: my-operator-migrate
+  namespace: my-operator-system
+spec:
+  ttlSecondsAfterFinished: 300
+  selector:
+    matchLabels:
+      controller-uid: 6d4f8c7b
+  template:
+    metadata:
+      labels:
+        app: migrate
+        controller-uid: 6d4f8c7b
+        job-name: my-operator-migrate
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: migrate/migrate:v4.15.2
+        args: ["up"]
+        resources:
+          requests:
+            cpu: 100m`
+
+func Test_job_Process(t *testing.T) {
+	var testInstance job
+
+	t.Run("processed", func(t *testing.T) {
+		obj := internal.GenerateObj(strJob)
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		obj := internal.TestNs
+		processed, _, err := testInstance.Process(&metadata.Service{}, obj)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_job_Process_values(t *testing.T) {
+	for _, jobHooks := range []bool{false, true} {
+		var testInstance job
+		obj := internal.GenerateObj(strJob)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name", JobHooks: jobHooks})
+		testMeta.Load(obj)
+		_, tpl, err := testInstance.Process(testMeta, obj)
+		assert.NoError(t, err)
+		values := tpl.Values()["myOperatorMigrate"].(map[string]interface{})
+		assert.Equal(t, int64(6), values["backoffLimit"])
+		assert.Equal(t, int64(300), values["ttlSecondsAfterFinished"])
+		assert.Equal(t, map[string]interface{}{"registry": "", "repository": "migrate/migrate", "tag": "v4.15.2", "digest": ""}, values["migrate"].(map[string]interface{})["image"])
+
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+		assert.NoError(t, err)
+		res := batchv1.Job{}
+		assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+		assert.Equal(t, int32(6), *res.Spec.BackoffLimit)
+		assert.Equal(t, int32(300), *res.Spec.TTLSecondsAfterFinished)
+		assert.Nil(t, res.Spec.Selector)
+		assert.Equal(t, map[string]string{"app": "migrate", "app.kubernetes.io/instance": "release"}, res.Spec.Template.Labels)
+		assert.Equal(t, "migrate/migrate:v4.15.2", res.Spec.Template.Spec.Containers[0].Image)
+		if jobHooks {
+			assert.Equal(t, "pre-install,pre-upgrade", res.Annotations["helm.sh/hook"])
+		} else {
+			assert.Empty(t, res.Annotations)
+		}
+	}
+}
+
+func Test_job_Process_generateName(t *testing.T) {
+	var testInstance job
+	obj := internal.GenerateObj(strings.Replace(strJob, "name: my-operator-migrate\n  namespace", "generateName: my-operator-migrate-\n  namespace", 1))
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", JobHooks: true})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.NotContains(t, tpl.Values(), "")
+	values := tpl.Values()["myOperatorMigrate"].(map[string]interface{})
+	assert.Equal(t, int64(6), values["backoffLimit"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), "backoffLimit: {{ .Values.myOperatorMigrate.backoffLimit }}")
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := batchv1.Job{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.True(t, strings.HasSuffix(res.Name, "-my-operator-migrate-0"), res.Name)
+	assert.Equal(t, "pre-install,pre-upgrade", res.Annotations["helm.sh/hook"])
+	assert.Equal(t, int32(6), *res.Spec.BackoffLimit)
+	assert.Equal(t, "migrate/migrate:v4.15.2", res.Spec.Template.Spec.Containers[0].Image)
+}
+
+func Test_job_Process_quotedArgs(t *testing.T) {
+	var testInstance job
+	obj := internal.GenerateObj(strings.Replace(strJob, `args: ["up"]`, `args: ["echo '*' hi", "echo 'it''s' done", "*"]`, 1))
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := batchv1.Job{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, []string{"echo '*' hi", "echo 'it''s' done", "*"}, res.Spec.Template.Spec.Containers[0].Args)
+}
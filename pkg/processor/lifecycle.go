@@ -0,0 +1,9 @@
+package processor
+
+import "github.com/arttor/helmify/pkg/helmify"
+
+// TemplateLifecycle - moves containers lifecycle hooks to <name>.<container name>.lifecycle values.
+// Pod spec is expected to be marshaled with given indent.
+func TemplateLifecycle(name string, podSpec map[string]interface{}, indent int, values *helmify.Values) error {
+	return templateContainersField(name, "lifecycle", "", podSpec, indent, values)
+}
@@ -0,0 +1,44 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplateLifecycle(t *testing.T) {
+	lifecycle := map[string]interface{}{
+		"preStop": map[string]interface{}{
+			"exec": map[string]interface{}{"command": []interface{}{"sleep", "5"}},
+		},
+	}
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "nginx", "lifecycle": lifecycle},
+			map[string]interface{}{"name": "sidecar", "image": "busybox"},
+		},
+	}
+	values := helmify.Values{}
+	err := TemplateLifecycle("web", podSpec, 0, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"web": map[string]interface{}{
+		"app": map[string]interface{}{"lifecycle": lifecycle},
+	}}, values)
+
+	assert.NoError(t, unstructured.SetNestedStringSlice(values, []string{"sleep", "15"}, "web", "app", "lifecycle", "preStop", "exec", "command"))
+	spec, err := yaml.Marshal(podSpec, 0)
+	assert.NoError(t, err)
+	rendered, err := internal.RenderTemplate("chart-name", strings.ReplaceAll(spec, "'", ""), values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, []string{"sleep", "15"}, res.Containers[0].Lifecycle.PreStop.Exec.Command)
+	assert.Nil(t, res.Containers[1].Lifecycle)
+}
@@ -6,14 +6,16 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
 )
 
 const metaTeml = `apiVersion: %[1]s
 kind: %[2]s
 metadata:
-  name: %[3]s
+  name: %[3]s%[7]s
   labels:
 %[5]s
   {{- include "%[4]s.labels" . | nindent 4 }}
@@ -47,9 +49,45 @@ func ProcessObjMeta(appMeta helmify.AppMetadata, obj *unstructured.Unstructured)
 		}
 	}
 	templatedName := appMeta.TemplatedName(obj.GetName())
+	if obj.GetName() == "" && obj.GetGenerateName() != "" {
+		// Helm does not support generateName: make name unique for every release revision instead.
+		templatedName = appMeta.TemplatedName(metadata.ObjectName(obj)) + "-{{ .Release.Revision }}"
+	}
 	apiVersion, kind := obj.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
-	metaStr := fmt.Sprintf(metaTeml, apiVersion, kind, templatedName, appMeta.ChartName(), labels, annotations)
+	metaStr := fmt.Sprintf(metaTeml, apiVersion, kind, templatedName, appMeta.ChartName(), labels, annotations, namespaceLine(appMeta, obj))
 	metaStr = strings.Trim(metaStr, " \n")
 	metaStr = strings.Replace(metaStr, "\n\n", "\n", -1)
 	return metaStr, nil
 }
+
+// clusterScopedKinds - kinds of common cluster-scoped resources not getting namespace in release namespace mode.
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"ClusterIssuer":                  true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"IngressClass":                   true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"PersistentVolume":               true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+// namespaceLine - returns metadata namespace line according to namespace mode. Empty if namespace is dropped.
+func namespaceLine(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) string {
+	switch appMeta.Config().NamespaceMode {
+	case config.NamespaceKeep:
+		if obj.GetNamespace() != "" {
+			return "\n  namespace: " + obj.GetNamespace()
+		}
+	case config.NamespaceRelease:
+		if !clusterScopedKinds[obj.GetKind()] {
+			return "\n  namespace: " + appMeta.TemplatedNamespace(obj.GetNamespace())
+		}
+	}
+	return ""
+}
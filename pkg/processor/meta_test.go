@@ -17,3 +17,58 @@ func TestProcessObjMeta(t *testing.T) {
 	assert.Contains(t, res, "chart-name.labels")
 	assert.Contains(t, res, "chart-name.fullname")
 }
+
+func TestProcessObjMeta_generateName(t *testing.T) {
+	job := internal.GenerateObj(`apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: my-app-migrate-
+  annotations:
+    helm.sh/hook: pre-upgrade
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: migrate:1.0.0`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(job)
+	testMeta.Load(internal.GenerateObj(pvcYaml))
+	res, err := ProcessObjMeta(testMeta, job)
+	assert.NoError(t, err)
+	assert.Contains(t, res, `name: {{ include "chart-name.fullname" . }}-app-migrate-{{ .Release.Revision }}`)
+	assert.Contains(t, res, "helm.sh/hook: pre-upgrade")
+	assert.NotContains(t, res, "generateName")
+}
+
+func TestProcessObjMeta_namespaceMode(t *testing.T) {
+	svc := internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-web
+  namespace: my-ns`)
+	role := internal.GenerateObj(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: my-app-role`)
+	process := func(mode string) (string, string) {
+		testMeta := metadata.New(config.Config{ChartName: "chart-name", NamespaceMode: mode})
+		testMeta.Load(svc)
+		testMeta.Load(role)
+		svcRes, err := ProcessObjMeta(testMeta, svc)
+		assert.NoError(t, err)
+		roleRes, err := ProcessObjMeta(testMeta, role)
+		assert.NoError(t, err)
+		return svcRes, roleRes
+	}
+	svcRes, roleRes := process("")
+	assert.NotContains(t, svcRes, "namespace:")
+	assert.NotContains(t, roleRes, "namespace:")
+	svcRes, roleRes = process(config.NamespaceKeep)
+	assert.Contains(t, svcRes, "\n  namespace: my-ns\n")
+	assert.NotContains(t, roleRes, "namespace:")
+	svcRes, roleRes = process(config.NamespaceRelease)
+	assert.Contains(t, svcRes, "\n  namespace: {{ .Release.Namespace }}\n")
+	assert.NotContains(t, roleRes, "namespace:")
+}
@@ -0,0 +1,130 @@
+package monitoring
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// monitorTempl - %[1]s - values name under metrics, %[2]s - metadata, %[3]s - spec.
+const monitorTempl = `{{- if .Values.metrics.%[1]s.enabled }}
+%[2]s
+%[3]s
+{{- end }}`
+
+// helmSelectorLabels - labels replaced by chart selectorLabels.
+var helmSelectorLabels = []string{"app.kubernetes.io/name", "app.kubernetes.io/instance"}
+
+var serviceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+var podMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PodMonitor",
+}
+
+// ServiceMonitor creates processor for Prometheus Operator ServiceMonitor resource.
+func ServiceMonitor() helmify.Processor {
+	return &monitor{gvk: serviceMonitorGVK}
+}
+
+// PodMonitor creates processor for Prometheus Operator PodMonitor resource.
+func PodMonitor() helmify.Processor {
+	return &monitor{gvk: podMonitorGVK}
+}
+
+type monitor struct {
+	gvk schema.GroupVersionKind
+}
+
+// Process ServiceMonitor or PodMonitor object into template. Returns false if not capable of processing given resource type.
+// Monitors select chart objects by chart selectorLabels and are created if metrics.<serviceMonitor|podMonitor>.enabled value is set.
+func (m monitor) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != m.gvk {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to get %s spec", m.gvk.Kind)
+	}
+	matchLabels, _, err := unstructured.NestedStringMap(spec, "selector", "matchLabels")
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to get %s selector", m.gvk.Kind)
+	}
+	for _, l := range helmSelectorLabels {
+		unstructured.RemoveNestedField(spec, "selector", "matchLabels", l)
+	}
+	if len(matchLabels) != 0 {
+		err = processor.MarkSelectorLabels(spec, "selector", "matchLabels")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if appMeta.Namespace() != "" || appMeta.Config().NamespaceMode == config.NamespaceRelease {
+		matchNames, _, _ := unstructured.NestedStringSlice(spec, "namespaceSelector", "matchNames")
+		templated := make([]interface{}, len(matchNames))
+		for i, n := range matchNames {
+			templated[i] = appMeta.TemplatedNamespace(n)
+		}
+		if len(templated) != 0 {
+			err = unstructured.SetNestedSlice(spec, templated, "namespaceSelector", "matchNames")
+			if err != nil {
+				return true, nil, err
+			}
+		}
+	}
+	specYaml, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	specYaml = processor.UnquoteTemplates(specYaml)
+	// spec -> selector -> matchLabels -> entries
+	specYaml = processor.ReplaceSelectorLabelsMark(specYaml, appMeta.ChartName(), 6)
+
+	valuesName := strcase.ToLowerCamel(m.gvk.Kind)
+	values := helmify.Values{}
+	_, err = values.Add(true, "metrics", valuesName, "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &result{
+		name:   appMeta.TrimName(obj.GetName()),
+		data:   []byte(fmt.Sprintf(monitorTempl, valuesName, meta, specYaml)),
+		values: values,
+	}, nil
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
@@ -0,0 +1,113 @@
+package monitoring
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const serviceMonitorYaml = `apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: my-operator-metrics-monitor
+  namespace: my-operator-system
+spec:
+  endpoints:
+  - path: /metrics
+    port: https
+    scheme: https
+  namespaceSelector:
+    matchNames:
+    - my-operator-system
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: my-operator
+      control-plane: controller-manager`
+
+func Test_monitor_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := ServiceMonitor().Process(&metadata.Service{}, internal.GenerateObj(serviceMonitorYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := PodMonitor().Process(&metadata.Service{}, internal.GenerateObj(serviceMonitorYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_monitor_Process_values(t *testing.T) {
+	obj := internal.GenerateObj(serviceMonitorYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-metrics-service
+  namespace: my-operator-system`))
+	_, tpl, err := ServiceMonitor().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"metrics": map[string]interface{}{
+		"serviceMonitor": map[string]interface{}{"enabled": true},
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			NamespaceSelector struct {
+				MatchNames []string `json:"matchNames"`
+			} `json:"namespaceSelector"`
+			Selector struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			}
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, []string{"ns"}, res.Spec.NamespaceSelector.MatchNames)
+	assert.Equal(t, map[string]string{
+		"app.kubernetes.io/instance": "release",
+		"control-plane":              "controller-manager",
+	}, res.Spec.Selector.MatchLabels)
+
+	tpl.Values()["metrics"].(map[string]interface{})["serviceMonitor"].(map[string]interface{})["enabled"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_monitor_Process_quotedRelabelings(t *testing.T) {
+	obj := internal.GenerateObj(strings.Replace(serviceMonitorYaml, "    scheme: https", `    scheme: https
+    relabelings:
+    - regex: '*'
+      replacement: 'it''s'
+      action: replace`, 1))
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := ServiceMonitor().Process(testMeta, obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Endpoints []struct {
+				Relabelings []map[string]string `json:"relabelings"`
+			} `json:"endpoints"`
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, []map[string]string{{"regex": "*", "replacement": "it's", "action": "replace"}}, res.Spec.Endpoints[0].Relabelings)
+}
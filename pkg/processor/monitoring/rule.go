@@ -0,0 +1,171 @@
+package monitoring
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var prometheusRuleGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// thresholdRegexp - matches numeric threshold compared with alert expression, e.g. '... > 0.9'.
+var thresholdRegexp = regexp.MustCompile(`(?s)^(.*\S)\s*(>=|<=|==|!=|>|<)\s*(-?[0-9]+(?:\.[0-9]+)?)\s*$`)
+
+// escapeTemplates - escapes Prometheus templates like '{{ $value }}' from Helm.
+var escapeTemplates = strings.NewReplacer("{{", `{{"{{"}}`, "}}", `{{"}}"}}`)
+
+// PrometheusRule creates processor for Prometheus Operator PrometheusRule resource.
+func PrometheusRule() helmify.Processor {
+	return &rule{}
+}
+
+type rule struct{}
+
+// Process PrometheusRule object into template. Returns false if not capable of processing given resource type.
+// Rule groups are kept as is. Alert thresholds, 'for' durations and severity labels are moved to
+// metrics.rules.alerts.<alert name> values. Template is created if metrics.rules.enabled value is set.
+func (r rule) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != prometheusRuleGVK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	groups, _, err := unstructured.NestedSlice(obj.Object, "spec", "groups")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get PrometheusRule groups")
+	}
+	values := helmify.Values{}
+	_, err = values.Add(true, "metrics", "rules", "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+	placeholders := map[string]string{}
+	alertNames := map[string]bool{}
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rules, _ := group["rules"].([]interface{})
+		for _, rl := range rules {
+			promRule, ok := rl.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			escapeRule(promRule)
+			alert, ok := promRule["alert"].(string)
+			if !ok {
+				continue
+			}
+			alertName := uniqueName(strcase.ToLowerCamel(alert), alertNames)
+			err = liftAlertValues(promRule, alertName, &values, placeholders)
+			if err != nil {
+				return true, nil, errors.Wrapf(err, "unable to process alert %s", alert)
+			}
+		}
+	}
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	spec["groups"] = groups
+	specYaml, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	for placeholder, t := range placeholders {
+		specYaml = strings.ReplaceAll(specYaml, placeholder, t)
+	}
+	return true, &result{
+		name:   appMeta.TrimName(obj.GetName()),
+		data:   []byte(fmt.Sprintf(monitorTempl, "rules", meta, specYaml)),
+		values: values,
+	}, nil
+}
+
+// escapeRule - escapes Prometheus templates in rule expression, labels and annotations.
+func escapeRule(promRule map[string]interface{}) {
+	if expr, ok := promRule["expr"].(string); ok {
+		promRule["expr"] = escapeTemplates.Replace(expr)
+	}
+	for _, field := range []string{"labels", "annotations"} {
+		m, ok := promRule[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k, v := range m {
+			if str, ok := v.(string); ok {
+				m[k] = escapeTemplates.Replace(str)
+			}
+		}
+	}
+}
+
+// liftAlertValues - moves alert threshold, 'for' duration and severity label to values.
+// Fields are replaced with placeholders to be substituted with templates after marshaling.
+func liftAlertValues(promRule map[string]interface{}, alertName string, values *helmify.Values, placeholders map[string]string) error {
+	path := []string{"metrics", "rules", "alerts", alertName}
+	addPlaceholder := func(value interface{}, field string) (string, error) {
+		tpl, err := values.Add(value, append(path, field)...)
+		if err != nil {
+			return "", err
+		}
+		placeholder := fmt.Sprintf("helmify-rule-value-%d-", len(placeholders))
+		placeholders[placeholder] = tpl
+		return placeholder, nil
+	}
+	if expr, ok := promRule["expr"].(string); ok {
+		if match := thresholdRegexp.FindStringSubmatch(expr); match != nil {
+			var threshold interface{}
+			if i, err := strconv.ParseInt(match[3], 10, 64); err == nil {
+				threshold = i
+			} else if f, err := strconv.ParseFloat(match[3], 64); err == nil {
+				threshold = f
+			}
+			placeholder, err := addPlaceholder(threshold, "threshold")
+			if err != nil {
+				return err
+			}
+			promRule["expr"] = match[1] + " " + match[2] + " " + placeholder
+		}
+	}
+	if forDuration, ok := promRule["for"].(string); ok {
+		placeholder, err := addPlaceholder(forDuration, "for")
+		if err != nil {
+			return err
+		}
+		promRule["for"] = placeholder
+	}
+	if labels, ok := promRule["labels"].(map[string]interface{}); ok {
+		if severity, ok := labels["severity"].(string); ok {
+			placeholder, err := addPlaceholder(severity, "severity")
+			if err != nil {
+				return err
+			}
+			labels["severity"] = placeholder
+		}
+	}
+	return nil
+}
+
+// uniqueName - returns given name or name with numeric suffix if the name is already used.
+func uniqueName(name string, used map[string]bool) string {
+	res := name
+	for i := 2; used[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+	used[res] = true
+	return res
+}
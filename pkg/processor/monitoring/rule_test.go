@@ -0,0 +1,88 @@
+package monitoring
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const prometheusRuleYaml = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: my-operator-rules
+  namespace: my-operator-system
+spec:
+  groups:
+  - name: my-operator
+    rules:
+    - record: job:reconcile_errors:rate5m
+      expr: sum by (job) (rate(controller_runtime_reconcile_errors_total[5m]))
+    - alert: ReconcileErrors
+      expr: job:reconcile_errors:rate5m > 0.5
+      for: 10m
+      labels:
+        severity: warning
+      annotations:
+        summary: "Reconcile error rate is {{ $value }} for {{ $labels.job }}"
+    - alert: ReconcileErrors
+      expr: job:reconcile_errors:rate5m > 2
+      labels:
+        severity: critical`
+
+func Test_rule_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := PrometheusRule().Process(&metadata.Service{}, internal.GenerateObj(prometheusRuleYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := PrometheusRule().Process(&metadata.Service{}, internal.GenerateObj(serviceMonitorYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_rule_Process_values(t *testing.T) {
+	obj := internal.GenerateObj(prometheusRuleYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := PrometheusRule().Process(testMeta, obj)
+	assert.NoError(t, err)
+	rules := tpl.Values()["metrics"].(map[string]interface{})["rules"].(map[string]interface{})
+	assert.Equal(t, true, rules["enabled"])
+	assert.Equal(t, map[string]interface{}{
+		"reconcileErrors":  map[string]interface{}{"threshold": 0.5, "for": "10m", "severity": "warning"},
+		"reconcileErrors2": map[string]interface{}{"threshold": int64(2), "severity": "critical"},
+	}, rules["alerts"])
+
+	rules["alerts"].(map[string]interface{})["reconcileErrors"].(map[string]interface{})["threshold"] = 0.1
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Groups []struct {
+				Rules []map[string]interface{}
+			}
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	promRules := res.Spec.Groups[0].Rules
+	assert.Equal(t, "sum by (job) (rate(controller_runtime_reconcile_errors_total[5m]))", promRules[0]["expr"])
+	assert.Equal(t, "job:reconcile_errors:rate5m > 0.1", promRules[1]["expr"])
+	assert.Equal(t, "10m", promRules[1]["for"])
+	assert.Equal(t, map[string]interface{}{"summary": "Reconcile error rate is {{ $value }} for {{ $labels.job }}"}, promRules[1]["annotations"])
+	assert.Equal(t, "job:reconcile_errors:rate5m > 2", promRules[2]["expr"])
+	assert.Equal(t, map[string]interface{}{"severity": "critical"}, promRules[2]["labels"])
+
+	rules["enabled"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Empty(t, rendered)
+}
@@ -0,0 +1,150 @@
+package openshift
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	"github.com/arttor/helmify/pkg/processor/pod"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var deploymentConfigGVK = schema.GroupVersionKind{
+	Group:   "apps.openshift.io",
+	Version: "v1",
+	Kind:    "DeploymentConfig",
+}
+
+var deploymentConfigTempl, _ = template.New("deploymentConfig").Parse(
+	`{{- .Meta }}
+spec:
+{{- if .Spec }}
+{{ .Spec }}
+{{- end }}
+  selector:
+{{ .Selector }}
+  triggers:
+{{ .Triggers }}
+  template:
+    metadata:
+      labels:
+{{ .PodLabels }}
+{{- .PodAnnotations }}
+    spec:
+{{ .PodSpec }}`)
+
+// NewDeploymentConfig creates processor for OpenShift DeploymentConfig resource.
+func NewDeploymentConfig() helmify.Processor {
+	return &deploymentConfig{}
+}
+
+type deploymentConfig struct{}
+
+// Process OpenShift DeploymentConfig object into template. Returns false if not capable of processing given resource type.
+// Pod template is processed like Deployment one. Replicas and triggers are moved to values.
+func (d deploymentConfig) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != deploymentConfigGVK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get DeploymentConfig spec")
+	}
+	selector, _, err := unstructured.NestedStringMap(spec, "selector")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get DeploymentConfig selector")
+	}
+	triggers, _ := spec["triggers"].([]interface{})
+	if triggers == nil {
+		triggers = []interface{}{}
+	}
+	err = unstructured.SetNestedSlice(values, triggers, nameCamel, "triggers")
+	if err != nil {
+		return true, nil, err
+	}
+	podTemplateMap, _ := spec["template"].(map[string]interface{})
+	delete(spec, "selector")
+	delete(spec, "triggers")
+	delete(spec, "template")
+	if replicas, ok := spec["replicas"]; ok {
+		spec["replicas"], err = values.Add(replicas, nameCamel, "replicas")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	specStr := ""
+	if len(spec) != 0 {
+		specStr, err = yamlformat.Marshal(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		specStr = processor.UnquoteTemplates(specStr)
+	}
+	selectorStr := ""
+	if len(selector) != 0 {
+		selectorStr, err = yamlformat.Marshal(selector, 4)
+		if err != nil {
+			return true, nil, err
+		}
+		selectorStr += "\n"
+	}
+	// DeploymentConfig selector is a plain labels map
+	selectorStr += fmt.Sprintf(`    {{- include "%s.selectorLabels" . | nindent 4 }}`, appMeta.ChartName())
+
+	podTpl := corev1.PodTemplateSpec{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(podTemplateMap, &podTpl)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to cast DeploymentConfig pod template")
+	}
+	origPodSpec, _, _ := unstructured.NestedMap(podTemplateMap, "spec")
+	processed, err := pod.ProcessTemplate(name, appMeta, &podTpl, origPodSpec, 6, pod.Options{}, &values)
+	if err != nil {
+		return true, nil, err
+	}
+	res := &dcResult{values: values}
+	res.data.Meta, res.data.Spec, res.data.Selector = meta, specStr, selectorStr
+	res.data.Triggers = fmt.Sprintf(`    {{- toYaml .Values.%s.triggers | nindent 4 }}`, nameCamel)
+	res.data.PodLabels, res.data.PodAnnotations, res.data.PodSpec = processed.Labels, processed.Annotations, processed.Spec
+	return true, res, nil
+}
+
+type dcResult struct {
+	data struct {
+		Meta           string
+		Spec           string
+		Selector       string
+		Triggers       string
+		PodLabels      string
+		PodAnnotations string
+		PodSpec        string
+	}
+	values helmify.Values
+}
+
+func (r *dcResult) Filename() string {
+	return "deploymentconfig.yaml"
+}
+
+func (r *dcResult) Values() helmify.Values {
+	return r.values
+}
+
+func (r *dcResult) Write(writer io.Writer) error {
+	return deploymentConfigTempl.Execute(writer, r.data)
+}
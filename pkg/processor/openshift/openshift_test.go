@@ -0,0 +1,173 @@
+package openshift
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	routeYaml = `apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: my-app-web
+  namespace: my-app
+spec:
+  host: web.apps.example.com
+  to:
+    kind: Service
+    name: my-app-svc
+  port:
+    targetPort: http
+  tls:
+    termination: edge
+    insecureEdgeTerminationPolicy: Redirect`
+	deploymentConfigYaml = `apiVersion: apps.openshift.io/v1
+kind: DeploymentConfig
+metadata:
+  name: my-app-api
+  namespace: my-app
+spec:
+  replicas: 2
+  selector:
+    app: api
+  strategy:
+    type: Rolling
+  triggers:
+  - type: ConfigChange
+  - type: ImageChange
+    imageChangeParams:
+      automatic: true
+      containerNames:
+      - api
+      from:
+        kind: ImageStreamTag
+        name: api:latest
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: api
+        image: example.com/api:1.0.0`
+)
+
+func loadMeta() *metadata.Service {
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(internal.GenerateObj(routeYaml))
+	testMeta.Load(internal.GenerateObj(deploymentConfigYaml))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-svc
+  namespace: my-app`))
+	return testMeta
+}
+
+func Test_route_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := NewRoute().Process(&metadata.Service{}, internal.GenerateObj(routeYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := NewRoute().Process(&metadata.Service{}, internal.GenerateObj(deploymentConfigYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_route_Process_values(t *testing.T) {
+	_, tpl, err := NewRoute().Process(loadMeta(), internal.GenerateObj(routeYaml))
+	assert.NoError(t, err)
+	values := tpl.Values()
+	routeValues := values["route"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"enabled": true,
+		"host":    "web.apps.example.com",
+		"tls":     map[string]interface{}{"termination": "edge", "insecureEdgeTerminationPolicy": "Redirect"},
+	}, routeValues["web"])
+
+	routeValues["web"].(map[string]interface{})["host"] = "web.example.org"
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Host string
+			To   struct {
+				Name string
+			}
+			TLS struct {
+				Termination string
+			} `json:"tls"`
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "web.example.org", res.Spec.Host)
+	assert.Equal(t, "release-svc", res.Spec.To.Name)
+	assert.Equal(t, "edge", res.Spec.TLS.Termination)
+
+	routeValues["web"].(map[string]interface{})["enabled"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_deploymentConfig_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := NewDeploymentConfig().Process(&metadata.Service{}, internal.GenerateObj(deploymentConfigYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := NewDeploymentConfig().Process(&metadata.Service{}, internal.GenerateObj(routeYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_deploymentConfig_Process_values(t *testing.T) {
+	_, tpl, err := NewDeploymentConfig().Process(loadMeta(), internal.GenerateObj(deploymentConfigYaml))
+	assert.NoError(t, err)
+	values := tpl.Values()
+	api := values["api"].(map[string]interface{})
+	assert.Equal(t, int64(2), api["replicas"])
+	assert.Len(t, api["triggers"], 2)
+	assert.Equal(t, map[string]interface{}{"registry": "example.com", "repository": "api", "tag": "1.0.0", "digest": ""}, api["api"].(map[string]interface{})["image"])
+
+	values["kubernetesClusterDomain"] = "cluster.local"
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Replicas int
+			Selector map[string]string
+			Strategy struct {
+				Type string
+			}
+			Triggers []map[string]interface{}
+			Template struct {
+				Metadata struct {
+					Labels map[string]string
+				}
+			}
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, 2, res.Spec.Replicas)
+	assert.Equal(t, map[string]string{"app": "api", "app.kubernetes.io/instance": "release"}, res.Spec.Selector)
+	assert.Equal(t, res.Spec.Selector, res.Spec.Template.Metadata.Labels)
+	assert.Equal(t, "Rolling", res.Spec.Strategy.Type)
+	assert.Equal(t, "ImageChange", res.Spec.Triggers[1]["type"])
+}
@@ -0,0 +1,125 @@
+package openshift
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// routeTempl - %[1]s - values name, %[2]s - metadata, %[3]s - spec.
+const routeTempl = `{{- if .Values.route.%[1]s.enabled }}
+%[2]s
+%[3]s
+{{- end }}`
+
+var routeGVK = schema.GroupVersionKind{
+	Group:   "route.openshift.io",
+	Version: "v1",
+	Kind:    "Route",
+}
+
+// routeTLSFields - Route tls fields moved to values.
+var routeTLSFields = []string{"termination", "insecureEdgeTerminationPolicy"}
+
+// NewRoute creates processor for OpenShift Route resource.
+func NewRoute() helmify.Processor {
+	return &route{}
+}
+
+type route struct{}
+
+// Process OpenShift Route object into template. Returns false if not capable of processing given resource type.
+// Like Ingress, the route is created if route.<name>.enabled value is set. Host and tls termination are moved to
+// route.<name> values, separate from values of Ingress or Service with the same name.
+func (r route) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != routeGVK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get route spec")
+	}
+	values := helmify.Values{}
+	_, err = values.Add(true, "route", nameCamel, "enabled")
+	if err != nil {
+		return true, nil, err
+	}
+	if host, ok := spec["host"].(string); ok {
+		spec["host"], err = values.Add(host, "route", nameCamel, "host")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if tls, ok := spec["tls"].(map[string]interface{}); ok {
+		for _, field := range routeTLSFields {
+			value, ok := tls[field].(string)
+			if !ok {
+				continue
+			}
+			tls[field], err = values.Add(value, "route", nameCamel, "tls", field)
+			if err != nil {
+				return true, nil, err
+			}
+		}
+	}
+	if to, ok := spec["to"].(map[string]interface{}); ok {
+		templateBackend(appMeta, to)
+	}
+	backends, _ := spec["alternateBackends"].([]interface{})
+	for _, b := range backends {
+		if backend, ok := b.(map[string]interface{}); ok {
+			templateBackend(appMeta, backend)
+		}
+	}
+	specStr, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &result{
+		name:   name + ".yaml",
+		data:   []byte(fmt.Sprintf(routeTempl, nameCamel, meta, processor.UnquoteTemplates(specStr))),
+		values: values,
+	}, nil
+}
+
+// templateBackend - templates route backend name if it is a chart service.
+func templateBackend(appMeta helmify.AppMetadata, backend map[string]interface{}) {
+	if kind, ok := backend["kind"].(string); ok && kind != "Service" {
+		return
+	}
+	if svc, ok := backend["name"].(string); ok {
+		backend["name"] = appMeta.TemplatedName(svc)
+	}
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
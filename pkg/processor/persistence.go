@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// persistenceMarkKey - temporary volume field replacing chart PVC volume source.
+	// Sorted after volume name, so it is never marshaled as the first field of a list item.
+	persistenceMarkKey = "persistentVolumeClaimHelmifyPersistence"
+	// noPersistenceMark - name prefix of temporary volume replaced with emptyDir volume used if persistence is disabled.
+	noPersistenceMark = "helmify-no-persistence-"
+)
+
+// noPersistenceMarkRegexp - matches marshaled volume with noPersistenceMark name. Captures indent and volume name.
+var noPersistenceMarkRegexp = regexp.MustCompile(`(?m)^( *)- name: ` + noPersistenceMark + `(\S+)$`)
+
+// noPersistenceTempl - emptyDir volume replacing claim template if persistence is disabled. %[1]s - indent, %[2]s - volume name.
+const noPersistenceTempl = `%[1]s{{- if not .Values.persistence.enabled }}
+%[1]s- name: %[2]s
+%[1]s  emptyDir: {}
+%[1]s{{- end }}`
+
+// MarkPersistence - marks pod volumes using chart PVCs to be replaced with emptyDir if persistence.enabled value is not set.
+// Volumes for given StatefulSet volume claim templates are added the same way. Marks have to be replaced
+// with ReplacePersistenceMarks after marshaling.
+func MarkPersistence(podSpec map[string]interface{}, claimTemplates []string, values *helmify.Values) error {
+	volumes, _, err := unstructured.NestedSlice(podSpec, "volumes")
+	if err != nil {
+		return err
+	}
+	marked := len(claimTemplates) != 0
+	for _, v := range volumes {
+		volume, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		claimName, _, _ := unstructured.NestedString(volume, "persistentVolumeClaim", "claimName")
+		// only PVCs from the chart are created if persistence is enabled
+		if !strings.HasPrefix(claimName, "{{") {
+			continue
+		}
+		volume[persistenceMarkKey] = volume["persistentVolumeClaim"]
+		delete(volume, "persistentVolumeClaim")
+		marked = true
+	}
+	for _, claim := range claimTemplates {
+		volumes = append(volumes, map[string]interface{}{"name": noPersistenceMark + claim})
+	}
+	if !marked {
+		return nil
+	}
+	_, err = values.Add(true, "persistence", "enabled")
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedSlice(podSpec, volumes, "volumes")
+}
+
+// ReplacePersistenceMarks - replaces marks added by MarkPersistence with templates checking persistence.enabled value.
+func ReplacePersistenceMarks(podSpec string) string {
+	podSpec = noPersistenceMarkRegexp.ReplaceAllStringFunc(podSpec, func(mark string) string {
+		match := noPersistenceMarkRegexp.FindStringSubmatch(mark)
+		return fmt.Sprintf(noPersistenceTempl, match[1], match[2])
+	})
+	lines := strings.Split(podSpec, "\n")
+	res := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		field := strings.TrimLeft(line, " ")
+		if field != persistenceMarkKey+":" {
+			res = append(res, line)
+			continue
+		}
+		indent := line[:len(line)-len(field)]
+		res = append(res, indent+"{{- if .Values.persistence.enabled }}", indent+"persistentVolumeClaim:")
+		// nested claim fields are indented deeper than the mark
+		for ; i+1 < len(lines) && strings.HasPrefix(lines[i+1], indent+" "); i++ {
+			res = append(res, lines[i+1])
+		}
+		res = append(res, indent+"{{- else }}", indent+"emptyDir: {}", indent+"{{- end }}")
+	}
+	return strings.Join(res, "\n")
+}
@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestMarkPersistence(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "nginx"},
+		},
+		"volumes": []interface{}{
+			map[string]interface{}{
+				"name": "data",
+				"persistentVolumeClaim": map[string]interface{}{
+					"claimName": `{{ include "chart-name.fullname" . }}-data`,
+					"readOnly":  true,
+				},
+			},
+			map[string]interface{}{
+				"name":                  "external",
+				"persistentVolumeClaim": map[string]interface{}{"claimName": "external"},
+			},
+		},
+	}
+	values := helmify.Values{}
+	err := MarkPersistence(podSpec, []string{"cache"}, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"persistence": map[string]interface{}{"enabled": true}}, values)
+
+	spec, err := yaml.Marshal(podSpec, 6)
+	assert.NoError(t, err)
+	spec = ReplacePersistenceMarks(spec)
+
+	rendered, err := internal.RenderTemplate("chart-name", spec, values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Len(t, res.Volumes, 2)
+	assert.Equal(t, "release-data", res.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.True(t, res.Volumes[0].PersistentVolumeClaim.ReadOnly)
+	assert.Equal(t, "external", res.Volumes[1].PersistentVolumeClaim.ClaimName)
+
+	values["persistence"] = map[string]interface{}{"enabled": false}
+	rendered, err = internal.RenderTemplate("chart-name", spec, values)
+	assert.NoError(t, err)
+	res = corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Len(t, res.Volumes, 3)
+	assert.Equal(t, "data", res.Volumes[0].Name)
+	assert.Nil(t, res.Volumes[0].PersistentVolumeClaim)
+	assert.NotNil(t, res.Volumes[0].EmptyDir)
+	assert.Equal(t, "external", res.Volumes[1].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, "cache", res.Volumes[2].Name)
+	assert.NotNil(t, res.Volumes[2].EmptyDir)
+}
+
+func TestMarkPersistence_noClaims(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"volumes": []interface{}{
+			map[string]interface{}{"name": "tmp", "emptyDir": map[string]interface{}{}},
+		},
+	}
+	values := helmify.Values{}
+	assert.NoError(t, MarkPersistence(podSpec, nil, &values))
+	assert.Empty(t, values)
+}
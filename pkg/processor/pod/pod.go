@@ -0,0 +1,241 @@
+package pod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/cluster"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Template - templated parts of a workload pod template.
+type Template struct {
+	Labels      string
+	Annotations string
+	Spec        string
+}
+
+// Options - workload specific options of pod template processing.
+type Options struct {
+	// RestartAnnotation - adds restartedAt pod annotation from <name>.restartedAt value.
+	RestartAnnotation bool
+	// ClaimTemplates - names of StatefulSet volumeClaimTemplates which are not replaced by emptyDir volumes.
+	ClaimTemplates []string
+}
+
+// ProcessTemplate - templates workload pod template with pod spec content placed at given indent.
+// origSpec is the pod spec from the original object used to keep fields unknown to typed API.
+// Pod values are added under <name>.<container name>.
+func ProcessTemplate(name string, appMeta helmify.AppMetadata, tpl *corev1.PodTemplateSpec, origSpec map[string]interface{}, indent int, opts Options, values *helmify.Values) (Template, error) {
+	res := Template{}
+	var err error
+	if len(tpl.ObjectMeta.Labels) != 0 {
+		res.Labels, err = yamlformat.Marshal(tpl.ObjectMeta.Labels, indent+2)
+		if err != nil {
+			return res, err
+		}
+		res.Labels += "\n"
+	}
+	res.Labels += fmt.Sprintf("%s{{- include \"%s.selectorLabels\" . | nindent %d }}", strings.Repeat(" ", indent), appMeta.ChartName(), indent+2)
+
+	if len(tpl.ObjectMeta.Annotations) != 0 {
+		res.Annotations, err = yamlformat.Marshal(map[string]interface{}{"annotations": tpl.ObjectMeta.Annotations}, indent)
+		if err != nil {
+			return res, err
+		}
+		res.Annotations = "\n" + res.Annotations
+	}
+	nameCamel := strcase.ToLowerCamel(name)
+	if opts.RestartAnnotation {
+		res.Annotations, err = processor.TemplateRestartAnnotation(nameCamel, res.Annotations, indent, values)
+		if err != nil {
+			return res, err
+		}
+	}
+	res.Annotations = processor.AddConfigChecksums(appMeta, tpl.Spec, res.Annotations, indent)
+
+	processor.WarnDuplicatePortNames(name, tpl.Spec)
+	res.Labels, res.Annotations, err = processor.TemplatePodMetadata(nameCamel, res.Labels, res.Annotations, indent, values)
+	if err != nil {
+		return res, err
+	}
+	podValues, err := processPodSpec(nameCamel, appMeta, &tpl.Spec)
+	if err != nil {
+		return res, err
+	}
+	err = values.Merge(podValues)
+	if err != nil {
+		return res, err
+	}
+
+	specMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&tpl.Spec)
+	if err != nil {
+		return res, err
+	}
+	if origSpec != nil {
+		processor.KeepUnknownFields(specMap, origSpec)
+	}
+	topologySpread, err := processor.TemplateTopologySpread(appMeta, nameCamel, specMap, indent, values)
+	if err != nil {
+		return res, err
+	}
+	scheduling, err := processor.TemplateScheduling(appMeta, nameCamel, specMap, indent, values)
+	if err != nil {
+		return res, err
+	}
+	err = processor.TemplateSecurityContext(appMeta, nameCamel, specMap, indent, values)
+	if err != nil {
+		return res, err
+	}
+	err = processor.TemplateProbes(nameCamel, specMap, indent, values)
+	if err != nil {
+		return res, err
+	}
+	err = processor.TemplateLifecycle(nameCamel, specMap, indent, values)
+	if err != nil {
+		return res, err
+	}
+	err = processor.TemplateCommand(appMeta, nameCamel, specMap, indent, values)
+	if err != nil {
+		return res, err
+	}
+	err = processor.TemplatePodFields(nameCamel, specMap, values)
+	if err != nil {
+		return res, err
+	}
+	// replace container resources with template to values.
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(specMap, field)
+		if err != nil {
+			return res, err
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			containerName := strcase.ToLowerCamel((containers[i].(map[string]interface{})["name"]).(string))
+			resources, exists, err := unstructured.NestedMap(*values, nameCamel, containerName, "resources")
+			if err != nil {
+				return res, err
+			}
+			if !exists || len(resources) == 0 {
+				continue
+			}
+			err = unstructured.SetNestedField(containers[i].(map[string]interface{}), fmt.Sprintf(`{{- toYaml .Values.%s.%s.resources | nindent %d }}`, nameCamel, containerName, indent+4), "resources")
+			if err != nil {
+				return res, err
+			}
+		}
+		err = unstructured.SetNestedSlice(specMap, containers, field)
+		if err != nil {
+			return res, err
+		}
+	}
+	err = processor.MarkPersistence(specMap, opts.ClaimTemplates, values)
+	if err != nil {
+		return res, err
+	}
+	err = processor.MarkExtraHooks(nameCamel, specMap, values)
+	if err != nil {
+		return res, err
+	}
+	res.Spec, err = yamlformat.Marshal(specMap, indent)
+	if err != nil {
+		return res, err
+	}
+	res.Spec = processor.UnquoteTemplates(res.Spec)
+	res.Spec = processor.ReplaceExtraMarks(res.Spec)
+	res.Spec = processor.ReplacePersistenceMarks(res.Spec)
+	res.Spec += topologySpread
+	res.Spec += scheduling
+	return res, nil
+}
+
+func processPodSpec(name string, appMeta helmify.AppMetadata, pod *corev1.PodSpec) (helmify.Values, error) {
+	values := helmify.Values{}
+	for i, c := range pod.Containers {
+		processed, err := processPodContainer(name, appMeta, c, &values)
+		if err != nil {
+			return nil, err
+		}
+		pod.Containers[i] = processed
+	}
+	for i, c := range pod.InitContainers {
+		processed, err := processPodContainer(name, appMeta, c, &values)
+		if err != nil {
+			return nil, err
+		}
+		pod.InitContainers[i] = processed
+	}
+	for _, v := range pod.Volumes {
+		if v.ConfigMap != nil {
+			v.ConfigMap.Name = appMeta.TemplatedName(v.ConfigMap.Name)
+		}
+		if v.Secret != nil {
+			v.Secret.SecretName = appMeta.TemplatedSecretName(v.Secret.SecretName)
+		}
+		if v.PersistentVolumeClaim != nil {
+			v.PersistentVolumeClaim.ClaimName = appMeta.TemplatedName(v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	pod.ServiceAccountName = appMeta.TemplatedServiceAccountName(pod.ServiceAccountName)
+
+	for i, s := range pod.ImagePullSecrets {
+		pod.ImagePullSecrets[i].Name = appMeta.TemplatedSecretName(s.Name)
+	}
+
+	return values, nil
+}
+
+func processPodContainer(name string, appMeta helmify.AppMetadata, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
+	containerName := strcase.ToLowerCamel(c.Name)
+	var err error
+	c.Image, err = processor.TemplateImage(appMeta, name, containerName, c.Image, values)
+	if err != nil {
+		return c, err
+	}
+	for _, e := range c.Env {
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+			e.ValueFrom.SecretKeyRef.Name = appMeta.TemplatedSecretName(e.ValueFrom.SecretKeyRef.Name)
+		}
+		if e.ValueFrom != nil && e.ValueFrom.ConfigMapKeyRef != nil {
+			e.ValueFrom.ConfigMapKeyRef.Name = appMeta.TemplatedName(e.ValueFrom.ConfigMapKeyRef.Name)
+		}
+	}
+	for _, e := range c.EnvFrom {
+		if e.SecretRef != nil {
+			e.SecretRef.Name = appMeta.TemplatedSecretName(e.SecretRef.Name)
+		}
+		if e.ConfigMapRef != nil {
+			e.ConfigMapRef.Name = appMeta.TemplatedName(e.ConfigMapRef.Name)
+		}
+	}
+	c.Env = append(c.Env, corev1.EnvVar{
+		Name:  cluster.DomainEnv,
+		Value: fmt.Sprintf("{{ .Values.%s }}", cluster.DomainKey),
+	})
+	err = processor.TemplateEnv(appMeta, name, containerName, &c, values)
+	if err != nil {
+		return c, err
+	}
+	for k, v := range c.Resources.Requests {
+		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "requests", k.String())
+		if err != nil {
+			return c, errors.Wrap(err, "unable to set container resources value")
+		}
+	}
+	for k, v := range c.Resources.Limits {
+		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "limits", k.String())
+		if err != nil {
+			return c, errors.Wrap(err, "unable to set container resources value")
+		}
+	}
+	return c, nil
+}
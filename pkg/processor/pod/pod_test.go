@@ -0,0 +1,98 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+const strPodTemplate = `metadata:
+  labels:
+    app: web
+spec:
+  containers:
+  - name: web
+    image: nginx:1.21
+    args:
+    - sh
+    - -c
+    - echo 'hello world'
+    env:
+    - name: PATTERN
+      value: "*"
+    - name: MESSAGE
+      value: "it's"
+    resources:
+      limits:
+        memory: 64Mi`
+
+func processTemplate(t *testing.T, conf config.Config, opts Options) (Template, helmify.Values) {
+	podTpl := corev1.PodTemplateSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(strPodTemplate), &podTpl))
+	origSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podTpl.Spec)
+	assert.NoError(t, err)
+	conf.ChartName = "chart-name"
+	values := helmify.Values{}
+	res, err := ProcessTemplate("web", metadata.New(conf), &podTpl, origSpec, 2, opts, &values)
+	assert.NoError(t, err)
+	values["kubernetesClusterDomain"] = "cluster.local"
+	return res, values
+}
+
+func render(t *testing.T, tpl Template, values helmify.Values) corev1.PodTemplateSpec {
+	rendered, err := internal.RenderTemplate("chart-name", "metadata:\n  labels:\n"+tpl.Labels+tpl.Annotations+"\nspec:\n"+tpl.Spec, values)
+	assert.NoError(t, err)
+	res := corev1.PodTemplateSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	return res
+}
+
+func TestProcessTemplate(t *testing.T) {
+	tpl, values := processTemplate(t, config.Config{}, Options{})
+	web := values["web"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"registry": "", "repository": "nginx", "tag": "1.21", "digest": ""}, web["web"].(map[string]interface{})["image"])
+	assert.NotContains(t, web, "restartedAt")
+	assert.NotContains(t, values, "persistence")
+
+	res := render(t, tpl, values)
+	assert.Equal(t, "web", res.Labels["app"])
+	assert.Equal(t, "release", res.Labels["app.kubernetes.io/instance"])
+	container := res.Spec.Containers[0]
+	assert.Equal(t, "nginx:1.21", container.Image)
+	assert.Equal(t, []string{"sh", "-c", "echo 'hello world'"}, container.Args)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "PATTERN", Value: "*"},
+		{Name: "MESSAGE", Value: "it's"},
+		{Name: "KUBERNETES_CLUSTER_DOMAIN", Value: "cluster.local"},
+	}, container.Env)
+	assert.Equal(t, "64Mi", container.Resources.Limits.Memory().String())
+}
+
+func TestProcessTemplate_restartAnnotation(t *testing.T) {
+	tpl, values := processTemplate(t, config.Config{}, Options{RestartAnnotation: true})
+	restartedAt, ok, err := unstructured.NestedString(values, "web", "restartedAt")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "", restartedAt)
+	assert.NotContains(t, render(t, tpl, values).Annotations, "kubectl.kubernetes.io/restartedAt")
+
+	assert.NoError(t, unstructured.SetNestedField(values, "now", "web", "restartedAt"))
+	assert.Equal(t, "now", render(t, tpl, values).Annotations["kubectl.kubernetes.io/restartedAt"])
+}
+
+func TestProcessTemplate_claimTemplates(t *testing.T) {
+	tpl, values := processTemplate(t, config.Config{}, Options{ClaimTemplates: []string{"data"}})
+	assert.Equal(t, map[string]interface{}{"enabled": true}, values["persistence"])
+	assert.Empty(t, render(t, tpl, values).Spec.Volumes)
+
+	values["persistence"] = map[string]interface{}{"enabled": false}
+	assert.Equal(t, []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}, render(t, tpl, values).Spec.Volumes)
+}
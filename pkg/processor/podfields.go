@@ -0,0 +1,23 @@
+package processor
+
+import "github.com/arttor/helmify/pkg/helmify"
+
+// podFields - pod spec fields often tuned per cluster.
+var podFields = []string{"terminationGracePeriodSeconds", "dnsPolicy", "hostNetwork"}
+
+// TemplatePodFields - moves pod terminationGracePeriodSeconds, dnsPolicy and hostNetwork to <name>.<field> values
+// if set in the pod spec.
+func TemplatePodFields(name string, podSpec map[string]interface{}, values *helmify.Values) error {
+	for _, field := range podFields {
+		value, ok := podSpec[field]
+		if !ok {
+			continue
+		}
+		templated, err := values.Add(value, name, field)
+		if err != nil {
+			return err
+		}
+		podSpec[field] = templated
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplatePodFields(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"terminationGracePeriodSeconds": int64(10),
+		"dnsPolicy":                     "ClusterFirstWithHostNet",
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "nginx"},
+		},
+	}
+	values := helmify.Values{}
+	err := TemplatePodFields("web", podSpec, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"web": map[string]interface{}{
+		"terminationGracePeriodSeconds": int64(10),
+		"dnsPolicy":                     "ClusterFirstWithHostNet",
+	}}, values)
+
+	assert.NoError(t, unstructured.SetNestedField(values, int64(60), "web", "terminationGracePeriodSeconds"))
+	spec, err := yaml.Marshal(podSpec, 0)
+	assert.NoError(t, err)
+	rendered, err := internal.RenderTemplate("chart-name", strings.ReplaceAll(spec, "'", ""), values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, int64(60), *res.TerminationGracePeriodSeconds)
+	assert.Equal(t, corev1.DNSClusterFirstWithHostNet, res.DNSPolicy)
+	assert.False(t, res.HostNetwork)
+}
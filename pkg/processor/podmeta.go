@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// podMetadataTempl - appends map entries from <name>.<field> value. %[1]s - indent, %[2]s - name, %[3]s - field, %[4]d - nindent.
+const podMetadataTempl = `
+%[1]s{{- with .Values.%[2]s.%[3]s }}
+%[1]s{{- toYaml . | nindent %[4]d }}
+%[1]s{{- end }}`
+
+// podAnnotationsTempl - adds annotations from <name>.podAnnotations value if pod has no other annotations.
+// %[1]s - indent, %[2]s - name, %[3]d - nindent.
+const podAnnotationsTempl = `
+%[1]s{{- with .Values.%[2]s.podAnnotations }}
+%[1]sannotations:
+%[1]s  {{- toYaml . | nindent %[3]d }}
+%[1]s{{- end }}`
+
+// restartAnnotationTempl - adds restartedAt pod annotation from <name>.restartedAt value. %[1]s - indent, %[2]s - name.
+const restartAnnotationTempl = `
+%[1]s  {{- with .Values.%[2]s.restartedAt }}
+%[1]s  kubectl.kubernetes.io/restartedAt: {{ . | quote }}
+%[1]s  {{- end }}`
+
+// TemplatePodMetadata - appends <name>.podLabels and <name>.podAnnotations values to pod template labels and annotations.
+// Pod template metadata fields are expected at given indent. podLabels must end with the last label line and
+// podAnnotations is either empty or starts with a newline followed by 'annotations:' key.
+func TemplatePodMetadata(name, podLabels, podAnnotations string, indent int, values *helmify.Values) (string, string, error) {
+	for _, field := range []string{"podLabels", "podAnnotations"} {
+		_, err := values.Add(map[string]interface{}{}, name, field)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	spaces := strings.Repeat(" ", indent)
+	podLabels += fmt.Sprintf(podMetadataTempl, spaces, name, "podLabels", indent+2)
+	if podAnnotations == "" {
+		return podLabels, fmt.Sprintf(podAnnotationsTempl, spaces, name, indent+2), nil
+	}
+	podAnnotations += fmt.Sprintf(podMetadataTempl, spaces+"  ", name, "podAnnotations", indent+2)
+	return podLabels, podAnnotations, nil
+}
+
+// TemplateRestartAnnotation - adds pod annotation set from <name>.restartedAt value to force rollout on helm upgrade.
+// Pod template metadata fields are expected at given indent. podAnnotations is either empty or starts with a newline
+// followed by 'annotations:' key.
+func TemplateRestartAnnotation(name, podAnnotations string, indent int, values *helmify.Values) (string, error) {
+	err := unstructured.SetNestedField(*values, "", name, "restartedAt")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to set restartedAt value")
+	}
+	spaces := strings.Repeat(" ", indent)
+	if podAnnotations == "" {
+		podAnnotations = "\n" + spaces + "annotations:"
+	}
+	return podAnnotations + fmt.Sprintf(restartAnnotationTempl, spaces, name), nil
+}
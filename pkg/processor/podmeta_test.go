@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplatePodMetadata(t *testing.T) {
+	render := func(t *testing.T, podLabels, podAnnotations string, values helmify.Values) corev1.PodTemplateSpec {
+		rendered, err := internal.RenderTemplate("chart-name", "metadata:\n  labels:\n"+podLabels+podAnnotations, values)
+		assert.NoError(t, err)
+		res := corev1.PodTemplateSpec{}
+		assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+		return res
+	}
+	t.Run("no annotations", func(t *testing.T) {
+		values := helmify.Values{}
+		podLabels, podAnnotations, err := TemplatePodMetadata("web", "    app: web", "", 2, &values)
+		assert.NoError(t, err)
+		assert.Equal(t, helmify.Values{"web": map[string]interface{}{
+			"podLabels":      map[string]interface{}{},
+			"podAnnotations": map[string]interface{}{},
+		}}, values)
+
+		res := render(t, podLabels, podAnnotations, values)
+		assert.Equal(t, map[string]string{"app": "web"}, res.Labels)
+		assert.Nil(t, res.Annotations)
+
+		web := values["web"].(map[string]interface{})
+		web["podLabels"] = map[string]interface{}{"team": "a"}
+		web["podAnnotations"] = map[string]interface{}{"linkerd.io/inject": "enabled"}
+		res = render(t, podLabels, podAnnotations, values)
+		assert.Equal(t, map[string]string{"app": "web", "team": "a"}, res.Labels)
+		assert.Equal(t, map[string]string{"linkerd.io/inject": "enabled"}, res.Annotations)
+	})
+	t.Run("append to annotations", func(t *testing.T) {
+		values := helmify.Values{}
+		podLabels, podAnnotations, err := TemplatePodMetadata("web", "    app: web", "\n  annotations:\n    a: b", 2, &values)
+		assert.NoError(t, err)
+		values["web"].(map[string]interface{})["podAnnotations"] = map[string]interface{}{"c": "d"}
+		res := render(t, podLabels, podAnnotations, values)
+		assert.Equal(t, map[string]string{"a": "b", "c": "d"}, res.Annotations)
+	})
+}
+
+func TestTemplateRestartAnnotation(t *testing.T) {
+	values := helmify.Values{}
+	podAnnotations, err := TemplateRestartAnnotation("web", "\n  annotations:\n    a: b", 2, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"web": map[string]interface{}{"restartedAt": ""}}, values)
+
+	values["web"].(map[string]interface{})["restartedAt"] = "now"
+	rendered, err := internal.RenderTemplate("chart-name", "metadata:"+podAnnotations, values)
+	assert.NoError(t, err)
+	res := corev1.PodTemplateSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, map[string]string{"a": "b", "kubectl.kubernetes.io/restartedAt": "now"}, res.Annotations)
+}
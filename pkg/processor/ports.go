@@ -0,0 +1,25 @@
+package processor
+
+import (
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WarnDuplicatePortNames - logs a warning for every container port name used more than once within a container.
+// Kubernetes rejects such pod specs, so the resulting chart would not install.
+func WarnDuplicatePortNames(objName string, podSpec corev1.PodSpec) {
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, c := range containers {
+		seen := map[string]bool{}
+		for _, p := range c.Ports {
+			if p.Name == "" {
+				continue
+			}
+			if seen[p.Name] {
+				logrus.Warnf("%s: container %s has duplicate port name %s", objName, c.Name, p.Name)
+				continue
+			}
+			seen[p.Name] = true
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWarnDuplicatePortNames(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "app", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 80}, {Name: "http", ContainerPort: 8080}, {ContainerPort: 9090}}},
+		{Name: "sidecar", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 80}, {ContainerPort: 9090}}},
+	}}
+	WarnDuplicatePortNames("my-app", podSpec)
+	assert.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "my-app: container app has duplicate port name http", hook.LastEntry().Message)
+}
@@ -0,0 +1,18 @@
+package processor
+
+import "github.com/arttor/helmify/pkg/helmify"
+
+// probes - container probes moved to values.
+var probes = []string{"livenessProbe", "readinessProbe", "startupProbe"}
+
+// TemplateProbes - moves containers probes to <name>.<container name>.<probe> values.
+// Pod spec is expected to be marshaled with given indent.
+func TemplateProbes(name string, podSpec map[string]interface{}, indent int, values *helmify.Values) error {
+	for _, probe := range probes {
+		err := templateContainersField(name, probe, "", podSpec, indent, values)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplateProbes(t *testing.T) {
+	probe := map[string]interface{}{
+		"httpGet":          map[string]interface{}{"path": "/healthz", "port": "http"},
+		"timeoutSeconds":   int64(1),
+		"failureThreshold": int64(3),
+	}
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":           "app",
+				"image":          "nginx",
+				"livenessProbe":  probe,
+				"readinessProbe": probe,
+			},
+		},
+	}
+	values := helmify.Values{}
+	err := TemplateProbes("web", podSpec, 0, &values)
+	assert.NoError(t, err)
+	app := values["web"].(map[string]interface{})["app"].(map[string]interface{})
+	assert.Equal(t, probe, app["livenessProbe"])
+	assert.Equal(t, probe, app["readinessProbe"])
+	assert.NotContains(t, app, "startupProbe")
+
+	assert.NoError(t, unstructured.SetNestedField(values, int64(5), "web", "app", "livenessProbe", "timeoutSeconds"))
+	spec, err := yaml.Marshal(podSpec, 0)
+	assert.NoError(t, err)
+	rendered, err := internal.RenderTemplate("chart-name", strings.ReplaceAll(spec, "'", ""), values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, int32(5), res.Containers[0].LivenessProbe.TimeoutSeconds)
+	assert.Equal(t, int32(1), res.Containers[0].ReadinessProbe.TimeoutSeconds)
+	assert.Equal(t, "/healthz", res.Containers[0].ReadinessProbe.HTTPGet.Path)
+	assert.Nil(t, res.Containers[0].StartupProbe)
+}
@@ -0,0 +1,104 @@
+package quota
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// quotaSpecTempl - spec with a field read from values. %[1]s - field, %[2]s - values name, %[3]s - other spec fields.
+const quotaSpecTempl = `
+spec:
+  %[1]s:
+    {{- toYaml .Values.quotas.%[2]s.%[1]s | nindent 4 }}%[3]s`
+
+var resourceQuotaGVC = schema.GroupVersionKind{
+	Group:   "",
+	Version: "v1",
+	Kind:    "ResourceQuota",
+}
+
+var limitRangeGVC = schema.GroupVersionKind{
+	Group:   "",
+	Version: "v1",
+	Kind:    "LimitRange",
+}
+
+// ResourceQuota creates processor for k8s ResourceQuota resource.
+func ResourceQuota() helmify.Processor {
+	return &quota{gvk: resourceQuotaGVC, field: "hard"}
+}
+
+// LimitRange creates processor for k8s LimitRange resource.
+func LimitRange() helmify.Processor {
+	return &quota{gvk: limitRangeGVC, field: "limits"}
+}
+
+// quota - processor moving given spec field to quotas.<name>.<field> value.
+type quota struct {
+	gvk   schema.GroupVersionKind
+	field string
+}
+
+// Process k8s ResourceQuota or LimitRange object into template. Returns false if not capable of processing given resource type.
+func (q quota) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != q.gvk {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to get %s spec", obj.GetKind())
+	}
+	values := helmify.Values{}
+	err = unstructured.SetNestedField(values, spec[q.field], "quotas", nameCamel, q.field)
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to set %s value", q.field)
+	}
+	delete(spec, q.field)
+	other := ""
+	if len(spec) != 0 {
+		other, err = yamlformat.Marshal(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		other = "\n" + other
+	}
+	return true, &result{
+		name:   name,
+		data:   []byte(meta + fmt.Sprintf(quotaSpecTempl, q.field, nameCamel, other)),
+		values: values,
+	}, nil
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
@@ -0,0 +1,97 @@
+package quota
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	strResourceQuota = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: my-app-quota
+  namespace: my-app
+spec:
+  hard:
+    requests.cpu: "4"
+    requests.memory: 8Gi
+    pods: "20"
+  scopes:
+  - NotBestEffort`
+	strLimitRange = `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: my-app-limits
+  namespace: my-app
+spec:
+  limits:
+  - type: Container
+    default:
+      cpu: 500m
+      memory: 512Mi
+    defaultRequest:
+      cpu: 100m`
+)
+
+func Test_quota_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := ResourceQuota().Process(&metadata.Service{}, internal.GenerateObj(strResourceQuota))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+		processed, _, err = LimitRange().Process(&metadata.Service{}, internal.GenerateObj(strLimitRange))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := ResourceQuota().Process(&metadata.Service{}, internal.GenerateObj(strLimitRange))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+		processed, _, err = LimitRange().Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_quota_Process_values(t *testing.T) {
+	quotaObj, limitsObj := internal.GenerateObj(strResourceQuota), internal.GenerateObj(strLimitRange)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(quotaObj)
+	testMeta.Load(limitsObj)
+
+	_, tpl, err := ResourceQuota().Process(testMeta, quotaObj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"quotas": map[string]interface{}{"quota": map[string]interface{}{
+		"hard": map[string]interface{}{"requests.cpu": "4", "requests.memory": "8Gi", "pods": "20"},
+	}}}, tpl.Values())
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values := tpl.Values()
+	values["quotas"].(map[string]interface{})["quota"].(map[string]interface{})["hard"] = map[string]interface{}{"pods": "50"}
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	rq := corev1.ResourceQuota{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &rq))
+	assert.Equal(t, corev1.ResourceList{"pods": resource.MustParse("50")}, rq.Spec.Hard)
+	assert.Equal(t, []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeNotBestEffort}, rq.Spec.Scopes)
+
+	_, tpl, err = LimitRange().Process(testMeta, limitsObj)
+	assert.NoError(t, err)
+	buf = bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	lr := corev1.LimitRange{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &lr))
+	assert.Equal(t, "release-limits", lr.Name)
+	assert.Equal(t, resource.MustParse("512Mi"), lr.Spec.Limits[0].Default["memory"])
+	assert.Equal(t, resource.MustParse("100m"), lr.Spec.Limits[0].DefaultRequest["cpu"])
+}
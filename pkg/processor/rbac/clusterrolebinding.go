@@ -17,9 +17,11 @@ import (
 )
 
 var clusterRoleBindingTempl, _ = template.New("clusterRoleBinding").Parse(
-	`{{ .Meta }}
+	`{{ "{{- if .Values.rbac.create }}" }}
+{{ .Meta }}
 {{ .RoleRef }}
-{{ .Subjects }}`)
+{{ .Subjects }}
+{{ "{{- end }}" }}`)
 
 var clusterRoleBindingGVC = schema.GroupVersionKind{
 	Group:   "rbac.authorization.k8s.io",
@@ -59,8 +61,12 @@ func (r clusterRoleBinding) Process(appMeta helmify.AppMetadata, obj *unstructur
 	}
 
 	for i, s := range rb.Subjects {
-		s.Namespace = "{{ .Release.Namespace }}"
-		s.Name = appMeta.TemplatedName(s.Name)
+		s.Namespace = appMeta.TemplatedNamespace(s.Namespace)
+		if s.Kind == "ServiceAccount" {
+			s.Name = appMeta.TemplatedServiceAccountName(s.Name)
+		} else {
+			s.Name = appMeta.TemplatedName(s.Name)
+		}
 		rb.Subjects[i] = s
 	}
 	subjects, err := yamlformat.Marshal(map[string]interface{}{"subjects": &rb.Subjects}, 0)
@@ -96,7 +102,7 @@ func (r *crbResult) Filename() string {
 }
 
 func (r *crbResult) Values() helmify.Values {
-	return helmify.Values{}
+	return rbacValues()
 }
 
 func (r *crbResult) Write(writer io.Writer) error {
@@ -14,8 +14,10 @@ import (
 )
 
 var roleTempl, _ = template.New("clusterRole").Parse(
-	`{{ .Meta }}
-{{ .Rules }}`)
+	`{{ "{{- if .Values.rbac.create }}" }}
+{{ .Meta }}
+{{ .Rules }}
+{{ "{{- end }}" }}`)
 
 var clusterRoleGVC = schema.GroupVersionKind{
 	Group:   "rbac.authorization.k8s.io",
@@ -28,6 +30,11 @@ var roleGVC = schema.GroupVersionKind{
 	Kind:    "Role",
 }
 
+// rbacValues - values for all RBAC templates. RBAC objects are created only if rbac.create value is set.
+func rbacValues() helmify.Values {
+	return helmify.Values{"rbac": map[string]interface{}{"create": true}}
+}
+
 // Role creates processor for k8s Role and ClusterRole resources.
 func Role() helmify.Processor {
 	return &role{}
@@ -77,7 +84,7 @@ func (r *crResult) GVK() schema.GroupVersionKind {
 }
 
 func (r *crResult) Values() helmify.Values {
-	return helmify.Values{}
+	return rbacValues()
 }
 
 func (r *crResult) Write(writer io.Writer) error {
@@ -17,9 +17,11 @@ import (
 )
 
 var roleBindingTempl, _ = template.New("roleBinding").Parse(
-	`{{- .Meta }}
+	`{{ "{{- if .Values.rbac.create }}" }}
+{{ .Meta }}
 {{ .RoleRef }}
-{{ .Subjects }}`)
+{{ .Subjects }}
+{{ "{{- end }}" }}`)
 
 var roleBindingGVC = schema.GroupVersionKind{
 	Group:   "rbac.authorization.k8s.io",
@@ -57,8 +59,12 @@ func (r roleBinding) Process(appMeta helmify.AppMetadata, obj *unstructured.Unst
 	}
 
 	for i, s := range rb.Subjects {
-		s.Namespace = "{{ .Release.Namespace }}"
-		s.Name = appMeta.TemplatedName(s.Name)
+		s.Namespace = appMeta.TemplatedNamespace(s.Namespace)
+		if s.Kind == "ServiceAccount" {
+			s.Name = appMeta.TemplatedServiceAccountName(s.Name)
+		} else {
+			s.Name = appMeta.TemplatedName(s.Name)
+		}
 		rb.Subjects[i] = s
 	}
 	subjects, err := yamlformat.Marshal(map[string]interface{}{"subjects": &rb.Subjects}, 0)
@@ -94,7 +100,7 @@ func (r *rbResult) Filename() string {
 }
 
 func (r *rbResult) Values() helmify.Values {
-	return helmify.Values{}
+	return rbacValues()
 }
 
 func (r *rbResult) Write(writer io.Writer) error {
@@ -1,9 +1,15 @@
 package rbac
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -39,3 +45,64 @@ func Test_roleBinding_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_roleBinding_Process_rbacCreate(t *testing.T) {
+	var testInstance roleBinding
+	obj := internal.GenerateObj(roleBindingYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: my-operator-leader-election-role
+  namespace: my-operator-system`))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-operator-controller-manager
+  namespace: my-operator-system`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"rbac": map[string]interface{}{"create": true}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values := helmify.Values{"serviceAccount": map[string]interface{}{"create": true}}
+	assert.NoError(t, values.Merge(tpl.Values()))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := rbacv1.RoleBinding{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "release-leader-election-role", res.RoleRef.Name)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "release", Namespace: "ns"}}, res.Subjects)
+
+	values["rbac"] = map[string]interface{}{"create": false}
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_roleBinding_Process_namespaceMode(t *testing.T) {
+	var testInstance roleBinding
+	obj := internal.GenerateObj(roleBindingYaml + `
+- kind: ServiceAccount
+  name: prometheus
+  namespace: monitoring`)
+	for mode, expected := range map[string][]string{
+		config.NamespaceDrop:    {"ns", "monitoring"},
+		config.NamespaceKeep:    {"my-operator-system", "monitoring"},
+		config.NamespaceRelease: {"ns", "ns"},
+	} {
+		testMeta := metadata.New(config.Config{ChartName: "chart-name", NamespaceMode: mode})
+		testMeta.Load(obj)
+		_, tpl, err := testInstance.Process(testMeta, obj)
+		assert.NoError(t, err)
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), helmify.Values{"rbac": map[string]interface{}{"create": true}})
+		assert.NoError(t, err)
+		res := rbacv1.RoleBinding{}
+		assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+		assert.Equal(t, expected, []string{res.Subjects[0].Namespace, res.Subjects[1].Namespace}, mode)
+	}
+}
@@ -1,7 +1,9 @@
 package rbac
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/processor"
@@ -9,6 +11,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// saTempl - 'helm create' style ServiceAccount template. %[1]s - metadata.
+const saTempl = `{{- if .Values.serviceAccount.create }}
+%[1]s
+  {{- with .Values.serviceAccount.annotations }}
+  annotations:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+{{- end }}`
+
 var serviceAccountGVC = schema.GroupVersionKind{
 	Group:   "",
 	Version: "v1",
@@ -23,29 +34,55 @@ func ServiceAccount() helmify.Processor {
 type serviceAccount struct{}
 
 // Process k8s ServiceAccount object into helm template. Returns false if not capable of processing given resource type.
+// The only chart ServiceAccount is created if serviceAccount.create value is set like in 'helm create' chart.
 func (sa serviceAccount) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
 	if obj.GroupVersionKind() != serviceAccountGVC {
 		return false, nil, nil
 	}
+	name := obj.GetName()
+	templatedName, saName := appMeta.TemplatedName(name), appMeta.TemplatedServiceAccountName(name)
+	if templatedName == saName {
+		meta, err := processor.ProcessObjMeta(appMeta, obj)
+		if err != nil {
+			return true, nil, err
+		}
+		return true, &saResult{
+			data:   []byte(meta),
+			values: helmify.Values{},
+		}, nil
+	}
+	annotations := map[string]interface{}{}
+	for k, v := range obj.GetAnnotations() {
+		annotations[k] = v
+	}
+	// annotations are moved to values
+	obj.SetAnnotations(nil)
 	meta, err := processor.ProcessObjMeta(appMeta, obj)
 	if err != nil {
 		return true, nil, err
 	}
+	meta = strings.Replace(meta, "name: "+templatedName, "name: "+saName, 1)
 	return true, &saResult{
-		data: []byte(meta),
+		data: []byte(fmt.Sprintf(saTempl, meta)),
+		values: helmify.Values{"serviceAccount": map[string]interface{}{
+			"create":      true,
+			"annotations": annotations,
+			"name":        "",
+		}},
 	}, nil
 }
 
 type saResult struct {
-	data []byte
+	data   []byte
+	values helmify.Values
 }
 
 func (r *saResult) Filename() string {
-	return "deployment.yaml"
+	return "serviceaccount.yaml"
 }
 
 func (r *saResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *saResult) Write(writer io.Writer) error {
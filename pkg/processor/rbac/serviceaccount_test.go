@@ -1,9 +1,15 @@
 package rbac
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -31,3 +37,58 @@ func Test_serviceAccount_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_serviceAccount_Process_values(t *testing.T) {
+	var testInstance serviceAccount
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-operator-controller-manager
+  namespace: my-operator-system
+  annotations:
+    eks.amazonaws.com/role-arn: arn:aws:iam::111122223333:role/my-role`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"serviceAccount": map[string]interface{}{
+		"create":      true,
+		"annotations": map[string]interface{}{"eks.amazonaws.com/role-arn": "arn:aws:iam::111122223333:role/my-role"},
+		"name":        "",
+	}}, tpl.Values())
+	assert.Equal(t, "serviceaccount.yaml", tpl.Filename())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.ServiceAccount{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "release", res.Name)
+	assert.Equal(t, "arn:aws:iam::111122223333:role/my-role", res.Annotations["eks.amazonaws.com/role-arn"])
+
+	values := tpl.Values()
+	values["serviceAccount"].(map[string]interface{})["create"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_serviceAccount_Process_multiple(t *testing.T) {
+	var testInstance serviceAccount
+	obj := internal.GenerateObj(serviceAccountYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-operator-webhook
+  namespace: my-operator-system`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Empty(t, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), `name: {{ include "chart-name.fullname" . }}-controller-manager`)
+}
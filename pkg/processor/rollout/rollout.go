@@ -0,0 +1,226 @@
+package rollout
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	"github.com/arttor/helmify/pkg/processor/pod"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var rolloutGVK = schema.GroupVersionKind{
+	Group:   "argoproj.io",
+	Version: "v1alpha1",
+	Kind:    "Rollout",
+}
+
+var rolloutTempl, _ = template.New("rollout").Parse(
+	`{{- .Meta }}
+spec:
+{{- if .Spec }}
+{{ .Spec }}
+{{- end }}
+  selector:
+{{ .Selector }}
+{{- if .PodSpec }}
+  template:
+    metadata:
+      labels:
+{{ .PodLabels }}
+{{- .PodAnnotations }}
+    spec:
+{{ .PodSpec }}
+{{- end }}`)
+
+const selectorTempl = `%[1]s
+{{- include "%[2]s.selectorLabels" . | nindent 6 }}
+%[3]s`
+
+// canaryStepsPlaceholder - replaced with canary steps read from values.
+const canaryStepsPlaceholder = "helmify-canary-steps"
+
+// blueGreenFields - blueGreen strategy fields moved to values.
+var blueGreenFields = []string{"autoPromotionEnabled", "autoPromotionSeconds", "scaleDownDelaySeconds", "previewReplicaCount"}
+
+// New creates processor for Argo Rollouts Rollout resource.
+func New() helmify.Processor {
+	return &rollout{}
+}
+
+type rollout struct{}
+
+// Process Rollout object into template. Returns false if not capable of processing given resource type.
+// Pod template is processed like Deployment one. Canary steps and blueGreen options are moved to <name>.rollout.* values.
+func (r rollout) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != rolloutGVK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	values := helmify.Values{}
+
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get rollout spec")
+	}
+	selectorMap, _ := spec["selector"].(map[string]interface{})
+	podTemplateMap, hasTemplate := spec["template"].(map[string]interface{})
+	delete(spec, "selector")
+	delete(spec, "template")
+
+	if replicas, ok := spec["replicas"]; ok {
+		spec["replicas"], err = values.Add(replicas, nameCamel, "replicas")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if ref, ok, _ := unstructured.NestedString(spec, "workloadRef", "name"); ok {
+		err = unstructured.SetNestedField(spec, appMeta.TemplatedName(ref), "workloadRef", "name")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	err = processStrategy(appMeta, nameCamel, spec, &values)
+	if err != nil {
+		return true, nil, err
+	}
+	specStr := ""
+	if len(spec) != 0 {
+		specStr, err = yamlformat.Marshal(spec, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		specStr = processor.UnquoteTemplates(specStr)
+		specStr = strings.ReplaceAll(specStr, " "+canaryStepsPlaceholder,
+			fmt.Sprintf("\n      {{- toYaml .Values.%s.rollout.canary.steps | nindent 6 }}", nameCamel))
+	}
+
+	selector := metav1.LabelSelector{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, &selector)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to cast rollout selector")
+	}
+	selectorStr, err := processSelector(appMeta, selector)
+	if err != nil {
+		return true, nil, err
+	}
+
+	res := &result{values: values}
+	res.data.Meta, res.data.Spec, res.data.Selector = meta, specStr, selectorStr
+	if hasTemplate {
+		podTpl := corev1.PodTemplateSpec{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(podTemplateMap, &podTpl)
+		if err != nil {
+			return true, nil, errors.Wrap(err, "unable to cast rollout pod template")
+		}
+		origPodSpec, _, _ := unstructured.NestedMap(podTemplateMap, "spec")
+		processed, err := pod.ProcessTemplate(name, appMeta, &podTpl, origPodSpec, 6, pod.Options{}, &res.values)
+		if err != nil {
+			return true, nil, err
+		}
+		res.data.PodLabels, res.data.PodAnnotations, res.data.PodSpec = processed.Labels, processed.Annotations, processed.Spec
+	}
+	return true, res, nil
+}
+
+// processSelector - returns rollout selector extended with chart selector labels.
+func processSelector(appMeta helmify.AppMetadata, selector metav1.LabelSelector) (string, error) {
+	matchLabels, err := yamlformat.Marshal(map[string]interface{}{"matchLabels": selector.MatchLabels}, 0)
+	if err != nil {
+		return "", err
+	}
+	matchExpr := ""
+	if selector.MatchExpressions != nil {
+		matchExpr, err = yamlformat.Marshal(map[string]interface{}{"matchExpressions": selector.MatchExpressions}, 0)
+		if err != nil {
+			return "", err
+		}
+	}
+	res := fmt.Sprintf(selectorTempl, matchLabels, appMeta.ChartName(), matchExpr)
+	res = strings.Trim(res, " \n")
+	return string(yamlformat.Indent([]byte(res), 4)), nil
+}
+
+// processStrategy - templates strategy services and moves canary steps and blueGreen options to values.
+func processStrategy(appMeta helmify.AppMetadata, nameCamel string, spec map[string]interface{}, values *helmify.Values) error {
+	if canary, ok, _ := unstructured.NestedMap(spec, "strategy", "canary"); ok {
+		templateServices(appMeta, canary, "canaryService", "stableService")
+		if steps, ok := canary["steps"].([]interface{}); ok {
+			err := unstructured.SetNestedSlice(*values, steps, nameCamel, "rollout", "canary", "steps")
+			if err != nil {
+				return err
+			}
+			canary["steps"] = canaryStepsPlaceholder
+		}
+		err := unstructured.SetNestedMap(spec, canary, "strategy", "canary")
+		if err != nil {
+			return err
+		}
+	}
+	if blueGreen, ok, _ := unstructured.NestedMap(spec, "strategy", "blueGreen"); ok {
+		templateServices(appMeta, blueGreen, "activeService", "previewService")
+		for _, field := range blueGreenFields {
+			value, ok := blueGreen[field]
+			if !ok {
+				continue
+			}
+			tpl, err := values.Add(value, nameCamel, "rollout", "blueGreen", field)
+			if err != nil {
+				return err
+			}
+			blueGreen[field] = tpl
+		}
+		err := unstructured.SetNestedMap(spec, blueGreen, "strategy", "blueGreen")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func templateServices(appMeta helmify.AppMetadata, strategy map[string]interface{}, fields ...string) {
+	for _, field := range fields {
+		if svc, ok := strategy[field].(string); ok {
+			strategy[field] = appMeta.TemplatedName(svc)
+		}
+	}
+}
+
+type result struct {
+	data struct {
+		Meta           string
+		Spec           string
+		Selector       string
+		PodLabels      string
+		PodAnnotations string
+		PodSpec        string
+	}
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return "rollout.yaml"
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	return rolloutTempl.Execute(writer, r.data)
+}
@@ -0,0 +1,122 @@
+package rollout
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const rolloutYaml = `apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: my-app-api
+  namespace: my-app
+spec:
+  replicas: 3
+  revisionHistoryLimit: 2
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: api
+        image: example.com/api:1.2.3
+        resources:
+          limits:
+            cpu: 500m
+  strategy:
+    canary:
+      canaryService: my-app-canary
+      stableService: my-app-stable
+      steps:
+      - setWeight: 20
+      - pause: {}
+      - setWeight: 50
+      - pause:
+          duration: 10m`
+
+func Test_rollout_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := New().Process(&metadata.Service{}, internal.GenerateObj(rolloutYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := New().Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_rollout_Process_values(t *testing.T) {
+	obj := internal.GenerateObj(rolloutYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	for _, svc := range []string{"my-app-canary", "my-app-stable"} {
+		testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: ` + svc + `
+  namespace: my-app`))
+	}
+	_, tpl, err := New().Process(testMeta, obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	api := values["api"].(map[string]interface{})
+	assert.Equal(t, int64(3), api["replicas"])
+	assert.Equal(t, map[string]interface{}{"registry": "example.com", "repository": "api", "tag": "1.2.3", "digest": ""}, api["api"].(map[string]interface{})["image"])
+	steps := api["rollout"].(map[string]interface{})["canary"].(map[string]interface{})["steps"].([]interface{})
+	assert.Len(t, steps, 4)
+
+	steps[0] = map[string]interface{}{"setWeight": 10}
+	values["kubernetesClusterDomain"] = "cluster.local"
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			Replicas             int
+			RevisionHistoryLimit int `json:"revisionHistoryLimit"`
+			Selector             struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			}
+			Template struct {
+				Metadata struct {
+					Labels map[string]string
+				}
+				Spec struct {
+					Containers []struct {
+						Image string
+					}
+				}
+			}
+			Strategy struct {
+				Canary struct {
+					CanaryService string `json:"canaryService"`
+					StableService string `json:"stableService"`
+					Steps         []map[string]interface{}
+				}
+			}
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, 3, res.Spec.Replicas)
+	assert.Equal(t, 2, res.Spec.RevisionHistoryLimit)
+	assert.Equal(t, map[string]string{"app": "api", "app.kubernetes.io/instance": "release"}, res.Spec.Selector.MatchLabels)
+	assert.Equal(t, res.Spec.Selector.MatchLabels, res.Spec.Template.Metadata.Labels)
+	assert.Equal(t, "example.com/api:1.2.3", res.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "release-canary", res.Spec.Strategy.Canary.CanaryService)
+	assert.Equal(t, "release-stable", res.Spec.Strategy.Canary.StableService)
+	assert.Equal(t, map[string]interface{}{"setWeight": float64(10)}, res.Spec.Strategy.Canary.Steps[0])
+	assert.Equal(t, map[string]interface{}{"pause": map[string]interface{}{"duration": "10m"}}, res.Spec.Strategy.Canary.Steps[3])
+}
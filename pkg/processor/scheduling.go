@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+)
+
+// schedulingTempl - pod spec field taken from values. %[1]s - indent, %[2]s - value path, %[3]s - field, %[4]d - field content indent.
+const schedulingTempl = `
+%[1]s{{- with .Values.%[2]s.%[3]s }}
+%[1]s%[3]s:
+%[1]s  {{- toYaml . | nindent %[4]d }}
+%[1]s{{- end }}`
+
+// schedulingScalarTempl - optional pod spec string field taken from values. %[1]s - indent, %[2]s - value path, %[3]s - field.
+const schedulingScalarTempl = `
+%[1]s{{- with .Values.%[2]s.%[3]s }}
+%[1]s%[3]s: {{ . | quote }}
+%[1]s{{- end }}`
+
+// schedulingScalarFields - optional pod spec string fields with empty string values used if the field is not set.
+var schedulingScalarFields = []string{"runtimeClassName", "schedulerName"}
+
+// schedulingFields - pod spec scheduling fields with empty values used if the field is not set.
+var schedulingFields = []struct {
+	field string
+	empty func() interface{}
+}{
+	{field: "nodeSelector", empty: func() interface{} { return map[string]interface{}{} }},
+	{field: "tolerations", empty: func() interface{} { return []interface{}{} }},
+	{field: "affinity", empty: func() interface{} { return map[string]interface{}{} }},
+}
+
+// TemplateScheduling - moves pod spec nodeSelector, tolerations, affinity, runtimeClassName and schedulerName
+// to <name>.* values if enabled by config.
+// Fields are removed from pod spec. Returned template has to be appended to pod spec marshaled with given indent.
+func TemplateScheduling(appMeta helmify.AppMetadata, name string, podSpec map[string]interface{}, indent int, values *helmify.Values) (string, error) {
+	if !appMeta.Config().SchedulingValues {
+		return "", nil
+	}
+	res := ""
+	for _, f := range schedulingFields {
+		value, ok := podSpec[f.field]
+		if !ok {
+			value = f.empty()
+		}
+		delete(podSpec, f.field)
+		_, err := values.Add(value, name, f.field)
+		if err != nil {
+			return "", err
+		}
+		res += fmt.Sprintf(schedulingTempl, strings.Repeat(" ", indent), name, f.field, indent+2)
+	}
+	for _, field := range schedulingScalarFields {
+		value, ok := podSpec[field]
+		if !ok {
+			value = ""
+		}
+		delete(podSpec, field)
+		_, err := values.Add(value, name, field)
+		if err != nil {
+			return "", err
+		}
+		res += fmt.Sprintf(schedulingScalarTempl, strings.Repeat(" ", indent), name, field)
+	}
+	return res, nil
+}
@@ -0,0 +1,158 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var externalSecretGK = schema.GroupKind{
+	Group: "external-secrets.io",
+	Kind:  "ExternalSecret",
+}
+
+// NewExternal creates processor for external-secrets.io ExternalSecret resource.
+func NewExternal() helmify.Processor {
+	return &externalSecret{}
+}
+
+type externalSecret struct{}
+
+// Process ExternalSecret object into template. Returns false if not capable of processing given resource type.
+// secretStoreRef, refreshInterval and remote keys are moved to <name>.* values. Target secret name is templated.
+func (e externalSecret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind().GroupKind() != externalSecretGK {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get ExternalSecret spec")
+	}
+	values := helmify.Values{}
+	// fields are replaced with placeholders to prevent yaml line wrapping of templates
+	placeholders := map[string]string{}
+	addValue := func(value interface{}, path ...string) (string, error) {
+		tpl, err := values.Add(value, append([]string{nameCamel}, path...)...)
+		if err != nil {
+			return "", err
+		}
+		placeholder := fmt.Sprintf("helmify-external-secret-value-%d-", len(placeholders))
+		placeholders[placeholder] = tpl
+		return placeholder, nil
+	}
+
+	if interval, ok := spec["refreshInterval"].(string); ok {
+		spec["refreshInterval"], err = addValue(interval, "refreshInterval")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	storeRef, _ := spec["secretStoreRef"].(map[string]interface{})
+	for _, field := range []string{"name", "kind"} {
+		value, ok := storeRef[field].(string)
+		if !ok {
+			continue
+		}
+		if templated := appMeta.TemplatedName(value); field == "name" && templated != value {
+			// chart secret store
+			storeRef[field] = templated
+			continue
+		}
+		storeRef[field], err = addValue(value, "secretStoreRef", field)
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	if targetName, ok, _ := unstructured.NestedString(spec, "target", "name"); ok {
+		err = unstructured.SetNestedField(spec, appMeta.TemplatedName(targetName), "target", "name")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	data, _ := spec["data"].([]interface{})
+	for _, d := range data {
+		item, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		secretKey, _ := item["secretKey"].(string)
+		remoteKey, ok, _ := unstructured.NestedString(item, "remoteRef", "key")
+		if !ok || secretKey == "" {
+			continue
+		}
+		placeholder, err := addValue(remoteKey, "remoteKeys", secretKey)
+		if err != nil {
+			return true, nil, errors.Wrapf(err, "unable to process remote key for %s", secretKey)
+		}
+		err = unstructured.SetNestedField(item, placeholder, "remoteRef", "key")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	dataFrom, _ := spec["dataFrom"].([]interface{})
+	for i, d := range dataFrom {
+		item, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		remoteKey, ok, _ := unstructured.NestedString(item, "extract", "key")
+		if !ok {
+			continue
+		}
+		placeholder, err := addValue(remoteKey, "remoteKeys", fmt.Sprintf("dataFrom%d", i))
+		if err != nil {
+			return true, nil, err
+		}
+		err = unstructured.SetNestedField(item, placeholder, "extract", "key")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+
+	specYaml, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	specYaml = processor.UnquoteTemplates(specYaml)
+	for placeholder, tpl := range placeholders {
+		specYaml = strings.ReplaceAll(specYaml, placeholder, tpl)
+	}
+	return true, &externalResult{
+		name:   name,
+		data:   []byte(meta + "\n" + specYaml),
+		values: values,
+	}, nil
+}
+
+type externalResult struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *externalResult) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *externalResult) Values() helmify.Values {
+	return r.values
+}
+
+func (r *externalResult) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
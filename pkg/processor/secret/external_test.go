@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const externalSecretYaml = `apiVersion: external-secrets.io/v1beta1
+kind: ExternalSecret
+metadata:
+  name: my-app-db
+  namespace: my-app
+spec:
+  refreshInterval: 1h
+  secretStoreRef:
+    name: vault-backend
+    kind: ClusterSecretStore
+  target:
+    name: my-app-db-credentials
+  data:
+  - secretKey: password
+    remoteRef:
+      key: secret/data/my-app/db
+      property: password
+  dataFrom:
+  - extract:
+      key: secret/data/my-app/common`
+
+func Test_externalSecret_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := NewExternal().Process(&metadata.Service{}, internal.GenerateObj(externalSecretYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := NewExternal().Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func Test_externalSecret_Process_values(t *testing.T) {
+	obj := internal.GenerateObj(externalSecretYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-api
+  namespace: my-app`))
+	_, tpl, err := NewExternal().Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"db": map[string]interface{}{
+		"refreshInterval": "1h",
+		"secretStoreRef":  map[string]interface{}{"name": "vault-backend", "kind": "ClusterSecretStore"},
+		"remoteKeys": map[string]interface{}{
+			"password":  "secret/data/my-app/db",
+			"dataFrom0": "secret/data/my-app/common",
+		},
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			RefreshInterval string `json:"refreshInterval"`
+			SecretStoreRef  struct {
+				Name string
+				Kind string
+			} `json:"secretStoreRef"`
+			Target struct {
+				Name string
+			}
+			Data []struct {
+				RemoteRef struct {
+					Key      string
+					Property string
+				} `json:"remoteRef"`
+			}
+			DataFrom []struct {
+				Extract struct {
+					Key string
+				}
+			} `json:"dataFrom"`
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "1h", res.Spec.RefreshInterval)
+	assert.Equal(t, "vault-backend", res.Spec.SecretStoreRef.Name)
+	assert.Equal(t, "ClusterSecretStore", res.Spec.SecretStoreRef.Kind)
+	assert.Equal(t, "release-db-credentials", res.Spec.Target.Name)
+	assert.Equal(t, "secret/data/my-app/db", res.Spec.Data[0].RemoteRef.Key)
+	assert.Equal(t, "password", res.Spec.Data[0].RemoteRef.Property)
+	assert.Equal(t, "secret/data/my-app/common", res.Spec.DataFrom[0].Extract.Key)
+}
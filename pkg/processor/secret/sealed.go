@@ -0,0 +1,79 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// sealedTempl - %[1]s - metadata, %[2]s - spec.
+const sealedTempl = `{{- if .Values.sealedSecrets.enabled }}
+%[1]s
+%[2]s
+{{- end }}`
+
+var sealedSecretGK = schema.GroupKind{
+	Group: "bitnami.com",
+	Kind:  "SealedSecret",
+}
+
+// NewSealed creates processor for Bitnami SealedSecret resource.
+func NewSealed() helmify.Processor {
+	return &sealedSecret{}
+}
+
+type sealedSecret struct{}
+
+// Process SealedSecret object into template. Returns false if not capable of processing given resource type.
+// Encrypted data is kept as is and only metadata is templated. SealedSecrets are created if sealedSecrets.enabled value is set.
+func (s sealedSecret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind().GroupKind() != sealedSecretGK {
+		return false, nil, nil
+	}
+	log := logrus.WithField("SealedSecret", obj.GetName())
+	switch {
+	case obj.GetAnnotations()["sealedsecrets.bitnami.com/cluster-wide"] == "true":
+		log.Warn("encrypted data is kept sealed")
+	case metadata.IsSealedSecretRenamable(obj):
+		log.Warnf("encrypted data is kept sealed and can be unsealed only in namespace %q", obj.GetNamespace())
+	default:
+		log.Warnf("encrypted data is kept sealed and can be unsealed only in namespace %q, name is not templated", obj.GetNamespace())
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	spec, err := yamlformat.Marshal(map[string]interface{}{"spec": obj.Object["spec"]}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, &sealedResult{
+		name: appMeta.TrimName(obj.GetName()),
+		data: []byte(fmt.Sprintf(sealedTempl, meta, spec)),
+	}, nil
+}
+
+type sealedResult struct {
+	name string
+	data []byte
+}
+
+func (r *sealedResult) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *sealedResult) Values() helmify.Values {
+	return helmify.Values{"sealedSecrets": map[string]interface{}{"enabled": true}}
+}
+
+func (r *sealedResult) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
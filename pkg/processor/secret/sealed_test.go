@@ -0,0 +1,89 @@
+package secret
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const sealedSecretYaml = `apiVersion: bitnami.com/v1alpha1
+kind: SealedSecret
+metadata:
+  name: my-app-db
+  namespace: my-app
+spec:
+  encryptedData:
+    password: AgBy3i4OJSWK+PiTySYZZA==
+  template:
+    type: Opaque`
+
+func Test_sealedSecret_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := NewSealed().Process(&metadata.Service{}, internal.GenerateObj(sealedSecretYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := NewSealed().Process(&metadata.Service{}, internal.GenerateObj(secretYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+type renderedSealedSecret struct {
+	Metadata struct {
+		Name string
+	}
+	Spec struct {
+		EncryptedData map[string]string `json:"encryptedData"`
+	}
+}
+
+func Test_sealedSecret_Process_values(t *testing.T) {
+	render := func(obj string) (renderedSealedSecret, string) {
+		sealed := internal.GenerateObj(obj)
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(sealed)
+		testMeta.Load(internal.GenerateObj(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app-api
+  namespace: my-app`))
+		_, tpl, err := NewSealed().Process(testMeta, sealed)
+		assert.NoError(t, err)
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		values := tpl.Values()
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+		assert.NoError(t, err)
+		res := renderedSealedSecret{}
+		assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+
+		values["sealedSecrets"].(map[string]interface{})["enabled"] = false
+		disabled, err := internal.RenderTemplate("chart-name", buf.String(), values)
+		assert.NoError(t, err)
+		return res, disabled
+	}
+	t.Run("strict scope", func(t *testing.T) {
+		hook := test.NewGlobal()
+		defer hook.Reset()
+		res, disabled := render(sealedSecretYaml)
+		assert.Equal(t, "my-app-db", res.Metadata.Name)
+		assert.Equal(t, map[string]string{"password": "AgBy3i4OJSWK+PiTySYZZA=="}, res.Spec.EncryptedData)
+		assert.Empty(t, strings.TrimSpace(disabled))
+		assert.Contains(t, hook.LastEntry().Message, "name is not templated")
+	})
+	t.Run("cluster-wide", func(t *testing.T) {
+		res, _ := render(strings.Replace(sealedSecretYaml, "  namespace: my-app", `  namespace: my-app
+  annotations:
+    sealedsecrets.bitnami.com/cluster-wide: "true"`, 1))
+		assert.Equal(t, "release-db", res.Metadata.Name)
+	})
+}
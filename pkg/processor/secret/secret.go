@@ -1,11 +1,16 @@
 package secret
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"strings"
 	"text/template"
+	"unicode/utf8"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/processor"
+	"github.com/sirupsen/logrus"
 
 	"github.com/arttor/helmify/pkg/helmify"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
@@ -29,6 +34,11 @@ var secretTempl, _ = template.New("secret").Parse(
 {{ .Type }}
 {{- end }}`)
 
+// existingSecretTempl - skips Secret if <name>.existingSecret value is set. %[1]s - name, %[2]s - Secret template.
+const existingSecretTempl = `{{- if not .Values.%[1]s.existingSecret }}
+%[2]s
+{{- end }}`
+
 var configMapGVC = schema.GroupVersionKind{
 	Group:   "",
 	Version: "v1",
@@ -68,69 +78,120 @@ func (d secret) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructu
 		}
 	}
 
-	values := helmify.Values{}
+	conf := appMeta.Config()
+	values, secretValues := helmify.Values{}, helmify.Values{}
 	var data, stringData string
 	templatedData := map[string]string{}
-	for key := range sec.Data {
-		keyCamelCase := strcase.ToLowerCamel(key)
-		if key == strings.ToUpper(key) {
-			keyCamelCase = strcase.ToLowerCamel(strings.ToLower(key))
-		}
-		templatedName, err := values.AddSecret(true, nameCamelCase, keyCamelCase)
+	if creds, ok := dockerConfigCreds(&sec); ok {
+		dockerConfig, err := processDockerConfig(conf, &values, &secretValues, nameCamelCase, creds)
 		if err != nil {
-			return true, nil, errors.Wrap(err, "unable add secret to values")
+			return true, nil, err
+		}
+		// written as is: yaml marshaller wraps the long template and breaks quoted strings inside the action
+		data = "data:\n  " + corev1.DockerConfigJsonKey + ": " + dockerConfig
+	} else {
+		for key, value := range sec.Data {
+			templatedData[key], err = addSecretValue(conf, &values, &secretValues, true, nameCamelCase, secretValueName(sec.Type, key), value)
+			if err != nil {
+				return true, nil, err
+			}
 		}
-		templatedData[key] = templatedName
 	}
 	if len(templatedData) != 0 {
 		data, err = yamlformat.Marshal(map[string]interface{}{"data": templatedData}, 0)
 		if err != nil {
 			return true, nil, err
 		}
-		data = strings.ReplaceAll(data, "'", "")
+		data = processor.UnquoteTemplates(data)
 	}
 
 	templatedData = map[string]string{}
-	for key := range sec.StringData {
-		keyCamelCase := strcase.ToLowerCamel(key)
-		if key == strings.ToUpper(key) {
-			keyCamelCase = strcase.ToLowerCamel(strings.ToLower(key))
-		}
-		templatedName, err := values.AddSecret(false, nameCamelCase, keyCamelCase)
+	for key, value := range sec.StringData {
+		templatedData[key], err = addSecretValue(conf, &values, &secretValues, false, nameCamelCase, secretValueName(sec.Type, key), []byte(value))
 		if err != nil {
-			return true, nil, errors.Wrap(err, "unable add secret to values")
+			return true, nil, err
 		}
-		templatedData[key] = templatedName
 	}
 	if len(templatedData) != 0 {
 		stringData, err = yamlformat.Marshal(map[string]interface{}{"stringData": templatedData}, 0)
 		if err != nil {
 			return true, nil, err
 		}
-		stringData = strings.ReplaceAll(stringData, "'", "")
+		stringData = processor.UnquoteTemplates(stringData)
+	}
+
+	existingSecret := ""
+	if conf.ExistingSecrets {
+		_, err = values.Add("", nameCamelCase, "existingSecret")
+		if err != nil {
+			return true, nil, err
+		}
+		existingSecret = nameCamelCase
 	}
 
 	return true, &result{
-		name: name + ".yaml",
+		name:           name + ".yaml",
+		existingSecret: existingSecret,
 		data: struct {
 			Type       string
 			Meta       string
 			Data       string
 			StringData string
 		}{Type: secretType, Meta: meta, Data: data, StringData: stringData},
-		values: values,
+		values:       values,
+		secretValues: secretValues,
 	}, nil
 }
 
+// secretValueName - returns value name for Secret key. Keys of TLS Secrets are mapped to cert, key and ca values.
+func secretValueName(secretType corev1.SecretType, key string) string {
+	if valueName, ok := tlsValueNames[key]; ok && secretType == corev1.SecretTypeTLS {
+		return valueName
+	}
+	keyCamelCase := strcase.ToLowerCamel(key)
+	if key == strings.ToUpper(key) {
+		keyCamelCase = strcase.ToLowerCamel(strings.ToLower(key))
+	}
+	return keyCamelCase
+}
+
+// addSecretValue - adds secret key to <name>.<valueName> value and returns its template. If enabled by config, decoded
+// key content becomes the default value either in values or in secret values written into a separate values file.
+func addSecretValue(conf config.Config, values, secretValues *helmify.Values, toBase64 bool, name, valueName string, value []byte) (string, error) {
+	templatedName, err := values.AddSecret(toBase64, name, valueName)
+	if err != nil {
+		return "", errors.Wrap(err, "unable add secret to values")
+	}
+	if !conf.SecretValues && conf.SecretValuesFile == "" {
+		return templatedName, nil
+	}
+	if !utf8.Valid(value) {
+		logrus.Warnf("Secret %s value %s is not a valid UTF-8 string: value is left empty", name, valueName)
+		return templatedName, nil
+	}
+	target := values
+	if conf.SecretValuesFile != "" {
+		target = secretValues
+	}
+	err = unstructured.SetNestedField(*target, string(value), name, valueName)
+	if err != nil {
+		return "", errors.Wrap(err, "unable add secret to values")
+	}
+	return templatedName, nil
+}
+
 type result struct {
 	name string
-	data struct {
+	// existingSecret - value name of existing Secret replacing this one. Empty if not enabled.
+	existingSecret string
+	data           struct {
 		Type       string
 		Meta       string
 		Data       string
 		StringData string
 	}
-	values helmify.Values
+	values       helmify.Values
+	secretValues helmify.Values
 }
 
 func (r *result) Filename() string {
@@ -141,6 +202,19 @@ func (r *result) Values() helmify.Values {
 	return r.values
 }
 
+func (r *result) SecretValues() helmify.Values {
+	return r.secretValues
+}
+
 func (r *result) Write(writer io.Writer) error {
-	return secretTempl.Execute(writer, r.data)
+	if r.existingSecret == "" {
+		return secretTempl.Execute(writer, r.data)
+	}
+	var buf bytes.Buffer
+	err := secretTempl.Execute(&buf, r.data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, existingSecretTempl, r.existingSecret, buf.String())
+	return err
 }
@@ -1,9 +1,14 @@
 package secret
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -37,3 +42,132 @@ func Test_secret_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_secret_Process_secretValues(t *testing.T) {
+	var testInstance secret
+	obj := internal.GenerateObj(secretYaml)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", SecretValues: true}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorSecretVars": map[string]interface{}{
+		"var1": "my_secret_var_1",
+		"var2": "my_secret_var_2",
+		"var3": "string secret",
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.Secret{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, []byte("my_secret_var_1"), res.Data["VAR1"])
+	assert.Equal(t, "string secret", res.StringData["VAR3"])
+}
+
+func Test_secret_Process_secretValuesFile(t *testing.T) {
+	var testInstance secret
+	obj := internal.GenerateObj(secretYaml)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", SecretValuesFile: "secret-values.yaml"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorSecretVars": map[string]interface{}{
+		"var1": "",
+		"var2": "",
+		"var3": "",
+	}}, tpl.Values())
+	assert.Equal(t, helmify.Values{"myOperatorSecretVars": map[string]interface{}{
+		"var1": "my_secret_var_1",
+		"var2": "my_secret_var_2",
+		"var3": "string secret",
+	}}, tpl.(helmify.SecretValuesTemplate).SecretValues())
+}
+
+func Test_secret_Process_existingSecret(t *testing.T) {
+	var testInstance secret
+	obj := internal.GenerateObj(secretYaml)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", ExistingSecrets: true}), obj)
+	assert.NoError(t, err)
+	values := tpl.Values()
+	assert.Equal(t, "", values["myOperatorSecretVars"].(map[string]interface{})["existingSecret"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), "{{- if not .Values.myOperatorSecretVars.existingSecret }}\n")
+
+	values["myOperatorSecretVars"].(map[string]interface{})["existingSecret"] = "prod-secret"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Empty(t, rendered)
+}
+
+func Test_secret_Process_tls(t *testing.T) {
+	var testInstance secret
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: Secret
+metadata:
+  name: my-operator-tls
+  namespace: my-operator-system
+type: kubernetes.io/tls
+data:
+  tls.crt: Y2VydA==
+  tls.key: a2V5`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", SecretValues: true}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"myOperatorTls": map[string]interface{}{
+		"cert": "cert",
+		"key":  "key",
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.Secret{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, corev1.SecretTypeTLS, res.Type)
+	assert.Equal(t, []byte("cert"), res.Data["tls.crt"])
+	assert.Equal(t, []byte("key"), res.Data["tls.key"])
+}
+
+func Test_secret_Process_dockerConfig(t *testing.T) {
+	var testInstance secret
+	// {"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}
+	obj := internal.GenerateObj(`apiVersion: v1
+kind: Secret
+metadata:
+  name: my-operator-regcred
+  namespace: my-operator-system
+type: kubernetes.io/dockerconfigjson
+data:
+  .dockerconfigjson: eyJhdXRocyI6eyJyZWdpc3RyeS5leGFtcGxlLmNvbSI6eyJhdXRoIjoiZFhObGNqcHdZWE56In19fQ==`)
+	t.Run("empty credentials", func(t *testing.T) {
+		_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+		assert.NoError(t, err)
+		assert.Equal(t, helmify.Values{"myOperatorRegcred": map[string]interface{}{
+			"registry": "registry.example.com",
+			"username": "",
+			"password": "",
+			"email":    "",
+		}}, tpl.Values())
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		_, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "myOperatorRegcred.username is required")
+	})
+	t.Run("secret values", func(t *testing.T) {
+		_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name", SecretValues: true, ExistingSecrets: true}), obj)
+		assert.NoError(t, err)
+		values := tpl.Values()["myOperatorRegcred"].(map[string]interface{})
+		assert.Equal(t, "user", values["username"])
+		assert.Equal(t, "pass", values["password"])
+
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+		assert.NoError(t, err)
+		res := corev1.Secret{}
+		assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+		assert.Equal(t, corev1.SecretTypeDockerConfigJson, res.Type)
+		assert.JSONEq(t, `{"auths":{"registry.example.com":{"username":"user","password":"pass","auth":"dXNlcjpwYXNz"}}}`, string(res.Data[".dockerconfigjson"]))
+	})
+}
@@ -0,0 +1,85 @@
+package secret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tlsValueNames - value names for kubernetes.io/tls Secret keys.
+var tlsValueNames = map[string]string{
+	corev1.TLSCertKey:              "cert",
+	corev1.TLSPrivateKeyKey:        "key",
+	corev1.ServiceAccountRootCAKey: "ca",
+}
+
+// dockerConfigTempl - single action rendering base64 encoded docker config from <name>.* values. Template variables
+// are not used, so the action is kept as a whole when values are inlined. Email is omitted if empty. %[1]s - name.
+const dockerConfigTempl = `{{ omit (dict "username" (required "%[1]s.username is required" .Values.%[1]s.username) ` +
+	`"password" (required "%[1]s.password is required" .Values.%[1]s.password) ` +
+	`"auth" (printf "%%s:%%s" .Values.%[1]s.username .Values.%[1]s.password | b64enc) "email" .Values.%[1]s.email) ` +
+	`(ternary "email" "" (empty .Values.%[1]s.email)) | dict (required "%[1]s.registry is required" .Values.%[1]s.registry) ` +
+	`| dict "auths" | toJson | b64enc }}`
+
+// dockerCreds - single registry credentials of docker config.
+type dockerCreds struct {
+	registry, username, password, email string
+}
+
+// dockerConfigCreds - returns registry credentials of kubernetes.io/dockerconfigjson Secret.
+// Returns false if the Secret is not a docker config Secret with credentials for a single registry.
+func dockerConfigCreds(sec *corev1.Secret) (dockerCreds, bool) {
+	res := dockerCreds{}
+	configJSON, ok := sec.Data[corev1.DockerConfigJsonKey]
+	if sec.Type != corev1.SecretTypeDockerConfigJson || !ok || len(sec.Data) != 1 || len(sec.StringData) != 0 {
+		return res, false
+	}
+	config := struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Email    string `json:"email"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(configJSON, &config); err != nil || len(config.Auths) != 1 {
+		return res, false
+	}
+	for registry, auth := range config.Auths {
+		res = dockerCreds{registry: registry, username: auth.Username, password: auth.Password, email: auth.Email}
+		if res.username != "" || auth.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return res, false
+		}
+		res.username, res.password, _ = strings.Cut(string(decoded), ":")
+	}
+	return res, true
+}
+
+// processDockerConfig - adds docker registry credentials to <name>.{registry,username,password,email} values
+// and returns template rendering docker config from them. Registry is always kept as the default value.
+func processDockerConfig(conf config.Config, values, secretValues *helmify.Values, name string, creds dockerCreds) (string, error) {
+	_, err := values.Add(creds.registry, name, "registry")
+	if err != nil {
+		return "", err
+	}
+	for _, v := range []struct{ name, value string }{
+		{name: "username", value: creds.username},
+		{name: "password", value: creds.password},
+		{name: "email", value: creds.email},
+	} {
+		_, err = addSecretValue(conf, values, secretValues, false, name, v.name, []byte(v.value))
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf(dockerConfigTempl, name), nil
+}
@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/iancoleman/strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TemplateSecurityContext - moves pod securityContext to <name>.podSecurityContext value and containers
+// securityContext to <name>.<container name>.securityContext values. Pod spec is expected to be marshaled with given indent.
+// Values are rendered with library chart 'securityContext' helper if library chart is enabled.
+func TemplateSecurityContext(appMeta helmify.AppMetadata, name string, podSpec map[string]interface{}, indent int, values *helmify.Values) error {
+	helper := ""
+	if library := appMeta.Config().LibraryChart; library != "" {
+		helper = library + ".securityContext"
+	}
+	if podCtx, ok := podSpec["securityContext"].(map[string]interface{}); ok && len(podCtx) != 0 {
+		_, err := values.Add(podCtx, name, "podSecurityContext")
+		if err != nil {
+			return err
+		}
+		podSpec["securityContext"] = fmt.Sprintf(`{{- %s .Values.%s.podSecurityContext | nindent %d }}`, toYamlFunc(helper), name, indent+2)
+	}
+	return templateContainersField(name, "securityContext", helper, podSpec, indent, values)
+}
+
+// toYamlFunc - returns template function rendering values as yaml: 'toYaml' or include of the given helper.
+func toYamlFunc(helper string) string {
+	if helper == "" {
+		return "toYaml"
+	}
+	return fmt.Sprintf("include %q", helper)
+}
+
+// templateContainersField - moves non-empty containers field to <name>.<container name>.<field> values.
+// Values are rendered with optional helper instead of toYaml. Pod spec is expected to be marshaled with given indent.
+func templateContainersField(name, field, helper string, podSpec map[string]interface{}, indent int, values *helmify.Values) error {
+	containers, _, err := unstructured.NestedSlice(podSpec, "containers")
+	if err != nil {
+		return err
+	}
+	for i := range containers {
+		container, ok := containers[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value := container[field]
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		containerName := strcase.ToLowerCamel(container["name"].(string))
+		_, err = values.Add(value, name, containerName, field)
+		if err != nil {
+			return err
+		}
+		container[field] = fmt.Sprintf(`{{- %s .Values.%s.%s.%s | nindent %d }}`, toYamlFunc(helper), name, containerName, field, indent+4)
+	}
+	return unstructured.SetNestedSlice(podSpec, containers, "containers")
+}
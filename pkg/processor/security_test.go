@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplateSecurityContext(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"securityContext": map[string]interface{}{
+			"runAsUser": int64(1000),
+			"fsGroup":   int64(2000),
+		},
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":  "web-app",
+				"image": "nginx",
+				"securityContext": map[string]interface{}{
+					"readOnlyRootFilesystem": true,
+				},
+			},
+			map[string]interface{}{
+				"name":  "sidecar",
+				"image": "busybox",
+			},
+		},
+	}
+	values := helmify.Values{}
+	err := TemplateSecurityContext(metadata.New(config.Config{}), "web", podSpec, 0, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"web": map[string]interface{}{
+		"podSecurityContext": map[string]interface{}{"runAsUser": int64(1000), "fsGroup": int64(2000)},
+		"webApp": map[string]interface{}{
+			"securityContext": map[string]interface{}{"readOnlyRootFilesystem": true},
+		},
+	}}, values)
+
+	spec, err := yaml.Marshal(podSpec, 0)
+	assert.NoError(t, err)
+	rendered, err := internal.RenderTemplate("chart-name", strings.ReplaceAll(spec, "'", ""), values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, int64(1000), *res.SecurityContext.RunAsUser)
+	assert.Equal(t, int64(2000), *res.SecurityContext.FSGroup)
+	assert.True(t, *res.Containers[0].SecurityContext.ReadOnlyRootFilesystem)
+	assert.Nil(t, res.Containers[1].SecurityContext)
+}
+
+func TestTemplateSecurityContext_library(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"securityContext": map[string]interface{}{"runAsNonRoot": true},
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":            "app",
+				"securityContext": map[string]interface{}{"privileged": false},
+			},
+		},
+	}
+	values := helmify.Values{}
+	err := TemplateSecurityContext(metadata.New(config.Config{LibraryChart: "common"}), "web", podSpec, 0, &values)
+	assert.NoError(t, err)
+	assert.Equal(t, `{{- include "common.securityContext" .Values.web.podSecurityContext | nindent 2 }}`, podSpec["securityContext"])
+	container := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, `{{- include "common.securityContext" .Values.web.app.securityContext | nindent 4 }}`, container["securityContext"])
+}
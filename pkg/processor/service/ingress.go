@@ -1,21 +1,70 @@
 package service
 
 import (
+	"fmt"
+	"io"
+	"strings"
+
 	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/processor"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
-	"io"
+	"github.com/sirupsen/logrus"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"text/template"
 )
 
-var ingressTempl, _ = template.New("ingress").Parse(
-	`{{ .Meta }}
-{{ .Spec }}`)
+// ingressTempl - Ingress template modeled on 'helm create' with backends kept in values.
+// %[1]s - values path, %[2]s - metadata, %[3]s - defaultBackend, %[4]s - backend service name.
+const ingressTempl = `{{- if .Values.%[1]s.enabled }}
+%[2]s
+  {{- with .Values.%[1]s.annotations }}
+  annotations:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+spec:
+  {{- with .Values.%[1]s.className }}
+  ingressClassName: {{ . }}
+  {{- end }}%[3]s
+  {{- if .Values.%[1]s.tls }}
+  tls:
+    {{- range .Values.%[1]s.tls }}
+    - hosts:
+        {{- range .hosts }}
+        - {{ . | quote }}
+        {{- end }}
+      {{- with .secretName }}
+      secretName: {{ . }}
+      {{- end }}
+    {{- end }}
+  {{- end }}
+  rules:
+    {{- range .Values.%[1]s.hosts }}
+    - {{- with .host }}
+      host: {{ . | quote }}
+      {{- end }}
+      http:
+        paths:
+          {{- range .paths }}
+          - path: {{ .path }}
+            {{- with .pathType }}
+            pathType: {{ . }}
+            {{- end }}
+            backend:
+              {{- if .backend.service }}
+              service:
+                name: %[4]s
+                port:
+                  {{- toYaml .backend.service.port | nindent 18 }}
+              {{- else }}
+              {{- toYaml .backend | nindent 14 }}
+              {{- end }}
+          {{- end }}
+    {{- end }}
+{{- end }}`
 
 var ingressGVC = schema.GroupVersionKind{
 	Group:   "networking.k8s.io",
@@ -30,7 +79,8 @@ func NewIngress() helmify.Processor {
 
 type ingress struct{}
 
-// Process k8s Service object into template. Returns false if not capable of processing given resource type.
+// Process k8s Ingress object into template. Returns false if not capable of processing given resource type.
+// Class name, annotations, hosts and tls are moved to 'ingress' values like 'helm create' does.
 func (r ingress) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
 	if obj.GroupVersionKind() != ingressGVC {
 		return false, nil, nil
@@ -40,47 +90,121 @@ func (r ingress) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstruct
 	if err != nil {
 		return true, nil, errors.Wrap(err, "unable to cast to ingress")
 	}
+	// annotations are moved to values
+	obj.SetAnnotations(nil)
 	meta, err := processor.ProcessObjMeta(appMeta, obj)
 	if err != nil {
 		return true, nil, err
 	}
 	name := appMeta.TrimName(obj.GetName())
-	processIngressSpec(appMeta, &ing.Spec)
-	spec, err := yamlformat.Marshal(map[string]interface{}{"spec": &ing.Spec}, 0)
+	valuesPath := IngressValuesPath(appMeta, obj.GetName())
+
+	defaultBackend := ""
+	if ing.Spec.DefaultBackend != nil {
+		if ing.Spec.DefaultBackend.Service != nil {
+			ing.Spec.DefaultBackend.Service.Name = appMeta.TemplatedName(ing.Spec.DefaultBackend.Service.Name)
+		}
+		defaultBackend, err = yamlformat.Marshal(map[string]interface{}{"defaultBackend": ing.Spec.DefaultBackend}, 2)
+		if err != nil {
+			return true, nil, err
+		}
+		defaultBackend = "\n" + processor.UnquoteTemplates(defaultBackend)
+	}
+
+	chartBackends := allChartBackends(appMeta, ing.Spec)
+	hosts := make([]interface{}, 0, len(ing.Spec.Rules))
+	for _, rule := range ing.Spec.Rules {
+		paths := []interface{}{}
+		if rule.HTTP != nil {
+			for _, p := range rule.HTTP.Paths {
+				if p.Backend.Service != nil && chartBackends {
+					p.Backend.Service.Name = appMeta.TrimName(p.Backend.Service.Name)
+				}
+				path, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&p)
+				if err != nil {
+					return true, nil, err
+				}
+				paths = append(paths, path)
+			}
+		}
+		hosts = append(hosts, map[string]interface{}{"host": rule.Host, "paths": paths})
+	}
+	tls := make([]interface{}, 0, len(ing.Spec.TLS))
+	for _, t := range ing.Spec.TLS {
+		tlsMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&t)
+		if err != nil {
+			return true, nil, err
+		}
+		tls = append(tls, tlsMap)
+	}
+	className := ""
+	if ing.Spec.IngressClassName != nil {
+		className = *ing.Spec.IngressClassName
+	}
+	annotations := map[string]interface{}{}
+	for k, v := range ing.GetAnnotations() {
+		annotations[k] = v
+	}
+	values := helmify.Values{}
+	err = unstructured.SetNestedField(values, map[string]interface{}{
+		"enabled":     true,
+		"className":   className,
+		"annotations": annotations,
+		"hosts":       hosts,
+		"tls":         tls,
+	}, strings.Split(valuesPath, ".")...)
 	if err != nil {
 		return true, nil, err
 	}
 
+	serviceName := "{{ .backend.service.name }}"
+	if chartBackends {
+		serviceName = fmt.Sprintf(`{{ include "%s.fullname" $ }}-{{ .backend.service.name }}`, appMeta.ChartName())
+	}
 	return true, &ingressResult{
-		name: name + ".yaml",
-		data: struct {
-			Meta string
-			Spec string
-		}{Meta: meta, Spec: spec},
+		name:   name + ".yaml",
+		data:   fmt.Sprintf(ingressTempl, valuesPath, meta, defaultBackend, serviceName),
+		values: values,
 	}, nil
 }
 
-func processIngressSpec(appMeta helmify.AppMetadata, ing *networkingv1.IngressSpec) {
-	if ing.DefaultBackend != nil && ing.DefaultBackend.Service != nil {
-		ing.DefaultBackend.Service.Name = appMeta.TemplatedName(ing.DefaultBackend.Service.Name)
+// IngressValuesPath - returns dot-separated path of Ingress values: 'ingress' if the chart has a single Ingress,
+// 'ingress.<name>' otherwise.
+func IngressValuesPath(appMeta helmify.AppMetadata, name string) string {
+	if appMeta.KindCount(ingressGVC.Kind) > 1 {
+		return "ingress." + strcase.ToLowerCamel(appMeta.TrimName(name))
 	}
-	for i := range ing.Rules {
-		if ing.Rules[i].IngressRuleValue.HTTP != nil {
-			for j := range ing.Rules[i].IngressRuleValue.HTTP.Paths {
-				if ing.Rules[i].IngressRuleValue.HTTP.Paths[j].Backend.Service != nil {
-					ing.Rules[i].IngressRuleValue.HTTP.Paths[j].Backend.Service.Name = appMeta.TemplatedName(ing.Rules[i].IngressRuleValue.HTTP.Paths[j].Backend.Service.Name)
-				}
+	return "ingress"
+}
+
+// allChartBackends - returns true if all rules backend services are chart services, so their names can be templated.
+func allChartBackends(appMeta helmify.AppMetadata, spec networkingv1.IngressSpec) bool {
+	chart, external := 0, 0
+	for _, rule := range spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			if p.Backend.Service == nil {
+				continue
+			}
+			if appMeta.TemplatedName(p.Backend.Service.Name) != p.Backend.Service.Name {
+				chart++
+			} else {
+				external++
 			}
 		}
 	}
+	if chart != 0 && external != 0 {
+		logrus.Warn("ingress: backends refer to both chart and external services, service names are not templated")
+	}
+	return chart != 0 && external == 0
 }
 
 type ingressResult struct {
-	name string
-	data struct {
-		Meta string
-		Spec string
-	}
+	name   string
+	data   string
+	values helmify.Values
 }
 
 func (r *ingressResult) Filename() string {
@@ -88,9 +212,10 @@ func (r *ingressResult) Filename() string {
 }
 
 func (r *ingressResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *ingressResult) Write(writer io.Writer) error {
-	return ingressTempl.Execute(writer, r.data)
+	_, err := writer.Write([]byte(r.data))
+	return err
 }
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/metadata"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -43,3 +48,95 @@ func Test_ingress_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_ingress_Process_values(t *testing.T) {
+	var testInstance ingress
+	obj := internal.GenerateObj(`apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: myapp-ingress
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /
+spec:
+  ingressClassName: nginx
+  tls:
+  - hosts:
+    - myapp.example.com
+    secretName: myapp-tls
+  rules:
+  - host: myapp.example.com
+    http:
+      paths:
+      - path: /testpath
+        pathType: Prefix
+        backend:
+          service:
+            name: myapp-service
+            port:
+              number: 8443`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: myapp-service`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	values := tpl.Values()["ingress"].(map[string]interface{})
+	assert.Equal(t, true, values["enabled"])
+	assert.Equal(t, "nginx", values["className"])
+	assert.Equal(t, map[string]interface{}{"nginx.ingress.kubernetes.io/rewrite-target": "/"}, values["annotations"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"host": "myapp.example.com",
+		"paths": []interface{}{map[string]interface{}{
+			"path":     "/testpath",
+			"pathType": "Prefix",
+			"backend": map[string]interface{}{"service": map[string]interface{}{
+				"name": "service",
+				"port": map[string]interface{}{"number": int64(8443)},
+			}},
+		}},
+	}}, values["hosts"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := networkingv1.Ingress{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "/", res.Annotations["nginx.ingress.kubernetes.io/rewrite-target"])
+	assert.Equal(t, "nginx", *res.Spec.IngressClassName)
+	assert.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"myapp.example.com"}, SecretName: "myapp-tls"}}, res.Spec.TLS)
+	assert.Equal(t, "myapp.example.com", res.Spec.Rules[0].Host)
+	backend := res.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	assert.Equal(t, "release-service", backend.Name)
+	assert.Equal(t, int32(8443), backend.Port.Number)
+
+	values["enabled"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_ingress_Process_several(t *testing.T) {
+	var testInstance ingress
+	web := internal.GenerateObj(strings.Replace(ingressYaml, "myapp-ingress", "myapp-web", 1))
+	api := internal.GenerateObj(strings.Replace(ingressYaml, "myapp-ingress", "myapp-api", 1))
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(web)
+	testMeta.Load(api)
+	assert.Equal(t, "ingress.web", IngressValuesPath(testMeta, web.GetName()))
+
+	_, tpl, err := testInstance.Process(testMeta, api)
+	assert.NoError(t, err)
+	values := tpl.Values()["ingress"].(map[string]interface{})["api"].(map[string]interface{})
+	assert.Equal(t, true, values["enabled"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := networkingv1.Ingress{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "/testpath", res.Spec.Rules[0].HTTP.Paths[0].Path)
+}
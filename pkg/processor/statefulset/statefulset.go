@@ -6,13 +6,14 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/arttor/helmify/pkg/cluster"
 	"github.com/arttor/helmify/pkg/processor"
+	"github.com/arttor/helmify/pkg/processor/pod"
 
 	"github.com/arttor/helmify/pkg/helmify"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
 	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -31,6 +32,9 @@ var statefulsetTempl, _ = template.New("statefulset").Parse(
 spec:
 {{- if .Replicas }}
 {{ .Replicas }}
+{{- end }}
+{{- if .Fields }}
+{{ .Fields }}
 {{- end }}
   selector:
 {{ .Selector }}
@@ -49,6 +53,10 @@ const selectorTempl = `%[1]s
 {{- include "%[2]s.selectorLabels" . | nindent 6 }}
 %[3]s`
 
+const volumeClaimTemplatesTempl = `  {{- if .Values.persistence.enabled }}
+%s
+  {{- end }}`
+
 // New creates processor for k8s Statefulset resource.
 func New() helmify.Processor {
 	return &statefulset{}
@@ -74,148 +82,94 @@ func (d statefulset) Process(appMeta helmify.AppMetadata, obj *unstructured.Unst
 	values := helmify.Values{}
 
 	name := appMeta.TrimName(obj.GetName())
-	replicas, err := processReplicas(name, &statefl, &values)
+	replicas, err := processReplicas(name, &statefl, appMeta.Config().ReplicaCount, &values)
 	if err != nil {
 		return true, nil, err
 	}
-
-	matchLabels, err := yamlformat.Marshal(map[string]interface{}{"matchLabels": statefl.Spec.Selector.MatchLabels}, 0)
+	replicas, err = processor.GuardAutoscaledReplicas(appMeta, obj, replicas, &values)
 	if err != nil {
 		return true, nil, err
 	}
-	matchExpr := ""
-	if statefl.Spec.Selector.MatchExpressions != nil {
-		matchExpr, err = yamlformat.Marshal(map[string]interface{}{"matchExpressions": statefl.Spec.Selector.MatchExpressions}, 0)
-		if err != nil {
-			return true, nil, err
-		}
-	}
-	selector := fmt.Sprintf(selectorTempl, matchLabels, appMeta.ChartName(), matchExpr)
-	selector = strings.Trim(selector, " \n")
-	selector = string(yamlformat.Indent([]byte(selector), 4))
 
-	podLabels, err := yamlformat.Marshal(statefl.Spec.Template.ObjectMeta.Labels, 8)
+	fields, err := processSpecFields(name, appMeta, obj, &values)
 	if err != nil {
 		return true, nil, err
 	}
-	podLabels += fmt.Sprintf("\n      {{- include \"%s.selectorLabels\" . | nindent 8 }}", appMeta.ChartName())
 
-	podAnnotations := ""
-	if len(statefl.Spec.Template.ObjectMeta.Annotations) != 0 {
-		podAnnotations, err = yamlformat.Marshal(map[string]interface{}{"annotations": statefl.Spec.Template.ObjectMeta.Annotations}, 6)
+	matchLabels := "matchLabels:"
+	if len(statefl.Spec.Selector.MatchLabels) != 0 {
+		matchLabels, err = yamlformat.Marshal(map[string]interface{}{"matchLabels": statefl.Spec.Selector.MatchLabels}, 0)
 		if err != nil {
 			return true, nil, err
 		}
-
-		podAnnotations = "\n" + podAnnotations
-	}
-
-	nameCamel := strcase.ToLowerCamel(name)
-	podValues, err := processPodSpec(nameCamel, appMeta, &statefl.Spec.Template.Spec)
-	if err != nil {
-		return true, nil, err
-	}
-	err = values.Merge(podValues)
-	if err != nil {
-		return true, nil, err
-	}
-
-	// replace PVC to templated name
-	for i := 0; i < len(statefl.Spec.Template.Spec.Volumes); i++ {
-		vol := statefl.Spec.Template.Spec.Volumes[i]
-		if vol.PersistentVolumeClaim == nil {
-			continue
-		}
-		tempPVCName := appMeta.TemplatedName(vol.PersistentVolumeClaim.ClaimName)
-		statefl.Spec.Template.Spec.Volumes[i].PersistentVolumeClaim.ClaimName = tempPVCName
-	}
-
-	// replace container resources with template to values.
-	specMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&statefl.Spec.Template.Spec)
-	if err != nil {
-		return true, nil, err
-	}
-	containers, _, err := unstructured.NestedSlice(specMap, "containers")
-	if err != nil {
-		return true, nil, err
+	} else {
+		// chart selector labels become the only matchLabels
+		logrus.Warnf("statefulset %s selector has no matchLabels: chart selector labels are added to it. "+
+			"StatefulSet selector is immutable, existing StatefulSet has to be recreated on upgrade", obj.GetName())
 	}
-	for i := range containers {
-		containerName := strcase.ToLowerCamel((containers[i].(map[string]interface{})["name"]).(string))
-		res, exists, err := unstructured.NestedMap(values, nameCamel, containerName, "resources")
-		if err != nil {
-			return true, nil, err
-		}
-		if !exists || len(res) == 0 {
-			continue
-		}
-		err = unstructured.SetNestedField(containers[i].(map[string]interface{}), fmt.Sprintf(`{{- toYaml .Values.%s.%s.resources | nindent 10 }}`, nameCamel, containerName), "resources")
+	matchExpr := ""
+	if statefl.Spec.Selector.MatchExpressions != nil {
+		matchExpr, err = yamlformat.Marshal(map[string]interface{}{"matchExpressions": statefl.Spec.Selector.MatchExpressions}, 0)
 		if err != nil {
 			return true, nil, err
 		}
 	}
-	err = unstructured.SetNestedSlice(specMap, containers, "containers")
-	if err != nil {
-		return true, nil, err
-	}
-
+	selector := fmt.Sprintf(selectorTempl, matchLabels, appMeta.ChartName(), matchExpr)
+	selector = strings.Trim(selector, " \n")
+	selector = string(yamlformat.Indent([]byte(selector), 4))
 
-	spec, err := yamlformat.Marshal(specMap, 6)
+	origPodSpec, _, _ := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+	opts := pod.Options{
+		RestartAnnotation: appMeta.Config().RestartAnnotation,
+		ClaimTemplates:    claimTemplateNames(statefl.Spec.VolumeClaimTemplates),
+	}
+	podTpl, err := pod.ProcessTemplate(name, appMeta, &statefl.Spec.Template, origPodSpec, 6, opts, &values)
 	if err != nil {
 		return true, nil, err
 	}
-	spec = strings.ReplaceAll(spec, "'", "")
-
-	//VolumeClaimTemplates
 
 	volumeClaimTemplates := ""
 	if len(statefl.Spec.VolumeClaimTemplates) != 0 {
-		volumeClaimTemplates, err = yamlformat.Marshal(map[string]interface{}{"VolumeClaimTemplates": statefl.Spec.VolumeClaimTemplates}, 2)
+		volumeClaimTemplates, err = processVolumeClaimTemplates(strcase.ToLowerCamel(name), appMeta, statefl.Spec.VolumeClaimTemplates, &values)
 		if err != nil {
 			return true, nil, err
 		}
-
-		volumeClaimTemplates = "\n" + volumeClaimTemplates
 	}
-	//volumeClaimTemplates = strings.ReplaceAll(spec, "'", "")
 
-
-	/*
-	for i := 0; i < len(statefl.Spec.VolumeClaimTemplates); i++ {
-		volClaim := statefl.Spec.VolumeClaimTemplates[i]
-		if vol.PersistentVolumeClaim == nil {
-			continue
-		}
-		tempPVCName := appMeta.TemplatedName(vol.PersistentVolumeClaim.ClaimName)
-		statefl.Spec.Template.Spec.Volumes[i].PersistentVolumeClaim.ClaimName = tempPVCName
-	}
-	*/
 	return true, &result{
 		values: values,
 		data: struct {
-			Meta           string
-			Replicas       string
-			Selector       string
-			PodLabels      string
-			PodAnnotations string			
-			Spec           string
+			Meta                 string
+			Replicas             string
+			Fields               string
+			Selector             string
+			PodLabels            string
+			PodAnnotations       string
+			Spec                 string
 			VolumeClaimTemplates string
 		}{
-			Meta:           meta,
-			Replicas:       replicas,
-			Selector:       selector,
-			PodLabels:      podLabels,
-			PodAnnotations: podAnnotations,
-			Spec:           spec,
+			Meta:                 meta,
+			Replicas:             replicas,
+			Fields:               fields,
+			Selector:             selector,
+			PodLabels:            podTpl.Labels,
+			PodAnnotations:       podTpl.Annotations,
+			Spec:                 podTpl.Spec,
 			VolumeClaimTemplates: volumeClaimTemplates,
 		},
 	}, nil
 }
 
-func processReplicas(name string, statefulset *appsv1.StatefulSet, values *helmify.Values) (string, error) {
+// processReplicas - adds replicas to <name>.replicas value or to top-level replicaCount value if replicaCount is set.
+func processReplicas(name string, statefulset *appsv1.StatefulSet, replicaCount bool, values *helmify.Values) (string, error) {
 	if statefulset.Spec.Replicas == nil {
 		return "", nil
 	}
-	replicasTpl, err := values.Add(int64(*statefulset.Spec.Replicas), name, "replicas")
+	valueName := []string{name, "replicas"}
+	if replicaCount {
+		valueName = []string{"replicaCount"}
+	}
+	replicasTpl, err := values.Add(int64(*statefulset.Spec.Replicas), valueName...)
 	if err != nil {
 		return "", err
 	}
@@ -223,96 +177,105 @@ func processReplicas(name string, statefulset *appsv1.StatefulSet, values *helmi
 	if err != nil {
 		return "", err
 	}
-	replicas = strings.ReplaceAll(replicas, "'", "")
+	replicas = processor.UnquoteTemplates(replicas)
 	return replicas, nil
 }
 
-func processPodSpec(name string, appMeta helmify.AppMetadata, pod *corev1.PodSpec) (helmify.Values, error) {
-	values := helmify.Values{}
-	for i, c := range pod.Containers {
-		processed, err := processPodContainer(name, appMeta, c, &values)
+// processSpecFields - templates serviceName with the chart Service name and moves podManagementPolicy
+// and updateStrategy to <name>.* values.
+func processSpecFields(name string, appMeta helmify.AppMetadata, obj *unstructured.Unstructured, values *helmify.Values) (string, error) {
+	fields := map[string]interface{}{}
+	if serviceName, ok, _ := unstructured.NestedString(obj.Object, "spec", "serviceName"); ok && serviceName != "" {
+		fields["serviceName"] = appMeta.TemplatedName(serviceName)
+	}
+	if policy, ok, _ := unstructured.NestedString(obj.Object, "spec", "podManagementPolicy"); ok && policy != "" {
+		policyTpl, err := values.Add(policy, name, "podManagementPolicy")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		pod.Containers[i] = processed
+		fields["podManagementPolicy"] = policyTpl
 	}
-	for _, v := range pod.Volumes {
-		if v.ConfigMap != nil {
-			v.ConfigMap.Name = appMeta.TemplatedName(v.ConfigMap.Name)
-		}
-		if v.Secret != nil {
-			v.Secret.SecretName = appMeta.TemplatedName(v.Secret.SecretName)
+	if strategy, ok, _ := unstructured.NestedMap(obj.Object, "spec", "updateStrategy"); ok && len(strategy) != 0 {
+		_, err := values.Add(strategy, name, "updateStrategy")
+		if err != nil {
+			return "", err
 		}
+		fields["updateStrategy"] = fmt.Sprintf(`{{- toYaml .Values.%s.updateStrategy | nindent 4 }}`, strcase.ToLowerCamel(name))
 	}
-	pod.ServiceAccountName = appMeta.TemplatedName(pod.ServiceAccountName)
-
-	for i, s := range pod.ImagePullSecrets {
-		pod.ImagePullSecrets[i].Name = appMeta.TemplatedName(s.Name)
-	}
-
-	return values, nil
-}
-
-func processPodContainer(name string, appMeta helmify.AppMetadata, c corev1.Container, values *helmify.Values) (corev1.Container, error) {
-	index := strings.LastIndex(c.Image, ":")
-	if index < 0 {
-		return c, errors.New("wrong image format: " + c.Image)
-	}
-	repo, tag := c.Image[:index], c.Image[index+1:]
-	containerName := strcase.ToLowerCamel(c.Name)
-	c.Image = fmt.Sprintf("{{ .Values.%[1]s.%[2]s.image.repository }}:{{ .Values.%[1]s.%[2]s.image.tag | default .Chart.AppVersion }}", name, containerName)
-
-	err := unstructured.SetNestedField(*values, repo, name, containerName, "image", "repository")
-	if err != nil {
-		return c, errors.Wrap(err, "unable to set statefulset value field")
+	if len(fields) == 0 {
+		return "", nil
 	}
-	err = unstructured.SetNestedField(*values, tag, name, containerName, "image", "tag")
+	res, err := yamlformat.Marshal(fields, 2)
 	if err != nil {
-		return c, errors.Wrap(err, "unable to set statefulset value field")
+		return "", err
 	}
-	for _, e := range c.Env {
-		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
-			e.ValueFrom.SecretKeyRef.Name = appMeta.TemplatedName(e.ValueFrom.SecretKeyRef.Name)
+	return processor.UnquoteTemplates(res), nil
+}
+
+// processVolumeClaimTemplates - moves claims storage size and storage class to
+// <name>.volumeClaimTemplates.<claim>.{size,storageClass} values. Storage class from the chart is templated instead.
+func processVolumeClaimTemplates(name string, appMeta helmify.AppMetadata, claims []corev1.PersistentVolumeClaim, values *helmify.Values) (string, error) {
+	res := make([]interface{}, len(claims))
+	for i := range claims {
+		claim, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&claims[i])
+		if err != nil {
+			return "", err
 		}
-		if e.ValueFrom != nil && e.ValueFrom.ConfigMapKeyRef != nil {
-			e.ValueFrom.ConfigMapKeyRef.Name = appMeta.TemplatedName(e.ValueFrom.ConfigMapKeyRef.Name)
+		delete(claim, "status")
+		unstructured.RemoveNestedField(claim, "metadata", "creationTimestamp")
+		claimName := strcase.ToLowerCamel(claims[i].Name)
+		if size, ok, _ := unstructured.NestedString(claim, "spec", "resources", "requests", "storage"); ok {
+			sizeTpl, err := values.Add(size, name, "volumeClaimTemplates", claimName, "size")
+			if err != nil {
+				return "", err
+			}
+			err = unstructured.SetNestedField(claim, sizeTpl, "spec", "resources", "requests", "storage")
+			if err != nil {
+				return "", err
+			}
 		}
-	}
-	for _, e := range c.EnvFrom {
-		if e.SecretRef != nil {
-			e.SecretRef.Name = appMeta.TemplatedName(e.SecretRef.Name)
-		}
-		if e.ConfigMapRef != nil {
-			e.ConfigMapRef.Name = appMeta.TemplatedName(e.ConfigMapRef.Name)
+		if sc := claims[i].Spec.StorageClassName; sc != nil {
+			// keep storage class name consistent with templated StorageClass from the chart
+			scTpl := appMeta.TemplatedName(*sc)
+			if scTpl == *sc {
+				scTpl, err = values.Add(*sc, name, "volumeClaimTemplates", claimName, "storageClass")
+				if err != nil {
+					return "", err
+				}
+			}
+			err = unstructured.SetNestedField(claim, scTpl, "spec", "storageClassName")
+			if err != nil {
+				return "", err
+			}
 		}
+		res[i] = claim
 	}
-	c.Env = append(c.Env, corev1.EnvVar{
-		Name:  cluster.DomainEnv,
-		Value: fmt.Sprintf("{{ .Values.%s }}", cluster.DomainKey),
-	})
-	for k, v := range c.Resources.Requests {
-		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "requests", k.String())
-		if err != nil {
-			return c, errors.Wrap(err, "unable to set container resources value")
-		}
+	volumeClaimTemplates, err := yamlformat.Marshal(map[string]interface{}{"volumeClaimTemplates": res}, 2)
+	if err != nil {
+		return "", err
 	}
-	for k, v := range c.Resources.Limits {
-		err = unstructured.SetNestedField(*values, v.ToUnstructured(), name, containerName, "resources", "limits", k.String())
-		if err != nil {
-			return c, errors.Wrap(err, "unable to set container resources value")
-		}
+	// pods use emptyDir volumes instead of claims if persistence is disabled
+	return fmt.Sprintf(volumeClaimTemplatesTempl, processor.UnquoteTemplates(volumeClaimTemplates)), nil
+}
+
+// claimTemplateNames - returns names of volume claim templates.
+func claimTemplateNames(claims []corev1.PersistentVolumeClaim) []string {
+	res := make([]string, len(claims))
+	for i, claim := range claims {
+		res[i] = claim.Name
 	}
-	return c, nil
+	return res
 }
 
 type result struct {
 	data struct {
-		Meta           string
-		Replicas       string
-		Selector       string
-		PodLabels      string
-		PodAnnotations string
-		Spec           string
+		Meta                 string
+		Replicas             string
+		Fields               string
+		Selector             string
+		PodLabels            string
+		PodAnnotations       string
+		Spec                 string
 		VolumeClaimTemplates string
 	}
 	values helmify.Values
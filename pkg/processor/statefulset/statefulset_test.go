@@ -1,16 +1,24 @@
 package statefulset
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/processor"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 const (
-	  strStatefl = `apiVersion: apps/v1
+	strStatefl = `apiVersion: apps/v1
 kind: StatefulSet
 metadata:
   name: redis
@@ -64,3 +72,222 @@ func Test_statefulset_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_statefulset_Process_restartAnnotation(t *testing.T) {
+	var testInstance statefulset
+
+	t.Run("enabled", func(t *testing.T) {
+		obj := internal.GenerateObj(strStatefl)
+		appMeta := metadata.New(config.Config{ChartName: "chart-name", RestartAnnotation: true})
+		_, tpl, err := testInstance.Process(appMeta, obj)
+		assert.NoError(t, err)
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		assert.Contains(t, buf.String(), `      annotations:
+        {{- with .Values.redis.restartedAt }}
+        kubectl.kubernetes.io/restartedAt: {{ . | quote }}
+        {{- end }}`)
+		assert.Contains(t, tpl.Values()["redis"], "restartedAt")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		obj := internal.GenerateObj(strStatefl)
+		_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+		assert.NoError(t, err)
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		assert.NotContains(t, buf.String(), "restartedAt")
+	})
+}
+
+const (
+	strStorageClass = `apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: my-app-fast
+provisioner: kubernetes.io/no-provisioner`
+	strStatefulVCT = `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-app-db
+spec:
+  serviceName: db
+  selector:
+    matchLabels:
+      app: db
+  template:
+    metadata:
+      labels:
+        app: db
+    spec:
+      containers:
+      - name: db
+        image: postgres:14
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      accessModes: [ "ReadWriteOnce" ]
+      storageClassName: my-app-fast
+      resources:
+        requests:
+          storage: 1Gi`
+)
+
+func Test_statefulset_Process_storageClass(t *testing.T) {
+	var testInstance statefulset
+	sc := internal.GenerateObj(strStorageClass)
+	obj := internal.GenerateObj(strStatefulVCT)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(sc)
+	appMeta.Load(obj)
+
+	_, scTpl, err := processor.Default().Process(appMeta, sc)
+	assert.NoError(t, err)
+	scBuf := bytes.Buffer{}
+	assert.NoError(t, scTpl.Write(&scBuf))
+	templatedName := `{{ include "chart-name.fullname" . }}-fast`
+	assert.Contains(t, scBuf.String(), "name: "+templatedName)
+
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.StatefulSet{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	claim := res.Spec.VolumeClaimTemplates[0]
+	assert.Equal(t, "release-fast", *claim.Spec.StorageClassName)
+	assert.Equal(t, resource.MustParse("1Gi"), claim.Spec.Resources.Requests[corev1.ResourceStorage])
+}
+
+func Test_statefulset_Process_replicaCount(t *testing.T) {
+	var testInstance statefulset
+	obj := internal.GenerateObj(strStatefl)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name", ReplicaCount: true})
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tpl.Values()["replicaCount"])
+	assert.NotContains(t, tpl.Values()["redis"], "replicas")
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.Contains(t, buf.String(), "replicas: {{ .Values.replicaCount }}")
+}
+
+func Test_statefulset_Process_matchExpressionsSelector(t *testing.T) {
+	var testInstance statefulset
+	obj := internal.GenerateObj(`apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: redis
+spec:
+  serviceName: redis
+  selector:
+    matchExpressions:
+    - key: app
+      operator: In
+      values:
+      - redis
+  template:
+    metadata:
+      labels:
+        app: redis
+    spec:
+      containers:
+      - name: redis
+        image: redis:6`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.NotContains(t, buf.String(), "matchLabels: null")
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.StatefulSet{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, map[string]string{"app.kubernetes.io/instance": "release"}, res.Spec.Selector.MatchLabels)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"redis"}}}, res.Spec.Selector.MatchExpressions)
+}
+
+func Test_statefulset_Process_volumeClaimTemplates(t *testing.T) {
+	var testInstance statefulset
+	obj := internal.GenerateObj(strStatefulVCT)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(obj)
+
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{
+		"size":         "1Gi",
+		"storageClass": "my-app-fast",
+	}}, tpl.Values()["myAppDb"].(map[string]interface{})["volumeClaimTemplates"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.NotContains(t, buf.String(), "creationTimestamp")
+	tpl.Values()["myAppDb"].(map[string]interface{})["volumeClaimTemplates"] = map[string]interface{}{"data": map[string]interface{}{
+		"size":         "5Gi",
+		"storageClass": "standard",
+	}}
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.StatefulSet{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	claim := res.Spec.VolumeClaimTemplates[0]
+	assert.Equal(t, "data", claim.Name)
+	assert.Equal(t, "standard", *claim.Spec.StorageClassName)
+	assert.Equal(t, resource.MustParse("5Gi"), claim.Spec.Resources.Requests[corev1.ResourceStorage])
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, claim.Spec.AccessModes)
+}
+
+func Test_statefulset_Process_specFields(t *testing.T) {
+	var testInstance statefulset
+	obj := internal.GenerateObj(strStatefl + `
+  podManagementPolicy: Parallel`)
+	appMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	appMeta.Load(obj)
+	appMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: redis`))
+	_, tpl, err := testInstance.Process(appMeta, obj)
+	assert.NoError(t, err)
+	redis := tpl.Values()["redis"].(map[string]interface{})
+	assert.Equal(t, "Parallel", redis["podManagementPolicy"])
+	assert.Equal(t, map[string]interface{}{"type": "RollingUpdate"}, redis["updateStrategy"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	redis["updateStrategy"] = map[string]interface{}{"type": "OnDelete"}
+	tpl.Values()["kubernetesClusterDomain"] = "cluster.local"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.StatefulSet{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Equal(t, "release-redis", res.Spec.ServiceName)
+	assert.Equal(t, appsv1.ParallelPodManagement, res.Spec.PodManagementPolicy)
+	assert.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, res.Spec.UpdateStrategy.Type)
+}
+
+func Test_statefulset_Process_persistenceDisabled(t *testing.T) {
+	var testInstance statefulset
+	obj := internal.GenerateObj(strStatefl)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"enabled": true}, tpl.Values()["persistence"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	tpl.Values()["persistence"] = map[string]interface{}{"enabled": false}
+	tpl.Values()["kubernetesClusterDomain"] = "cluster.local"
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := appsv1.StatefulSet{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Empty(t, res.Spec.VolumeClaimTemplates)
+	assert.Len(t, res.Spec.Template.Spec.Volumes, 1)
+	assert.Equal(t, "redis-data", res.Spec.Template.Spec.Volumes[0].Name)
+	assert.NotNil(t, res.Spec.Template.Spec.Volumes[0].EmptyDir)
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"fmt"
 	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/processor"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
@@ -16,8 +17,10 @@ import (
 )
 
 var pvcTempl, _ = template.New("pvc").Parse(
-	`{{ .Meta }}
-{{ .Spec }}`)
+	`{{ "{{- if .Values.persistence.enabled }}" }}
+{{ .Meta }}
+{{ .Spec }}
+{{ "{{- end }}" }}`)
 
 var pvcGVC = schema.GroupVersionKind{
 	Group:   "",
@@ -44,7 +47,8 @@ func (p pvc) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 
 	name := appMeta.TrimName(obj.GetName())
 	nameCamelCase := strcase.ToLowerCamel(name)
-	values := helmify.Values{}
+	// all chart PVCs are created only if persistence is enabled
+	values := helmify.Values{"persistence": map[string]interface{}{"enabled": true}}
 
 	claim := corev1.PersistentVolumeClaim{}
 	err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &claim)
@@ -67,6 +71,18 @@ func (p pvc) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 		return true, nil, err
 	}
 
+	if len(claim.Spec.AccessModes) != 0 {
+		accessModes := make([]interface{}, len(claim.Spec.AccessModes))
+		for i, m := range claim.Spec.AccessModes {
+			accessModes[i] = string(m)
+		}
+		err = unstructured.SetNestedSlice(values, accessModes, "pvc", nameCamelCase, "accessModes")
+		if err != nil {
+			return true, nil, err
+		}
+		specMap["accessModes"] = fmt.Sprintf("{{- toYaml .Values.pvc.%s.accessModes | nindent 4 }}", nameCamelCase)
+	}
+
 	storageReq, ok, _ := unstructured.NestedString(specMap, "resources", "requests", "storage")
 	if ok {
 		templatedStorageReq, err := values.Add(storageReq, "pvc", nameCamelCase, "storageRequest")
@@ -1,9 +1,16 @@
 package storage
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -39,3 +46,34 @@ func Test_PVC_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_PVC_Process_values(t *testing.T) {
+	var testInstance pvc
+	obj := internal.GenerateObj(pvcYaml)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{
+		"persistence": map[string]interface{}{"enabled": true},
+		"pvc": map[string]interface{}{"taskPvClaim": map[string]interface{}{
+			"accessModes":    []interface{}{"ReadWriteOnce"},
+			"storageClass":   "manual",
+			"storageRequest": "3Gi",
+			"storageLimit":   "5Gi",
+		}},
+	}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := corev1.PersistentVolumeClaim{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, res.Spec.AccessModes)
+	assert.Equal(t, "manual", *res.Spec.StorageClassName)
+	assert.Equal(t, resource.MustParse("3Gi"), res.Spec.Resources.Requests[corev1.ResourceStorage])
+
+	tpl.Values()["persistence"] = map[string]interface{}{"enabled": false}
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
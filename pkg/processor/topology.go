@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// temporary matchLabels entry replaced with chart selectorLabels include.
+const (
+	selectorLabelsMarkKey = "helmify-selector-labels"
+	selectorLabelsMarkVal = "include"
+	selectorLabelsMark    = selectorLabelsMarkKey + ": " + selectorLabelsMarkVal
+)
+
+// topologySpreadTempl - topologySpreadConstraints taken from values. Chart selector labels are added to constraints
+// selecting pods by labels. %[1]s - indent, %[2]s - value path, %[3]s - chart name, %[4]d - constraint content indent.
+const topologySpreadTempl = `
+%[1]s{{- with .Values.%[2]s.topologySpreadConstraints }}
+%[1]stopologySpreadConstraints:
+%[1]s{{- range . }}
+%[1]s{{- if dig "labelSelector" "matchLabels" nil . }}
+%[1]s- {{- toYaml (merge (dict "labelSelector" (dict "matchLabels" (include "%[3]s.selectorLabels" $ | fromYaml))) .) | nindent %[4]d }}
+%[1]s{{- else }}
+%[1]s- {{- toYaml . | nindent %[4]d }}
+%[1]s{{- end }}
+%[1]s{{- end }}
+%[1]s{{- end }}`
+
+// TemplateTopologySpread - moves pod spec topologySpreadConstraints to <name>.topologySpreadConstraints value.
+// Field is removed from pod spec. Returned template has to be appended to pod spec marshaled with given indent.
+// Charts without values get constraints as is, because values are not inlined into range over the constraints.
+func TemplateTopologySpread(appMeta helmify.AppMetadata, name string, podSpec map[string]interface{}, indent int, values *helmify.Values) (string, error) {
+	constraints, ok := podSpec["topologySpreadConstraints"]
+	if !ok {
+		if !appMeta.Config().SchedulingValues {
+			return "", nil
+		}
+		constraints = []interface{}{}
+	}
+	delete(podSpec, "topologySpreadConstraints")
+	if appMeta.Config().NoValues {
+		return topologySpreadLiteral(appMeta.ChartName(), constraints, indent)
+	}
+	_, err := values.Add(constraints, name, "topologySpreadConstraints")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(topologySpreadTempl, strings.Repeat(" ", indent), name, appMeta.ChartName(), indent+2), nil
+}
+
+// topologySpreadLiteral - returns topologySpreadConstraints with chart selector labels added to constraints
+// selecting pods by labels.
+func topologySpreadLiteral(chartName string, constraints interface{}, indent int) (string, error) {
+	list, _ := constraints.([]interface{})
+	if len(list) == 0 {
+		return "", nil
+	}
+	for _, c := range list {
+		constraint, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if matchLabels, _, _ := unstructured.NestedMap(constraint, "labelSelector", "matchLabels"); len(matchLabels) == 0 {
+			continue
+		}
+		err := MarkSelectorLabels(constraint, "labelSelector", "matchLabels")
+		if err != nil {
+			return "", err
+		}
+	}
+	res, err := yamlformat.Marshal(map[string]interface{}{"topologySpreadConstraints": list}, indent)
+	if err != nil {
+		return "", err
+	}
+	return "\n" + ReplaceSelectorLabelsMark(res, chartName, indent+6), nil
+}
+
+// MarkSelectorLabels - adds a mark to matchLabels map with given path. The mark has to be replaced
+// with ReplaceSelectorLabelsMark after marshaling.
+func MarkSelectorLabels(obj map[string]interface{}, matchLabelsPath ...string) error {
+	return unstructured.SetNestedField(obj, selectorLabelsMarkVal, append(matchLabelsPath, selectorLabelsMarkKey)...)
+}
+
+// ReplaceSelectorLabelsMark - replaces marks added by MarkSelectorLabels with chart selectorLabels include.
+// entriesIndent is the indent of marked matchLabels entries.
+func ReplaceSelectorLabelsMark(yaml, chartName string, entriesIndent int) string {
+	return strings.ReplaceAll(yaml, selectorLabelsMark,
+		fmt.Sprintf(`{{- include "%s.selectorLabels" . | nindent %d }}`, chartName, entriesIndent))
+}
@@ -0,0 +1,58 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/arttor/helmify/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestTemplateTopologySpread(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"restartPolicy": "Always",
+		"topologySpreadConstraints": []interface{}{
+			map[string]interface{}{
+				"maxSkew":     int64(1),
+				"topologyKey": "zone",
+				"labelSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "web"},
+				},
+			},
+			map[string]interface{}{
+				"maxSkew":     int64(1),
+				"topologyKey": "node",
+			},
+		},
+	}
+	values := helmify.Values{}
+	tpl, err := TemplateTopologySpread(metadata.New(config.Config{ChartName: "chart-name"}), "web", podSpec, 0, &values)
+	assert.NoError(t, err)
+	assert.NotContains(t, podSpec, "topologySpreadConstraints")
+	assert.Len(t, values["web"].(map[string]interface{})["topologySpreadConstraints"], 2)
+
+	spec, err := yaml.Marshal(podSpec, 0)
+	assert.NoError(t, err)
+	rendered, err := internal.RenderTemplate("chart-name", spec+tpl, values)
+	assert.NoError(t, err)
+	res := corev1.PodSpec{}
+	assert.NoError(t, sigsyaml.UnmarshalStrict([]byte(rendered), &res))
+	assert.Len(t, res.TopologySpreadConstraints, 2)
+	assert.Equal(t, map[string]string{"app": "web", "app.kubernetes.io/instance": "release"}, res.TopologySpreadConstraints[0].LabelSelector.MatchLabels)
+	assert.Equal(t, "zone", res.TopologySpreadConstraints[0].TopologyKey)
+	assert.Nil(t, res.TopologySpreadConstraints[1].LabelSelector)
+	assert.Equal(t, "node", res.TopologySpreadConstraints[1].TopologyKey)
+}
+
+func TestTemplateTopologySpread_missing(t *testing.T) {
+	values := helmify.Values{}
+	tpl, err := TemplateTopologySpread(metadata.New(config.Config{}), "web", map[string]interface{}{}, 6, &values)
+	assert.NoError(t, err)
+	assert.Empty(t, tpl)
+	assert.Empty(t, values)
+}
@@ -0,0 +1,158 @@
+package traefik
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// traefikTempl - %[1]s - metadata, %[2]s - spec.
+const traefikTempl = `{{- if .Values.traefik.enabled }}
+%[1]s
+%[2]s
+{{- end }}`
+
+// middlewareSpecTempl - %[1]s - values name.
+const middlewareSpecTempl = `spec:
+  {{- toYaml .Values.traefik.%[1]s.middleware | nindent 2 }}`
+
+// traefikGroups - API groups of Traefik v2 and v3 CRDs.
+var traefikGroups = map[string]bool{"traefik.io": true, "traefik.containo.us": true}
+
+// hostRegexp - matches Host rule with a single host, e.g. Host(`example.com`).
+var hostRegexp = regexp.MustCompile("Host\\(`([^`]+)`\\)")
+
+// New creates processor for Traefik IngressRoute and Middleware resources.
+func New() helmify.Processor {
+	return &traefik{}
+}
+
+type traefik struct{}
+
+// Process Traefik object into template. Returns false if not capable of processing given resource type.
+// IngressRoute hosts and Middleware options are moved to traefik.<name>.* values. Templates are created if
+// traefik.enabled value is set.
+func (t traefik) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	gvk := obj.GroupVersionKind()
+	if !traefikGroups[gvk.Group] || (gvk.Kind != "IngressRoute" && gvk.Kind != "Middleware") {
+		return false, nil, nil
+	}
+	meta, err := processor.ProcessObjMeta(appMeta, obj)
+	if err != nil {
+		return true, nil, err
+	}
+	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "unable to get %s spec", gvk.Kind)
+	}
+	values := helmify.Values{"traefik": map[string]interface{}{"enabled": true}}
+
+	var specStr string
+	if gvk.Kind == "Middleware" {
+		err = unstructured.SetNestedMap(values, spec, "traefik", nameCamel, "middleware")
+		if err != nil {
+			return true, nil, err
+		}
+		specStr = fmt.Sprintf(middlewareSpecTempl, nameCamel)
+	} else {
+		err = processIngressRoute(appMeta, nameCamel, spec, &values)
+		if err != nil {
+			return true, nil, err
+		}
+		specStr, err = yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+		if err != nil {
+			return true, nil, err
+		}
+		specStr = processor.UnquoteTemplates(specStr)
+	}
+	return true, &result{
+		name:   name + ".yaml",
+		data:   []byte(fmt.Sprintf(traefikTempl, meta, specStr)),
+		values: values,
+	}, nil
+}
+
+// processIngressRoute - moves hosts of routes match rules to values and templates chart services, middlewares and tls secret.
+func processIngressRoute(appMeta helmify.AppMetadata, nameCamel string, spec map[string]interface{}, values *helmify.Values) error {
+	hostKeys := map[string]string{}
+	routes, _ := spec["routes"].([]interface{})
+	for _, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if match, ok := route["match"].(string); ok {
+			var err error
+			route["match"] = hostRegexp.ReplaceAllStringFunc(match, func(hostRule string) string {
+				host := hostRegexp.FindStringSubmatch(hostRule)[1]
+				key, ok := hostKeys[host]
+				if !ok {
+					key = "host"
+					if len(hostKeys) != 0 {
+						key += strconv.Itoa(len(hostKeys) + 1)
+					}
+					hostKeys[host] = key
+					if setErr := unstructured.SetNestedField(*values, host, "traefik", nameCamel, key); setErr != nil {
+						err = setErr
+					}
+				}
+				return fmt.Sprintf("Host(`{{ .Values.traefik.%s.%s }}`)", nameCamel, key)
+			})
+			if err != nil {
+				return errors.Wrap(err, "unable to set route host value")
+			}
+		}
+		for _, field := range []string{"services", "middlewares"} {
+			refs, _ := route[field].([]interface{})
+			for _, rf := range refs {
+				ref, ok := rf.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				refName, ok := ref["name"].(string)
+				if !ok {
+					continue
+				}
+				if templated := appMeta.TemplatedName(refName); templated != refName {
+					ref["name"] = templated
+					if ns, ok := ref["namespace"].(string); ok && ns != "" {
+						ref["namespace"] = appMeta.TemplatedNamespace(ns)
+					}
+				}
+			}
+		}
+	}
+	if secretName, ok, _ := unstructured.NestedString(spec, "tls", "secretName"); ok {
+		return unstructured.SetNestedField(spec, appMeta.TemplatedName(secretName), "tls", "secretName")
+	}
+	return nil
+}
+
+type result struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *result) Filename() string {
+	return r.name
+}
+
+func (r *result) Values() helmify.Values {
+	return r.values
+}
+
+func (r *result) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
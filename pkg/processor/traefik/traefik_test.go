@@ -0,0 +1,143 @@
+package traefik
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	ingressRouteYaml = "apiVersion: traefik.io/v1alpha1\n" +
+		"kind: IngressRoute\n" +
+		"metadata:\n" +
+		"  name: my-app-web\n" +
+		"  namespace: my-app\n" +
+		"spec:\n" +
+		"  entryPoints:\n" +
+		"  - websecure\n" +
+		"  routes:\n" +
+		"  - kind: Rule\n" +
+		"    match: Host(`app.example.com`) && PathPrefix(`/api`)\n" +
+		"    middlewares:\n" +
+		"    - name: my-app-strip\n" +
+		"      namespace: my-app\n" +
+		"    services:\n" +
+		"    - name: my-app-api\n" +
+		"      port: 80\n" +
+		"  - kind: Rule\n" +
+		"    match: Host(`app.example.com`)\n" +
+		"    services:\n" +
+		"    - name: my-app-ui\n" +
+		"      port: 80\n" +
+		"  tls:\n" +
+		"    secretName: app-tls"
+	middlewareYaml = `apiVersion: traefik.io/v1alpha1
+kind: Middleware
+metadata:
+  name: my-app-strip
+  namespace: my-app
+spec:
+  stripPrefix:
+    prefixes:
+    - /api`
+)
+
+func Test_traefik_Process(t *testing.T) {
+	t.Run("processed", func(t *testing.T) {
+		for _, obj := range []string{ingressRouteYaml, middlewareYaml} {
+			processed, _, err := New().Process(&metadata.Service{}, internal.GenerateObj(obj))
+			assert.NoError(t, err)
+			assert.Equal(t, true, processed)
+		}
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := New().Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+func loadMeta() *metadata.Service {
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(internal.GenerateObj(ingressRouteYaml))
+	testMeta.Load(internal.GenerateObj(middlewareYaml))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-app-api
+  namespace: my-app`))
+	return testMeta
+}
+
+func Test_traefik_Process_ingressRoute(t *testing.T) {
+	_, tpl, err := New().Process(loadMeta(), internal.GenerateObj(ingressRouteYaml))
+	assert.NoError(t, err)
+	values := tpl.Values()
+	traefikValues := values["traefik"].(map[string]interface{})
+	assert.Equal(t, true, traefikValues["enabled"])
+	assert.Equal(t, map[string]interface{}{"host": "app.example.com"}, traefikValues["web"])
+
+	traefikValues["web"] = map[string]interface{}{"host": "app.example.org"}
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	type ref struct {
+		Name      string
+		Namespace string
+		Port      int
+	}
+	var res struct {
+		Spec struct {
+			Routes []struct {
+				Match       string
+				Middlewares []ref
+				Services    []ref
+			}
+			TLS struct {
+				SecretName string `json:"secretName"`
+			} `json:"tls"`
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "Host(`app.example.org`) && PathPrefix(`/api`)", res.Spec.Routes[0].Match)
+	assert.Equal(t, []ref{{Name: "release-strip", Namespace: "ns"}}, res.Spec.Routes[0].Middlewares)
+	assert.Equal(t, []ref{{Name: "release-api", Port: 80}}, res.Spec.Routes[0].Services)
+	assert.Equal(t, "Host(`app.example.org`)", res.Spec.Routes[1].Match)
+	assert.Equal(t, []ref{{Name: "my-app-ui", Port: 80}}, res.Spec.Routes[1].Services)
+	assert.Equal(t, "app-tls", res.Spec.TLS.SecretName)
+
+	traefikValues["enabled"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_traefik_Process_middleware(t *testing.T) {
+	_, tpl, err := New().Process(loadMeta(), internal.GenerateObj(middlewareYaml))
+	assert.NoError(t, err)
+	values := tpl.Values()
+	assert.Equal(t, map[string]interface{}{"middleware": map[string]interface{}{
+		"stripPrefix": map[string]interface{}{"prefixes": []interface{}{"/api"}},
+	}}, values["traefik"].(map[string]interface{})["strip"])
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	var res struct {
+		Metadata struct {
+			Name string
+		}
+		Spec map[string]interface{}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "release-strip", res.Metadata.Name)
+	assert.Equal(t, map[string]interface{}{"stripPrefix": map[string]interface{}{"prefixes": []interface{}{"/api"}}}, res.Spec)
+}
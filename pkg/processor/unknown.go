@@ -0,0 +1,32 @@
+package processor
+
+// KeepUnknownFields copies fields missing in processed object from the original one.
+// Typed k8s API structs drop fields unknown to the vendored API version (e.g. probe grpc action),
+// so such fields are restored from the original unstructured object.
+func KeepUnknownFields(processed, original map[string]interface{}) {
+	for k, origVal := range original {
+		val, ok := processed[k]
+		if !ok {
+			processed[k] = origVal
+			continue
+		}
+		switch typed := val.(type) {
+		case map[string]interface{}:
+			if origMap, ok := origVal.(map[string]interface{}); ok {
+				KeepUnknownFields(typed, origMap)
+			}
+		case []interface{}:
+			origSlice, ok := origVal.([]interface{})
+			if !ok {
+				continue
+			}
+			for i := 0; i < len(typed) && i < len(origSlice); i++ {
+				elem, isMap := typed[i].(map[string]interface{})
+				origElem, isOrigMap := origSlice[i].(map[string]interface{})
+				if isMap && isOrigMap {
+					KeepUnknownFields(elem, origElem)
+				}
+			}
+		}
+	}
+}
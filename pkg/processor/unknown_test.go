@@ -0,0 +1,31 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepUnknownFields(t *testing.T) {
+	processed := map[string]interface{}{
+		"known": "templated",
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "startupProbe": map[string]interface{}{"periodSeconds": int64(10)}},
+		},
+	}
+	original := map[string]interface{}{
+		"known":   "original",
+		"unknown": "value",
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "startupProbe": map[string]interface{}{
+				"periodSeconds": int64(10),
+				"grpc":          map[string]interface{}{"port": int64(9090)},
+			}},
+		},
+	}
+	KeepUnknownFields(processed, original)
+	assert.Equal(t, "templated", processed["known"])
+	assert.Equal(t, "value", processed["unknown"])
+	probe := processed["containers"].([]interface{})[0].(map[string]interface{})["startupProbe"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"port": int64(9090)}, probe["grpc"])
+}
@@ -0,0 +1,40 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// quotedTemplateRegexp - matches single-quoted yaml scalars containing template actions.
+var quotedTemplateRegexp = regexp.MustCompile(`'((?:[^']|'')*?\{\{(?:[^']|'')*)'`)
+
+// templateActionRegexp - matches template actions.
+var templateActionRegexp = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// plainTextRegexp - matches text around template actions which can be a part of plain yaml scalar, e.g. '-svc' or '.svc:8080'.
+var plainTextRegexp = regexp.MustCompile(`^[\w./@:=-]*$`)
+
+// UnquoteTemplates - removes yaml quotes around generated template actions in marshaled yaml, e.g. '{{ .Values.image }}'.
+// Scalars mixing actions with text are unquoted only if the text is valid in a plain scalar, e.g. '{{ .Values.host }}:80'.
+// Other quoted scalars, like '*' or 'a: {{ .Values.a }}', are kept.
+// Template delimiters of source manifests are marked and never matched.
+func UnquoteTemplates(content string) string {
+	return quotedTemplateRegexp.ReplaceAllStringFunc(content, func(quoted string) string {
+		value := strings.ReplaceAll(quoted[1:len(quoted)-1], "''", "'")
+		if !isPlainTemplate(value) {
+			return quoted
+		}
+		return value
+	})
+}
+
+// isPlainTemplate - returns true if value with template actions stays valid yaml without quotes.
+func isPlainTemplate(value string) bool {
+	if !strings.HasPrefix(value, "{{") && strings.ContainsAny(value[:1], ":@") {
+		return false
+	}
+	if !strings.HasSuffix(value, "}}") && strings.HasSuffix(value, ":") {
+		return false
+	}
+	return plainTextRegexp.MatchString(templateActionRegexp.ReplaceAllString(value, ""))
+}
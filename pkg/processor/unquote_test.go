@@ -0,0 +1,30 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnquoteTemplates(t *testing.T) {
+	for name, tt := range map[string]struct {
+		in, want string
+	}{
+		"template":          {in: "image: '{{ .Values.image }}'", want: "image: {{ .Values.image }}"},
+		"template in text":  {in: "key: 'a: {{ .Values.a }}'", want: "key: 'a: {{ .Values.a }}'"},
+		"name suffix":       {in: `name: '{{ include "x.fullname" . }}-svc'`, want: `name: {{ include "x.fullname" . }}-svc`},
+		"host and port":     {in: "host: '{{ .Values.host }}.svc:80'", want: "host: {{ .Values.host }}.svc:80"},
+		"trailing colon":    {in: "key: '{{ .Values.a }}:'", want: "key: '{{ .Values.a }}:'"},
+		"leading colon":     {in: "key: ':{{ .Values.a }}'", want: "key: ':{{ .Values.a }}'"},
+		"two actions":       {in: "key: '{{ .Values.a }} {{ .Values.b }}'", want: "key: '{{ .Values.a }} {{ .Values.b }}'"},
+		"escaped quote":     {in: `key: '{{ include "x" . | default ''a'' }}'`, want: `key: {{ include "x" . | default 'a' }}`},
+		"wildcard":          {in: "hosts:\n- '*'\n- '{{ .Values.host }}'", want: "hosts:\n- '*'\n- {{ .Values.host }}"},
+		"flow sequence":     {in: "resources: ['*', '{{ .Values.r }}']", want: "resources: ['*', {{ .Values.r }}]"},
+		"command":           {in: "- echo 'it''s' done\n- '*'", want: "- echo 'it''s' done\n- '*'"},
+		"empty and wrapped": {in: "a: ''\nb: '{{ .Values.b\n  }}'", want: "a: ''\nb: {{ .Values.b\n  }}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, UnquoteTemplates(tt.in))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// apiServiceTempl - APIService name is kept as is because it must be in form '<version>.<group>'.
+const apiServiceTempl = `apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: %[2]s%[3]s
+  labels:
+  {{- include "%[1]s.labels" . | nindent 4 }}
+%[4]s`
+
+var apiServiceGVK = schema.GroupVersionKind{
+	Group:   "apiregistration.k8s.io",
+	Version: "v1",
+	Kind:    "APIService",
+}
+
+// APIService creates processor for aggregated API server APIService resource.
+func APIService() helmify.Processor {
+	return &apiService{}
+}
+
+type apiService struct{}
+
+// Process APIService object into template. Returns false if not capable of processing given resource type.
+// Backing service reference is templated. caBundle is moved to values unless it is injected by cert-manager.
+func (a apiService) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
+	if obj.GroupVersionKind() != apiServiceGVK {
+		return false, nil, nil
+	}
+	name := obj.GetName()
+	nameCamel := strcase.ToLowerCamel(name)
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return true, nil, errors.Wrap(err, "unable to get APIService spec")
+	}
+	if svcName, ok, _ := unstructured.NestedString(spec, "service", "name"); ok {
+		templatedName := appMeta.TemplatedName(svcName)
+		err = unstructured.SetNestedField(spec, templatedName, "service", "name")
+		if err != nil {
+			return true, nil, err
+		}
+		if templatedName != svcName {
+			svcNs, _, _ := unstructured.NestedString(spec, "service", "namespace")
+			err = unstructured.SetNestedField(spec, appMeta.TemplatedNamespace(svcNs), "service", "namespace")
+			if err != nil {
+				return true, nil, err
+			}
+		}
+	}
+	annotations := certManagerAnnotations(appMeta, obj)
+	values := helmify.Values{}
+	caBundle := ""
+	if appMeta.Config().GenWebhookCerts {
+		caBundle = processor.CABundle(appMeta, obj)
+	}
+	if caBundle != "" {
+		// CA is generated by Helm
+		spec["caBundle"] = caBundlePlaceholder
+	} else if annotations != "" {
+		// caBundle is injected by cert-manager
+		delete(spec, "caBundle")
+	} else if caBundle, ok := spec["caBundle"].(string); ok {
+		spec["caBundle"], err = values.Add(caBundle, nameCamel, "caBundle")
+		if err != nil {
+			return true, nil, err
+		}
+	}
+	specYaml, err := yamlformat.Marshal(map[string]interface{}{"spec": spec}, 0)
+	if err != nil {
+		return true, nil, err
+	}
+	specYaml = processor.UnquoteTemplates(specYaml)
+	specYaml = strings.ReplaceAll(specYaml, caBundlePlaceholder, caBundle)
+	return true, &apiServiceResult{
+		name:   name,
+		data:   []byte(fmt.Sprintf(apiServiceTempl, appMeta.ChartName(), name, annotations, specYaml)),
+		values: values,
+	}, nil
+}
+
+type apiServiceResult struct {
+	name   string
+	data   []byte
+	values helmify.Values
+}
+
+func (r *apiServiceResult) Filename() string {
+	return r.name + ".yaml"
+}
+
+func (r *apiServiceResult) Values() helmify.Values {
+	return r.values
+}
+
+func (r *apiServiceResult) Write(writer io.Writer) error {
+	_, err := writer.Write(r.data)
+	return err
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arttor/helmify/internal"
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+const apiServiceYaml = `apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.example.com
+spec:
+  group: metrics.example.com
+  version: v1beta1
+  groupPriorityMinimum: 100
+  versionPriority: 100
+  caBundle: Y2EtYnVuZGxl
+  service:
+    name: my-operator-metrics-api
+    namespace: my-operator-system
+    port: 443`
+
+func Test_apiService_Process(t *testing.T) {
+	var testInstance apiService
+
+	t.Run("processed", func(t *testing.T) {
+		processed, _, err := testInstance.Process(&metadata.Service{}, internal.GenerateObj(apiServiceYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, true, processed)
+	})
+	t.Run("skipped", func(t *testing.T) {
+		processed, _, err := testInstance.Process(&metadata.Service{}, internal.TestNs)
+		assert.NoError(t, err)
+		assert.Equal(t, false, processed)
+	})
+}
+
+type renderedAPIService struct {
+	Metadata struct {
+		Name        string
+		Annotations map[string]string
+	}
+	Spec struct {
+		CABundle string `json:"caBundle"`
+		Service  struct {
+			Name      string
+			Namespace string
+			Port      int
+		}
+	}
+}
+
+func Test_apiService_Process_values(t *testing.T) {
+	var testInstance apiService
+	load := func(obj string) *metadata.Service {
+		testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+		testMeta.Load(internal.GenerateObj(obj))
+		testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-metrics-api
+  namespace: my-operator-system`))
+		return testMeta
+	}
+	t.Run("caBundle", func(t *testing.T) {
+		_, tpl, err := testInstance.Process(load(apiServiceYaml), internal.GenerateObj(apiServiceYaml))
+		assert.NoError(t, err)
+		assert.Equal(t, helmify.Values{"v1Beta1MetricsExampleCom": map[string]interface{}{"caBundle": "Y2EtYnVuZGxl"}}, tpl.Values())
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+		assert.NoError(t, err)
+		res := renderedAPIService{}
+		assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+		assert.Equal(t, "v1beta1.metrics.example.com", res.Metadata.Name)
+		assert.Equal(t, "Y2EtYnVuZGxl", res.Spec.CABundle)
+		assert.Equal(t, "release-my-operator-metrics-api", res.Spec.Service.Name)
+		assert.Equal(t, "ns", res.Spec.Service.Namespace)
+		assert.Equal(t, 443, res.Spec.Service.Port)
+	})
+	t.Run("cert-manager", func(t *testing.T) {
+		obj := internal.GenerateObj(apiServiceYaml)
+		obj.SetAnnotations(map[string]string{"cert-manager.io/inject-ca-from": "my-operator-system/my-operator-serving-cert"})
+		_, tpl, err := testInstance.Process(load(apiServiceYaml), obj)
+		assert.NoError(t, err)
+		assert.Equal(t, helmify.Values{}, tpl.Values())
+		buf := bytes.Buffer{}
+		assert.NoError(t, tpl.Write(&buf))
+		rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+		assert.NoError(t, err)
+		res := renderedAPIService{}
+		assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+		assert.Equal(t, "ns/release-my-operator-serving-cert", res.Metadata.Annotations["cert-manager.io/inject-ca-from"])
+		assert.Empty(t, res.Spec.CABundle)
+	})
+}
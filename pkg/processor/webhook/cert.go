@@ -8,7 +8,9 @@ import (
 
 	"github.com/arttor/helmify/pkg/cluster"
 	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
 	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,16 +18,52 @@ import (
 )
 
 const (
-	certTempl = `apiVersion: cert-manager.io/v1
+	certTempl = `{{- if .Values.certmanager.enabled }}
+apiVersion: cert-manager.io/v1
 kind: Certificate
 metadata:
   name: {{ include "%[1]s.fullname" . }}-%[2]s
   labels:
   {{- include "%[1]s.labels" . | nindent 4 }}
 spec:
-%[3]s`
+%[3]s
+  {{- with .Values.certmanager.%[4]s.duration }}
+  duration: {{ . }}
+  {{- end }}
+  {{- with .Values.certmanager.%[4]s.renewBefore }}
+  renewBefore: {{ . }}
+  {{- end }}
+{{- end }}`
 )
 
+// genCertsTempl - Certificate replacement with certificates generated by Helm. Certificates are generated once per
+// render and cached in the root context to be shared with templates injecting CA. Existing secret is reused on upgrade.
+// %[1]s - chart name, %[2]s - helper name, %[3]s - templated secret name, %[4]s - quoted dns names templates.
+const genCertsTempl = `{{- define "%[2]s" -}}
+{{- if not (hasKey $ "%[2]s") }}
+{{- $secretName := tpl ` + "`%[3]s`" + ` $ }}
+{{- $data := get (lookup "v1" "Secret" $.Release.Namespace $secretName) "data" | default dict }}
+{{- if hasKey $data "ca.crt" }}
+{{- $_ := set $ "%[2]s" (pick $data "ca.crt" "tls.crt" "tls.key") }}
+{{- else }}
+{{- $altNames := list %[4]s }}
+{{- $ca := genCA (printf "%%s-ca" $secretName) 3650 }}
+{{- $cert := genSignedCert (first $altNames) nil $altNames 3650 $ca }}
+{{- $_ := set $ "%[2]s" (dict "ca.crt" (b64enc $ca.Cert) "tls.crt" (b64enc $cert.Cert) "tls.key" (b64enc $cert.Key)) }}
+{{- end }}
+{{- end }}
+{{- toYaml (get $ "%[2]s") }}
+{{- end }}
+apiVersion: v1
+kind: Secret
+type: kubernetes.io/tls
+metadata:
+  name: %[3]s
+  labels:
+  {{- include "%[1]s.labels" . | nindent 4 }}
+data:
+  {{- include "%[2]s" . | nindent 2 }}`
+
 var certGVC = schema.GroupVersionKind{
 	Group:   "cert-manager.io",
 	Version: "v1",
@@ -40,11 +78,14 @@ func Certificate() helmify.Processor {
 type cert struct{}
 
 // Process k8s Certificate object into template. Returns false if not capable of processing given resource type.
+// Certificate is created if certmanager.enabled value is set. Duration and renewBefore are moved to values.
+// If certificates are generated by Helm, the Certificate is replaced with a TLS Secret and a helper generating it.
 func (c cert) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
 	if obj.GroupVersionKind() != certGVC {
 		return false, nil, nil
 	}
 	name := appMeta.TrimName(obj.GetName())
+	nameCamel := strcase.ToLowerCamel(name)
 
 	dnsNames, _, err := unstructured.NestedSlice(obj.Object, "spec", "dnsNames")
 	if err != nil {
@@ -55,7 +96,10 @@ func (c cert) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 	for _, dnsName := range dnsNames {
 		dns := dnsName.(string)
 		templatedDns := appMeta.TemplatedString(dns)
-		processedDns := strings.ReplaceAll(templatedDns, appMeta.Namespace(), "{{ .Release.Namespace }}")
+		processedDns := templatedDns
+		if ns := appMeta.Namespace(); ns != "" {
+			processedDns = strings.ReplaceAll(processedDns, ns, appMeta.TemplatedNamespace(ns))
+		}
 		processedDns = strings.ReplaceAll(processedDns, cluster.DefaultDomain, fmt.Sprintf("{{ .Values.%s }}", cluster.DomainKey))
 		processedDnsNames = append(processedDnsNames, processedDns)
 	}
@@ -73,19 +117,58 @@ func (c cert) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructure
 	if err != nil {
 		return true, nil, errors.Wrap(err, "unable set cert issuerRef")
 	}
+	secretName, ok, _ := unstructured.NestedString(obj.Object, "spec", "secretName")
+	if ok {
+		secretName = appMeta.TemplatedName(secretName)
+		err = unstructured.SetNestedField(obj.Object, secretName, "spec", "secretName")
+		if err != nil {
+			return true, nil, errors.Wrap(err, "unable set cert secretName")
+		}
+	}
+	if appMeta.Config().GenWebhookCerts {
+		if secretName == "" {
+			return true, nil, errors.Errorf("unable to generate certificate %s: secretName is not set", obj.GetName())
+		}
+		altNames := make([]string, len(processedDnsNames))
+		for i, dns := range processedDnsNames {
+			altNames[i] = "(tpl `" + dns.(string) + "` $)"
+		}
+		res := fmt.Sprintf(genCertsTempl, appMeta.ChartName(), processor.CertsHelperName(appMeta, obj.GetName()), secretName, strings.Join(altNames, " "))
+		return true, &certResult{
+			name:   name,
+			data:   []byte(res),
+			values: helmify.Values{},
+		}, nil
+	}
+	values := certManagerValues()
+	for _, field := range []string{"duration", "renewBefore"} {
+		value, _, _ := unstructured.NestedString(obj.Object, "spec", field)
+		_, err = values.Add(value, "certmanager", nameCamel, field)
+		if err != nil {
+			return true, nil, err
+		}
+		unstructured.RemoveNestedField(obj.Object, "spec", field)
+	}
 	spec, _ := yaml.Marshal(obj.Object["spec"])
 	spec = yamlformat.Indent(spec, 2)
 	spec = bytes.TrimRight(spec, "\n ")
-	res := fmt.Sprintf(certTempl, appMeta.ChartName(), name, string(spec))
+	res := fmt.Sprintf(certTempl, appMeta.ChartName(), name, string(spec), nameCamel)
 	return true, &certResult{
-		name: name,
-		data: []byte(res),
+		name:   name,
+		data:   []byte(res),
+		values: values,
 	}, nil
 }
 
+// certManagerValues - returns values enabling cert-manager resources.
+func certManagerValues() helmify.Values {
+	return helmify.Values{"certmanager": map[string]interface{}{"enabled": true}}
+}
+
 type certResult struct {
-	name string
-	data []byte
+	name   string
+	data   []byte
+	values helmify.Values
 }
 
 func (r *certResult) Filename() string {
@@ -93,7 +176,7 @@ func (r *certResult) Filename() string {
 }
 
 func (r *certResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *certResult) Write(writer io.Writer) error {
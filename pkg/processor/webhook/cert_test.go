@@ -1,9 +1,16 @@
 package webhook
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -39,3 +46,103 @@ func Test_cert_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_cert_Process_values(t *testing.T) {
+	var testInstance cert
+	obj := internal.GenerateObj(certYaml + `
+  duration: 8760h`)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: my-operator-selfsigned-issuer
+  namespace: my-operator-system`))
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-webhook-service
+  namespace: my-operator-system`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"certmanager": map[string]interface{}{
+		"enabled":     true,
+		"servingCert": map[string]interface{}{"duration": "8760h", "renewBefore": ""},
+	}}, tpl.Values())
+
+	values := tpl.Values()
+	values["kubernetesClusterDomain"] = "cluster.local"
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	var res struct {
+		Spec struct {
+			DNSNames    []string `json:"dnsNames"`
+			Duration    string
+			RenewBefore string `json:"renewBefore"`
+			SecretName  string `json:"secretName"`
+			IssuerRef   struct {
+				Name string
+			} `json:"issuerRef"`
+		}
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "release-webhook-server-cert", res.Spec.SecretName)
+	assert.Equal(t, "release-selfsigned-issuer", res.Spec.IssuerRef.Name)
+	assert.Equal(t, "8760h", res.Spec.Duration)
+	assert.Empty(t, res.Spec.RenewBefore)
+	assert.Equal(t, "release-webhook-service.ns.svc.cluster.local", res.Spec.DNSNames[1])
+
+	values["certmanager"].(map[string]interface{})["enabled"] = false
+	rendered, err = internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(rendered))
+}
+
+func Test_cert_Process_genCerts(t *testing.T) {
+	var testInstance cert
+	obj := internal.GenerateObj(certYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name", GenWebhookCerts: true})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-webhook-service
+  namespace: my-operator-system`))
+	_, certTpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Empty(t, certTpl.Values())
+
+	_, vwhTpl, err := vwh{}.Process(testMeta, internal.GenerateObj(vwhYaml))
+	assert.NoError(t, err)
+
+	processed, issuerTpl, err := issuer{}.Process(testMeta, internal.GenerateObj(issuerYaml))
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Nil(t, issuerTpl)
+
+	values := vwhTpl.Values()
+	values["kubernetesClusterDomain"] = "cluster.local"
+	buf := bytes.Buffer{}
+	assert.NoError(t, certTpl.Write(&buf))
+	buf.WriteString("\n---\n")
+	assert.NoError(t, vwhTpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	docs := strings.Split(rendered, "\n---\n")
+	assert.Len(t, docs, 2)
+
+	var secret corev1.Secret
+	assert.NoError(t, yaml.Unmarshal([]byte(docs[0]), &secret))
+	assert.Equal(t, "release-webhook-server-cert", secret.Name)
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	assert.NotEmpty(t, secret.Data["ca.crt"])
+	assert.NotEmpty(t, secret.Data["tls.crt"])
+	assert.NotEmpty(t, secret.Data["tls.key"])
+
+	var webhook admissionv1.ValidatingWebhookConfiguration
+	assert.NoError(t, yaml.Unmarshal([]byte(docs[1]), &webhook))
+	assert.Empty(t, webhook.Annotations)
+	assert.Equal(t, secret.Data["ca.crt"], webhook.Webhooks[0].ClientConfig.CABundle)
+}
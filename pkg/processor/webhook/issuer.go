@@ -13,14 +13,16 @@ import (
 )
 
 const (
-	issuerTempl = `apiVersion: cert-manager.io/v1
+	issuerTempl = `{{- if .Values.certmanager.enabled }}
+apiVersion: cert-manager.io/v1
 kind: Issuer
 metadata:
   name: {{ include "%[1]s.fullname" . }}-%[2]s
   labels:
   {{- include "%[1]s.labels" . | nindent 4 }}
 spec:
-%[3]s`
+%[3]s
+{{- end }}`
 )
 
 var issuerGVC = schema.GroupVersionKind{
@@ -37,10 +39,15 @@ func Issuer() helmify.Processor {
 type issuer struct{}
 
 // Process k8s Issuer object into template. Returns false if not capable of processing given resource type.
+// Issuer is created if certmanager.enabled value is set.
 func (i issuer) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) (bool, helmify.Template, error) {
 	if obj.GroupVersionKind() != issuerGVC {
 		return false, nil, nil
 	}
+	if appMeta.Config().GenWebhookCerts {
+		// certificates are generated by Helm
+		return true, nil, nil
+	}
 	name := appMeta.TrimName(obj.GetName())
 	spec, _ := yaml.Marshal(obj.Object["spec"])
 	spec = yamlformat.Indent(spec, 2)
@@ -62,7 +69,7 @@ func (r *issResult) Filename() string {
 }
 
 func (r *issResult) Values() helmify.Values {
-	return helmify.Values{}
+	return certManagerValues()
 }
 
 func (r *issResult) Write(writer io.Writer) error {
@@ -1,8 +1,10 @@
 package webhook
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
 	"github.com/arttor/helmify/pkg/metadata"
 
 	"github.com/arttor/helmify/internal"
@@ -54,3 +56,29 @@ func Test_mwh_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_mwh_Process_url(t *testing.T) {
+	var testInstance mwh
+	obj := internal.GenerateObj(`apiVersion: admissionregistration.k8s.io/v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: my-operator-mutating-webhook-configuration
+webhooks:
+- admissionReviewVersions:
+  - v1
+  clientConfig:
+    url: https://webhook.example.com/mutate
+  name: mpod.example.com
+  sideEffects: None`)
+	_, tpl, err := testInstance.Process(metadata.New(config.Config{ChartName: "chart-name"}), obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	assert.NotContains(t, buf.String(), "cert-manager.io/inject-ca-from")
+	assert.Contains(t, buf.String(), "url: https://webhook.example.com/mutate")
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	assert.Contains(t, rendered, "failurePolicy: \"Fail\"\n")
+	assert.Contains(t, rendered, "timeoutSeconds: 10")
+}
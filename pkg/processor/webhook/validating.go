@@ -1,10 +1,8 @@
 package webhook
 
 import (
-	"bytes"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/pkg/errors"
@@ -12,19 +10,15 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"sigs.k8s.io/yaml"
 )
 
 const (
 	vwhTempl = `apiVersion: admissionregistration.k8s.io/v1
 kind: ValidatingWebhookConfiguration
 metadata:
-  name: {{ include "%[1]s.fullname" . }}-%[2]s
-  annotations:
-    cert-manager.io/inject-ca-from: {{ .Release.Namespace }}/{{ include "%[1]s.fullname" . }}-%[3]s
+  name: {{ include "%[1]s.fullname" . }}-%[2]s%[3]s
   labels:
   {{- include "%[1]s.labels" . | nindent 4 }}
-webhooks:
 %[4]s`
 )
 
@@ -53,29 +47,23 @@ func (w vwh) Process(appMeta helmify.AppMetadata, obj *unstructured.Unstructured
 	if err != nil {
 		return true, nil, errors.Wrap(err, "unable to cast to ValidatingWebhookConfiguration")
 	}
-	for i, whc := range whConf.Webhooks {
-		whc.ClientConfig.Service.Name = appMeta.TemplatedName(whc.ClientConfig.Service.Name)
-		whc.ClientConfig.Service.Namespace = strings.ReplaceAll(whc.ClientConfig.Service.Namespace, appMeta.Namespace(), `{{ .Release.Namespace }}`)
-		whConf.Webhooks[i] = whc
-	}
-	webhooks, _ := yaml.Marshal(whConf.Webhooks)
-	webhooks = bytes.TrimRight(webhooks, "\n ")
-	certName, _, err := unstructured.NestedString(obj.Object, "metadata", "annotations", "cert-manager.io/inject-ca-from")
+	values := helmify.Values{}
+	webhooks, err := processWebhooks(appMeta, name, obj, &values)
 	if err != nil {
-		return true, nil, errors.Wrap(err, "unable get webhook certName")
+		return true, nil, err
 	}
-	certName = strings.TrimPrefix(certName, appMeta.Namespace()+"/")
-	certName = appMeta.TrimName(certName)
-	res := fmt.Sprintf(vwhTempl, appMeta.ChartName(), name, certName, string(webhooks))
+	res := fmt.Sprintf(vwhTempl, appMeta.ChartName(), name, certManagerAnnotations(appMeta, obj), webhooks)
 	return true, &vwhResult{
-		name: name,
-		data: []byte(res),
+		name:   name,
+		data:   []byte(res),
+		values: values,
 	}, nil
 }
 
 type vwhResult struct {
-	name string
-	data []byte
+	name   string
+	data   []byte
+	values helmify.Values
 }
 
 func (r *vwhResult) Filename() string {
@@ -83,7 +71,7 @@ func (r *vwhResult) Filename() string {
 }
 
 func (r *vwhResult) Values() helmify.Values {
-	return helmify.Values{}
+	return r.values
 }
 
 func (r *vwhResult) Write(writer io.Writer) error {
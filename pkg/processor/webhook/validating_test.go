@@ -1,9 +1,15 @@
 package webhook
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/arttor/helmify/pkg/config"
+	"github.com/arttor/helmify/pkg/helmify"
 	"github.com/arttor/helmify/pkg/metadata"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/arttor/helmify/internal"
 	"github.com/stretchr/testify/assert"
@@ -54,3 +60,62 @@ func Test_vwh_Process(t *testing.T) {
 		assert.Equal(t, false, processed)
 	})
 }
+
+func Test_vwh_Process_values(t *testing.T) {
+	var testInstance vwh
+	obj := internal.GenerateObj(vwhYaml)
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	testMeta.Load(internal.GenerateObj(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-operator-webhook-service
+  namespace: my-operator-system`))
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+	assert.Equal(t, helmify.Values{"validatingWebhookConfiguration": map[string]interface{}{
+		"vvolumeKbIo": map[string]interface{}{"failurePolicy": "Fail", "timeoutSeconds": int64(10)},
+	}}, tpl.Values())
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	values := tpl.Values()
+	values["validatingWebhookConfiguration"].(map[string]interface{})["vvolumeKbIo"] = map[string]interface{}{"failurePolicy": "Ignore", "timeoutSeconds": int64(5)}
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), values)
+	assert.NoError(t, err)
+	res := admissionv1.ValidatingWebhookConfiguration{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	assert.Equal(t, "ns/release-serving-cert", res.Annotations["cert-manager.io/inject-ca-from"])
+	webhook := res.Webhooks[0]
+	assert.Equal(t, "release-webhook-service", webhook.ClientConfig.Service.Name)
+	assert.Equal(t, "ns", webhook.ClientConfig.Service.Namespace)
+	assert.Equal(t, admissionv1.Ignore, *webhook.FailurePolicy)
+	assert.Equal(t, int32(5), *webhook.TimeoutSeconds)
+}
+
+func Test_vwh_Process_wildcardRules(t *testing.T) {
+	var testInstance vwh
+	obj := internal.GenerateObj(strings.NewReplacer(
+		"- test.example.com", `- "*"`,
+		"- v1alpha1", `- "*"`,
+		"- CREATE\n    - UPDATE", `- "*"`,
+		"- volumes", `- "*"`,
+	).Replace(vwhYaml))
+	testMeta := metadata.New(config.Config{ChartName: "chart-name"})
+	testMeta.Load(obj)
+	_, tpl, err := testInstance.Process(testMeta, obj)
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, tpl.Write(&buf))
+	rendered, err := internal.RenderTemplate("chart-name", buf.String(), tpl.Values())
+	assert.NoError(t, err)
+	res := admissionv1.ValidatingWebhookConfiguration{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rendered), &res))
+	rule := res.Webhooks[0].Rules[0]
+	assert.Equal(t, []string{"*"}, rule.APIGroups)
+	assert.Equal(t, []string{"*"}, rule.APIVersions)
+	assert.Equal(t, []admissionv1.OperationType{admissionv1.OperationAll}, rule.Operations)
+	assert.Equal(t, []string{"*"}, rule.Resources)
+	assert.Equal(t, admissionv1.Fail, *res.Webhooks[0].FailurePolicy)
+}
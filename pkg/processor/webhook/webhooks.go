@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arttor/helmify/pkg/helmify"
+	"github.com/arttor/helmify/pkg/processor"
+	yamlformat "github.com/arttor/helmify/pkg/yaml"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// webhookValueDefaults - webhook fields moved to values with admissionregistration/v1 defaults used for missing fields.
+var webhookValueDefaults = []struct {
+	field string
+	dft   interface{}
+}{
+	{field: "failurePolicy", dft: "Fail"},
+	{field: "timeoutSeconds", dft: int64(10)},
+}
+
+// caBundlePlaceholder - replaced with caBundle template after marshaling to keep the template unquoted.
+const caBundlePlaceholder = "helmify-ca-bundle"
+
+// processWebhooks - templates webhooks client config service and moves failurePolicy and timeoutSeconds
+// to <name>.<webhook name>.* values. If webhook certificates are generated by Helm, generated CA is
+// injected into client config caBundle. Returns webhooks list as yaml.
+func processWebhooks(appMeta helmify.AppMetadata, name string, obj *unstructured.Unstructured, values *helmify.Values) (string, error) {
+	webhooks, _, err := unstructured.NestedSlice(obj.Object, "webhooks")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get webhooks")
+	}
+	nameCamel := strcase.ToLowerCamel(name)
+	caBundle := ""
+	if appMeta.Config().GenWebhookCerts {
+		caBundle = processor.CABundle(appMeta, obj)
+	}
+	for _, w := range webhooks {
+		webhook, ok := w.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if svcName, ok, _ := unstructured.NestedString(webhook, "clientConfig", "service", "name"); ok {
+			err = unstructured.SetNestedField(webhook, appMeta.TemplatedName(svcName), "clientConfig", "service", "name")
+			if err != nil {
+				return "", err
+			}
+		}
+		if svcNs, ok, _ := unstructured.NestedString(webhook, "clientConfig", "service", "namespace"); ok && svcNs != "" {
+			err = unstructured.SetNestedField(webhook, appMeta.TemplatedNamespace(svcNs), "clientConfig", "service", "namespace")
+			if err != nil {
+				return "", err
+			}
+		}
+		if caBundle != "" {
+			err = unstructured.SetNestedField(webhook, caBundlePlaceholder, "clientConfig", "caBundle")
+			if err != nil {
+				return "", err
+			}
+		}
+		webhookName, _, _ := unstructured.NestedString(webhook, "name")
+		for _, v := range webhookValueDefaults {
+			value, ok := webhook[v.field]
+			if !ok {
+				value = v.dft
+			}
+			webhook[v.field], err = values.Add(value, nameCamel, webhookName, v.field)
+			if err != nil {
+				return "", errors.Wrapf(err, "unable to process webhook %s", webhookName)
+			}
+		}
+	}
+	res, err := yamlformat.Marshal(map[string]interface{}{"webhooks": webhooks}, 0)
+	if err != nil {
+		return "", err
+	}
+	res = processor.UnquoteTemplates(res)
+	return strings.ReplaceAll(res, caBundlePlaceholder, caBundle), nil
+}
+
+// certManagerAnnotations - returns cert-manager CA injection annotation templated with chart certificate name.
+// Returns empty string if the object has no such annotation or webhook certificates are generated by Helm.
+func certManagerAnnotations(appMeta helmify.AppMetadata, obj *unstructured.Unstructured) string {
+	certName := obj.GetAnnotations()[processor.InjectCAAnnotation]
+	if certName == "" || appMeta.Config().GenWebhookCerts {
+		return ""
+	}
+	certName = strings.TrimPrefix(certName, appMeta.Namespace()+"/")
+	certName = appMeta.TrimName(certName)
+	return fmt.Sprintf(`
+  annotations:
+    cert-manager.io/inject-ca-from: %s/{{ include "%s.fullname" . }}-%s`, appMeta.TemplatedNamespace(appMeta.Namespace()), appMeta.ChartName(), certName)
+}